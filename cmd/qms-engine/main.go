@@ -1,7 +1,11 @@
 package main
 
-import "github.com/project-weekend/qms-engine/server"
+import (
+	"os"
+
+	"github.com/project-weekend/qms-engine/server"
+)
 
 func main() {
-	server.Serve()
+	os.Exit(server.Run(os.Args[1:]))
 }
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS `schema_migrations` (
+    `version`           INT UNSIGNED NOT NULL                                           COMMENT 'number prefix of the migration file',
+    `applied_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'applied time',
+
+    PRIMARY KEY (`version`)
+);
+
+-- every migration records its own version as its last statement
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (0), (1);
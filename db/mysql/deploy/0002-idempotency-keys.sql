@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+    `id`                CHAR(64) NOT NULL                                               COMMENT 'sha256 of principal and Idempotency-Key',
+    `fingerprint`       CHAR(64) NOT NULL                                               COMMENT 'sha256 of method, path and body of the first request',
+    `status_code`       SMALLINT UNSIGNED NULL DEFAULT NULL                             COMMENT 'stored response status, null while in progress',
+    `content_type`      VARCHAR(255) NOT NULL DEFAULT ''                                COMMENT 'stored response content type',
+    `body`              MEDIUMBLOB NULL                                                 COMMENT 'stored response body',
+    `expires_at`        TIMESTAMP NOT NULL                                              COMMENT 'end of the lock while in progress, of the record once completed',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+
+    PRIMARY KEY (`id`),
+    INDEX idx_expires_at (expires_at)
+);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (2);
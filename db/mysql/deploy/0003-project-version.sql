@@ -0,0 +1,4 @@
+ALTER TABLE `projects`
+    ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 1                                COMMENT 'incremented by every update, exposed as the ETag' AFTER `description`;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (3);
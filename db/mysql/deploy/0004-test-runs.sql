@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS `test_runs` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `project_id`        BIGINT UNSIGNED NOT NULL                                        COMMENT 'project the run belongs to',
+    `name`              VARCHAR(100) NOT NULL DEFAULT ''                                COMMENT 'run name, e.g. the CI build',
+    `status`            VARCHAR(10) NOT NULL DEFAULT 'open'                             COMMENT 'open while results are accepted, then closed',
+    `last_sequence`     BIGINT UNSIGNED NOT NULL DEFAULT 0                              COMMENT 'highest committed result sequence, uploads resume after it',
+    `result_count`      INT UNSIGNED NOT NULL DEFAULT 0                                 COMMENT 'number of stored results',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+    `updated_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'updated time',
+    `closed_at`         TIMESTAMP NULL DEFAULT NULL                                     COMMENT 'closed time',
+
+    PRIMARY KEY (`id`),
+    INDEX idx_project_id (project_id)
+);
+
+CREATE TABLE IF NOT EXISTS `test_results` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `run_id`            BIGINT UNSIGNED NOT NULL                                        COMMENT 'run the result belongs to',
+    `sequence`          BIGINT UNSIGNED NOT NULL                                        COMMENT 'position given by the uploader, unique in the run',
+    `case_key`          VARCHAR(255) NOT NULL                                           COMMENT 'identifier of the test case',
+    `status`            VARCHAR(10) NOT NULL                                            COMMENT 'passed, failed, skipped or blocked',
+    `duration_ms`       BIGINT UNSIGNED NOT NULL DEFAULT 0                              COMMENT 'test duration',
+    `message`           TEXT NOT NULL                                                   COMMENT 'failure message or output',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_run_sequence` (`run_id`, `sequence`)
+);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (4);
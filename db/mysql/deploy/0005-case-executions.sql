@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS `case_executions` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `run_id`            BIGINT UNSIGNED NOT NULL                                        COMMENT 'run the case is executed in',
+    `case_key`          VARCHAR(255) NOT NULL                                           COMMENT 'identifier of the test case',
+    `status`            VARCHAR(10) NOT NULL DEFAULT ''                                 COMMENT 'recorded result, empty while the case is executed',
+    `message`           TEXT NOT NULL                                                   COMMENT 'notes recorded with the result',
+    `duration_ms`       BIGINT UNSIGNED NOT NULL DEFAULT 0                              COMMENT 'execution time recorded with the result',
+    `recorded_by`       VARCHAR(100) NOT NULL DEFAULT ''                                COMMENT 'tester who recorded the result',
+    `version`           INT UNSIGNED NOT NULL DEFAULT 0                                 COMMENT 'number of recorded results, guards against overwriting unseen ones',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+    `updated_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'updated time',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_run_case` (`run_id`, `case_key`)
+);
+
+CREATE TABLE IF NOT EXISTS `case_execution_steps` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `execution_id`      BIGINT UNSIGNED NOT NULL                                        COMMENT 'execution the step belongs to',
+    `step`              INT UNSIGNED NOT NULL                                           COMMENT 'position of the step in the case, from 1',
+    `status`            VARCHAR(10) NOT NULL                                            COMMENT 'passed, failed, skipped or blocked',
+    `note`              TEXT NOT NULL                                                   COMMENT 'what the tester observed',
+    `updated_by`        VARCHAR(100) NOT NULL                                           COMMENT 'tester who recorded the step last',
+    `updated_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'updated time',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_execution_step` (`execution_id`, `step`)
+);
+
+CREATE TABLE IF NOT EXISTS `run_comments` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `run_id`            BIGINT UNSIGNED NOT NULL                                        COMMENT 'run the comment is about',
+    `case_key`          VARCHAR(255) NOT NULL DEFAULT ''                                COMMENT 'case the comment is about, empty for the run',
+    `author`            VARCHAR(100) NOT NULL                                           COMMENT 'tester who wrote the comment',
+    `body`              TEXT NOT NULL                                                   COMMENT 'comment text',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+
+    PRIMARY KEY (`id`),
+    INDEX idx_run_id (run_id)
+);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (5);
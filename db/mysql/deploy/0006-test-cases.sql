@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS `test_cases` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `project_id`        BIGINT UNSIGNED NOT NULL                                        COMMENT 'project the case belongs to',
+    `external_id`       VARCHAR(255) NOT NULL                                           COMMENT 'ID of the case where it was written, imports update the case with it',
+    `title`             VARCHAR(255) NOT NULL                                           COMMENT 'title of the case',
+    `section`           VARCHAR(255) NOT NULL DEFAULT ''                                COMMENT 'folder the case is filed in, levels separated by /',
+    `priority`          VARCHAR(20) NOT NULL DEFAULT ''                                 COMMENT 'priority as named where the case was written',
+    `preconditions`     TEXT NOT NULL                                                   COMMENT 'state the system must be in before the steps',
+    `description`       TEXT NOT NULL                                                   COMMENT 'what the case verifies',
+    `tags`              VARCHAR(1000) NOT NULL DEFAULT ''                               COMMENT 'comma-separated labels',
+    `checksum`          CHAR(64) NOT NULL                                               COMMENT 'SHA-256 of the imported content, re-imports skip unchanged cases',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+    `updated_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'updated time',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_project_external_id` (`project_id`, `external_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `test_case_steps` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `case_id`           BIGINT UNSIGNED NOT NULL                                        COMMENT 'case the step belongs to',
+    `position`          INT UNSIGNED NOT NULL                                           COMMENT 'position of the step in the case, from 1',
+    `action`            TEXT NOT NULL                                                   COMMENT 'what the tester does',
+    `expected`          TEXT NOT NULL                                                   COMMENT 'what the tester should observe',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_case_position` (`case_id`, `position`)
+);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (6);
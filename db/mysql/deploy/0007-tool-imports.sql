@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS `test_suites` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `project_id`        BIGINT UNSIGNED NOT NULL                                        COMMENT 'project the suite belongs to',
+    `name`              VARCHAR(255) NOT NULL                                           COMMENT 'suite name',
+    `description`       TEXT NOT NULL                                                   COMMENT 'what the suite covers',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+    `updated_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'updated time',
+
+    PRIMARY KEY (`id`),
+    INDEX idx_project_id (project_id)
+);
+
+ALTER TABLE `test_cases`
+    ADD COLUMN `suite_id` BIGINT UNSIGNED NULL DEFAULT NULL                             COMMENT 'suite the case belongs to, when it has one' AFTER `project_id`,
+    ADD COLUMN `custom_fields` JSON NULL                                                COMMENT 'custom fields of the tool the case was imported from, by name' AFTER `tags`;
+
+CREATE TABLE IF NOT EXISTS `import_mappings` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `project_id`        BIGINT UNSIGNED NOT NULL                                        COMMENT 'project the export was imported into',
+    `source`            VARCHAR(20) NOT NULL                                            COMMENT 'tool the export comes from: testrail, zephyr or xray',
+    `kind`              VARCHAR(10) NOT NULL                                            COMMENT 'what is mapped: suite, case or run',
+    `source_id`         VARCHAR(255) NOT NULL                                           COMMENT 'ID in the tool',
+    `target_id`         BIGINT UNSIGNED NOT NULL                                        COMMENT 'ID of the suite, case or run it was imported as',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_project_source_kind_source_id` (`project_id`, `source`, `kind`, `source_id`)
+);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (7);
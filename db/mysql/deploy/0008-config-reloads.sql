@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `config_reloads` (
+    `id`                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT                         COMMENT 'primary key',
+    `instance`          VARCHAR(255) NOT NULL DEFAULT ''                                COMMENT 'host name of the process that reloaded',
+    `trigger_source`    VARCHAR(20) NOT NULL                                            COMMENT 'what started the reload: SIGHUP or file change',
+    `outcome`           VARCHAR(12) NOT NULL                                            COMMENT 'applied, rolled_back or rejected',
+    `changes`           JSON NOT NULL                                                   COMMENT 'applied changes as key: old -> new, secrets redacted',
+    `restart_keys`      JSON NOT NULL                                                   COMMENT 'changed keys that need a restart',
+    `error`             TEXT NOT NULL                                                   COMMENT 'why the configuration was rejected or rolled back',
+    `created_at`        TIMESTAMP DEFAULT CURRENT_TIMESTAMP                             COMMENT 'created time',
+
+    PRIMARY KEY (`id`),
+    INDEX idx_created_at (created_at)
+);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (8);
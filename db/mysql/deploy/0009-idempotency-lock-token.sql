@@ -0,0 +1,4 @@
+ALTER TABLE `idempotency_keys`
+    ADD COLUMN `lock_token` CHAR(32) NOT NULL DEFAULT ''                                COMMENT 'token of the request holding the lock, only it may complete the record' AFTER `fingerprint`;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (9);
@@ -0,0 +1,4 @@
+ALTER TABLE `idempotency_keys`
+    ADD COLUMN `header` JSON NULL                                                       COMMENT 'stored response headers kept for retries, e.g. ETag and Location' AFTER `content_type`;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (10);
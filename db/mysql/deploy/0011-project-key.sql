@@ -0,0 +1,5 @@
+ALTER TABLE `projects`
+    ADD COLUMN `project_key` VARCHAR(10) NULL DEFAULT NULL                              COMMENT 'unique upper case prefix of the IDs of its test cases, e.g. QMS in QMS-42' AFTER `name`,
+    ADD UNIQUE KEY `uk_project_key` (`project_key`);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (11);
@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed deploy/*.sql
+var deployFS embed.FS
+
+// LatestVersion returns the number prefix of the newest migration in deploy/,
+// e.g. 1 for 0001-schema-migrations.sql
+func LatestVersion() (int, error) {
+	files, err := fs.Glob(deployFS, "deploy/*.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	latest := -1
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "deploy/")
+		prefix, _, _ := strings.Cut(name, "-")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has no numeric prefix: %w", name, err)
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// abortWithError stops the handler chain with err, the response is written by the error middleware
+func abortWithError(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
+	ctx.Abort()
+}
+
+// validationError converts validator errors to a BAD_REQUEST in the language of the request
+func validationError(ctx *gin.Context, err error) *common.ServiceError {
+	trans, _ := ctx.Value(common.TranslatorKey).(ut.Translator)
+	return common.NewServiceError(common.ErrCode_BadRequest, common.ParseValidationErrors(err, trans))
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+// setETag exposes the version of the returned resource as its entity tag
+func setETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion returns the version the client expects from the If-Match header, which
+// carries the ETag of its copy. Weak tags are accepted since the version covers every field.
+// If-Match: * writes the resource at whatever version it is, returned as service.AnyVersion
+func ifMatchVersion(ctx *gin.Context) (int, *common.ServiceError) {
+	ifMatch := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if ifMatch == "" {
+		return 0, common.NewServiceError(common.ErrCode_PreconditionRequired, []common.ErrorDetail{{
+			ErrorCode: "IF_MATCH_REQUIRED",
+			Message:   "send the ETag of the resource in If-Match, or * to write it at any version",
+		}})
+	}
+	if ifMatch == "*" {
+		return service.AnyVersion, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+	if err != nil || version <= 0 {
+		return 0, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_IF_MATCH",
+			Message:   "If-Match must be a single ETag returned by the API, or *",
+		}})
+	}
+	return version, nil
+}
+
+// pathID parses the :id path parameter
+func pathID(ctx *gin.Context) (int, *common.ServiceError) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		return 0, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_ID",
+			Message:   "id must be a positive integer",
+			Path:      "id",
+		}})
+	}
+	return id, nil
+}
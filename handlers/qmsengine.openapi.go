@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/openapi"
+)
+
+var (
+	ifMatchParam = &openapi.Parameter{
+		Name:        "If-Match",
+		In:          "header",
+		Required:    true,
+		Description: "ETag of the copy of the resource being changed, or * to change it at any version",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	idempotencyKeyParam = &openapi.Parameter{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Unique key of the request, retries with the same key replay the first response",
+		Schema:      &openapi.Schema{Type: "string", MaxLength: &maxIdempotencyKeyLen},
+	}
+	lastEventIDParam = &openapi.Parameter{
+		Name:        "Last-Event-ID",
+		In:          "header",
+		Description: "ID of the last event received, sent by browsers when they reconnect",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	importParams = []*openapi.Parameter{
+		{
+			Name: "format", In: "query", Required: true, Description: "Format of the body",
+			Schema: &openapi.Schema{Type: "string", Enum: []any{"csv", "xlsx"}},
+		},
+		{
+			Name: "dryRun", In: "query", Description: "Report the invalid rows and what applying would do, writing nothing",
+			Schema: &openapi.Schema{Type: "boolean"},
+		},
+		{
+			Name: "columns", In: "query", Style: "deepObject", Explode: true,
+			Description: "Header of the column holding a case field, e.g. columns[title]=Summary. Fields left out " +
+				"are found under a header matching their name: externalId, title, section, priority, " +
+				"preconditions, description, tags, steps and expected",
+			Schema: &openapi.Schema{Type: "object", AdditionalProperties: &openapi.Schema{Type: "string"}},
+		},
+	}
+	importProjectParams = []*openapi.Parameter{
+		{
+			Name: "source", In: "query", Required: true, Description: "Tool the export comes from",
+			Schema: &openapi.Schema{Type: "string", Enum: []any{"testrail", "zephyr", "xray"}},
+		},
+		{
+			Name: "format", In: "query",
+			Description: "Format of the body: xml or csv for TestRail, json for Zephyr Scale and Xray. " +
+				"Defaults to xml for TestRail and json otherwise",
+			Schema: &openapi.Schema{Type: "string", Enum: []any{"xml", "csv", "json"}},
+		},
+		{
+			Name: "projectId", In: "query", Description: "Project to import into, name creates one instead",
+			Schema: &openapi.Schema{Type: "integer", ExclusiveMinimum: &zero},
+		},
+		{
+			Name: "name", In: "query",
+			Description: "Name of the project the import creates, case folded like the names of created projects",
+			Schema:      &openapi.Schema{Type: "string", MinLength: &minProjectNameLen, MaxLength: &maxProjectNameLen},
+		},
+		{
+			Name: "description", In: "query", Description: "Description of the project the import creates",
+			Schema: &openapi.Schema{Type: "string", MaxLength: &maxProjectDescriptionLen},
+		},
+		{
+			Name: "dryRun", In: "query", Description: "Report what the import would do and roll it back",
+			Schema: &openapi.Schema{Type: "boolean"},
+		},
+	}
+	listRunsParams = []*openapi.Parameter{
+		{
+			Name: "status", In: "query", Description: "Status of the runs listed, all of them when left out",
+			Schema: &openapi.Schema{Type: "string", Enum: []any{"open", "closed"}},
+		},
+		{
+			Name: "limit", In: "query", Description: "How many runs are listed, 20 when left out",
+			Schema: &openapi.Schema{Type: "integer", Minimum: &zero, Maximum: &maxRunListLimit},
+		},
+	}
+	gateParams = []*openapi.Parameter{
+		{
+			Name: "minPassRate", In: "query",
+			Description: "Lowest percentage of passed results among the passed, failed and blocked ones, " +
+				"the configured one when left out",
+			Schema: &openapi.Schema{Type: "number", Minimum: &zero, Maximum: &maxPassRate},
+		},
+		{
+			Name: "maxFailed", In: "query", Description: "Most failed and blocked results, the configured bound when left out",
+			Schema: &openapi.Schema{Type: "integer", Minimum: &zero},
+		},
+	}
+	etagHeader = map[string]string{"ETag": "Version of the returned resource, send it back in If-Match"}
+
+	maxIdempotencyKeyLen = 255
+	// The lengths of project names and descriptions, as validated by CreateProjectRequest
+	minProjectNameLen        = 5
+	maxProjectNameLen        = 50
+	maxProjectDescriptionLen = 250
+	zero                     = 0.0
+	// The bounds of ListTestRunsRequest and EvaluateGateRequest
+	maxRunListLimit = 100.0
+	maxPassRate     = 100.0
+)
+
+// APIRoutes describes the routes registered by RegisterRoutes, they make up the OpenAPI document.
+// The tests fail when the two drift apart
+func APIRoutes() []openapi.Route {
+	return slices.Concat(projectRoutes(), testRunRoutes(), testCaseRoutes(), streamRoutes())
+}
+
+func projectRoutes() []openapi.Route {
+	return []openapi.Route{
+		{
+			Method: http.MethodPost, Path: "/api/v1/project", OperationID: "createProject",
+			Summary: "Create a project", Tag: "projects",
+			Params:  []*openapi.Parameter{idempotencyKeyParam},
+			Request: model.CreateProjectRequest{}, Status: http.StatusOK,
+			Response: model.CreateProjectResponse{}, ResponseHeaders: etagHeader,
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_Conflict,
+				common.ErrCode_PayloadTooLarge, common.ErrCode_Unprocessable},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/v1/project/:id", OperationID: "getProject",
+			Summary: "Get a project", Tag: "projects",
+			Status: http.StatusOK, Response: model.ProjectResponse{}, ResponseHeaders: etagHeader,
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound},
+		},
+		{
+			Method: http.MethodPatch, Path: "/api/v1/project/:id", OperationID: "updateProject",
+			Summary: "Update the fields of a project that are set", Tag: "projects",
+			Params:  []*openapi.Parameter{ifMatchParam, idempotencyKeyParam},
+			Request: model.UpdateProjectRequest{}, Status: http.StatusOK,
+			Response: model.ProjectResponse{}, ResponseHeaders: etagHeader,
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound,
+				common.ErrCode_Conflict, common.ErrCode_PreconditionFailed, common.ErrCode_PreconditionRequired,
+				common.ErrCode_PayloadTooLarge, common.ErrCode_Unprocessable},
+		},
+		{
+			Method: http.MethodDelete, Path: "/api/v1/project/:id", OperationID: "deleteProject",
+			Summary: "Delete a project", Tag: "projects",
+			Params: []*openapi.Parameter{ifMatchParam}, Status: http.StatusNoContent,
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound,
+				common.ErrCode_PreconditionFailed, common.ErrCode_PreconditionRequired},
+		},
+		{
+			Method: http.MethodPost, Path: "/api/v1/project/import", OperationID: "importProject",
+			Summary: "Import a TestRail, Zephyr Scale or Xray export into a project", Tag: "projects",
+			Description: "Suites, cases with their steps and custom fields, and runs with their results are " +
+				"imported in one transaction, into the project projectId or into a project created under name. " +
+				"Items imported before are found by their ID in the tool: changed suites and cases are updated " +
+				"and runs are kept as they are, so sending an export again does not duplicate it. Invalid " +
+				"cases are reported with paths like cases[3].title, their position in the export, and answer " +
+				"422 with nothing written. The fields of the export left out are listed under unmapped.",
+			Params: importProjectParams, RequestContentTypes: []string{XMLContentType, CSVContentType, JSONContentType},
+			Status: http.StatusOK, Response: model.ImportProjectResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound,
+				common.ErrCode_Conflict, common.ErrCode_PayloadTooLarge, common.ErrCode_Unprocessable},
+		},
+	}
+}
+
+func testRunRoutes() []openapi.Route {
+	return []openapi.Route{
+		{
+			Method: http.MethodPost, Path: "/api/v1/project/:id/run", OperationID: "createTestRun",
+			Summary: "Open a test run of a project", Tag: "runs",
+			Params:  []*openapi.Parameter{idempotencyKeyParam},
+			Request: model.CreateTestRunRequest{}, Status: http.StatusOK, Response: model.TestRunResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound,
+				common.ErrCode_PayloadTooLarge, common.ErrCode_Unprocessable},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/v1/project/:id/runs", OperationID: "listTestRuns",
+			Summary: "List the newest test runs of a project", Tag: "runs",
+			Params: listRunsParams, Status: http.StatusOK, Response: model.ListTestRunsResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/v1/run/:id", OperationID: "getTestRun",
+			Summary: "Get a test run", Tag: "runs",
+			Status: http.StatusOK, Response: model.TestRunResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/v1/run/:id/gate", OperationID: "evaluateGate",
+			Summary: "Evaluate the quality gate of a test run", Tag: "runs",
+			Description: "The run passes when its pass rate, passed results among the passed, failed and " +
+				"blocked ones, reaches minPassRate and at most maxFailed results failed or are blocked. " +
+				"Skipped results are left out, a run without other results does not pass. reasons lists " +
+				"the thresholds that were missed.",
+			Params: gateParams, Status: http.StatusOK, Response: model.GateResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound},
+		},
+		{
+			Method: http.MethodPost, Path: "/api/v1/run/:id/close", OperationID: "closeTestRun",
+			Summary: "Close a test run to further results", Tag: "runs",
+			Params: []*openapi.Parameter{idempotencyKeyParam}, Status: http.StatusOK, Response: model.TestRunResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound},
+		},
+		{
+			Method: http.MethodPost, Path: "/api/v1/run/:id/results", OperationID: "uploadResults",
+			Summary: "Stream results into an open test run", Tag: "runs",
+			Description: "The body holds one result per line. A progress line is streamed back after every " +
+				"committed batch and a last one with done set, carrying the error when the upload failed " +
+				"after progress was sent. An upload that was cut off resumes after the lastSequence of the run. " +
+				"Sequences must increase through the upload, a sequence that does not answers 422 " +
+				"SEQUENCE_NOT_INCREASING. Results whose sequence is already stored are dropped as duplicates.",
+			ContentType: NDJSONContentType, Request: model.TestResultRequest{},
+			Status: http.StatusOK, Response: model.UploadResultsResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound,
+				common.ErrCode_PayloadTooLarge, common.ErrCode_Unprocessable},
+		},
+	}
+}
+
+func testCaseRoutes() []openapi.Route {
+	return []openapi.Route{
+		{
+			Method: http.MethodPost, Path: "/api/v1/project/:id/cases/import", OperationID: "importCases",
+			Summary: "Import the test cases of a CSV file or Excel workbook", Tag: "cases",
+			Description: "Every row below the header is a case, the steps and expected columns hold one step " +
+				"per line. Cases are matched by external ID: new ones are created, changed ones updated and " +
+				"the others left alone, so an import that failed part way is resumed by sending it again. " +
+				"Invalid rows are reported with paths like rows[12].title, the row number in the sheet. " +
+				"Nothing is written while a row is invalid, which answers 422 with the row errors.",
+			Params: importParams, RequestContentTypes: []string{CSVContentType, XLSXContentType},
+			Status: http.StatusOK, Response: model.ImportCasesResponse{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound,
+				common.ErrCode_Conflict, common.ErrCode_PayloadTooLarge, common.ErrCode_Unprocessable},
+		},
+	}
+}
+
+// streamRoutes hold their connection open, the contract middleware does not check them
+func streamRoutes() []openapi.Route {
+	return []openapi.Route{
+		{
+			Method: http.MethodGet, Path: "/api/v1/runs/:id/events", OperationID: "streamRunEvents",
+			Summary: "Follow the changes of a test run", Tag: "runs",
+			Description: "Server-sent events: result-recorded after every stored batch of results, described " +
+				"below, and run-status with the test run when it is opened or closed. A client reconnecting " +
+				"with Last-Event-ID gets the events it missed. When they are no longer kept, or without " +
+				"Last-Event-ID, the stream starts with a run-status event holding the current run.",
+			Params:      []*openapi.Parameter{lastEventIDParam},
+			ContentType: EventStreamContentType, Status: http.StatusOK, Response: model.ResultRecordedEvent{},
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_ResourceNotFound},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/v1/runs/:id/session", OperationID: "joinRunSession",
+			Summary: "Execute the cases of a test run together with other testers", Tag: "runs",
+			Description: "WebSocket carrying JSON messages {type, ref, data}, the ref of a request is echoed " +
+				"on its answer. The tester is the authenticated caller, named by its principal, e.g. key:<name>. " +
+				"The session starts with welcome holding the session ID, the testers present " +
+				"and the run with its case executions and latest comments. Testers send start {caseKey, force} " +
+				"to take the soft lock on a case, answered with locked or lock-held, and release. They record " +
+				"step {caseKey, step, status, note}, result {caseKey, status, message, durationMs, version} " +
+				"and comment {caseKey, body}, answered with ack, error, or conflict when a result was " +
+				"recorded since the version the tester saw. Conflicts carry the current result to record " +
+				"over or keep. Others get joined, left, locked, unlocked, step-updated, result-recorded " +
+				"and comment-added updates.",
+			Status: http.StatusSwitchingProtocols,
+			Errors: []common.ErrorCode{common.ErrCode_BadRequest, common.ErrCode_Unauthorized, common.ErrCode_Forbidden,
+				common.ErrCode_ResourceNotFound, common.ErrCode_Unprocessable, common.ErrCode_ServiceUnavailable},
+		},
+	}
+}
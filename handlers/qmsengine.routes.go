@@ -4,9 +4,42 @@ import "github.com/gin-gonic/gin"
 
 type RouteConfig struct {
 	AppEngine *gin.Engine
+	// Limits returns the rate and body size limit middleware of a route group
+	Limits func(group string) gin.HandlerFunc
+	// Contract checks requests and responses against the OpenAPI document when enabled
+	Contract gin.HandlerFunc
+	// Idempotency replays the stored response of retried POST and PATCH requests
+	Idempotency gin.HandlerFunc
 	*QMSEngineService
 }
 
 func (r *RouteConfig) RegisterRoutes() {
-	r.AppEngine.POST("/api/v1/project", r.CreateProject)
+	api := r.AppEngine.Group("/api/v1", r.Limits("default"), r.Contract, r.Idempotency)
+	api.POST("/project", r.CreateProject)
+	api.GET("/project/:id", r.GetProject)
+	api.PATCH("/project/:id", r.UpdateProject)
+	api.DELETE("/project/:id", r.DeleteProject)
+	api.POST("/project/:id/run", r.CreateTestRun)
+	api.GET("/project/:id/runs", r.ListTestRuns)
+	api.GET("/run/:id", r.GetTestRun)
+	api.GET("/run/:id/gate", r.EvaluateGate)
+	api.POST("/run/:id/close", r.CloseTestRun)
+
+	// Uploads stream their body and response, which the contract and idempotency
+	// middleware would buffer. A resumed upload skips the results already stored instead
+	uploads := r.AppEngine.Group("/api/v1", r.Limits("uploads"))
+	uploads.POST("/run/:id/results", r.UploadResults)
+
+	// Imports are read whole before they are parsed, their group caps the body.
+	// They are idempotent by the external IDs of the cases
+	imports := r.AppEngine.Group("/api/v1", r.Limits("imports"))
+	imports.POST("/project/:id/cases/import", r.ImportCases)
+	// and imports of exports by the IDs of their items in the tool
+	imports.POST("/project/import", r.ImportProject)
+
+	// Event streams and sessions stay open for as long as the client follows them, which the
+	// contract middleware would wait for
+	streams := r.AppEngine.Group("/api/v1", r.Limits("default"))
+	streams.GET("/runs/:id/events", r.StreamRunEvents)
+	streams.GET("/runs/:id/session", r.JoinRunSession)
 }
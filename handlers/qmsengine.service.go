@@ -5,7 +5,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 
-	"github.com/project-weekend/qms-engine/internal/service/project"
+	"github.com/project-weekend/qms-engine/internal/service"
 )
 
 const logTag = "handlers"
@@ -14,13 +14,24 @@ const logTag = "handlers"
 type QMSEngineService struct {
 	Logger         *slog.Logger
 	Validator      *validator.Validate
-	ProjectService *project.ProjectServiceImpl
+	ProjectService service.IProjectService
+	TestRunService service.ITestRunService
+	RunEvents      service.IRunEventService
+	RunSessions    service.IRunSessionService
+	// TestCaseService imports test cases
+	TestCaseService service.ITestCaseService
 }
 
-func NewQMSEngineService(logger *slog.Logger, validator *validator.Validate, projectService *project.ProjectServiceImpl) *QMSEngineService {
+func NewQMSEngineService(logger *slog.Logger, validator *validator.Validate, projectService service.IProjectService,
+	testRunService service.ITestRunService, runEvents service.IRunEventService,
+	runSessions service.IRunSessionService, testCaseService service.ITestCaseService) *QMSEngineService {
 	return &QMSEngineService{
-		Logger:         logger,
-		Validator:      validator,
-		ProjectService: projectService,
+		Logger:          logger,
+		Validator:       validator,
+		ProjectService:  projectService,
+		TestRunService:  testRunService,
+		RunEvents:       runEvents,
+		RunSessions:     runSessions,
+		TestCaseService: testCaseService,
 	}
 }
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CloseTestRun handles closing a test run to further results
+func (s *QMSEngineService) CloseTestRun(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	runResponse, err := s.TestRunService.CloseTestRun(ctx, id)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "CloseTestRun error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runResponse)
+}
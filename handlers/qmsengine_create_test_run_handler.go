@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// CreateTestRun handles opening a test run of a project
+func (s *QMSEngineService) CreateTestRun(ctx *gin.Context) {
+	projectID, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	request := new(model.CreateTestRunRequest)
+	if err := s.bindRequest(ctx, request); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	runResponse, err := s.TestRunService.CreateTestRun(ctx, projectID, request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "CreateTestRun error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runResponse)
+}
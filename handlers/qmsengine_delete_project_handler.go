@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteProject handles project deletion, guarded by If-Match
+func (s *QMSEngineService) DeleteProject(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+	version, serviceErr := ifMatchVersion(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	if err := s.ProjectService.DeleteProject(ctx, id, version); err != nil {
+		s.Logger.ErrorContext(ctx, "DeleteProject error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
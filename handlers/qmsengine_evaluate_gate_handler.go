@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// EvaluateGate handles evaluating the quality gate of a run, the thresholds are query parameters
+func (s *QMSEngineService) EvaluateGate(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	var request model.EvaluateGateRequest
+	if err := s.bindQuery(ctx, &request); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	response, err := s.TestRunService.EvaluateGate(ctx, id, &request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "EvaluateGate error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
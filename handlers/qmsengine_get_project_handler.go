@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProject handles project retrieval
+func (s *QMSEngineService) GetProject(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	projectResponse, err := s.ProjectService.GetProject(ctx, id)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "GetProject error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	setETag(ctx, projectResponse.Version)
+	ctx.JSON(http.StatusOK, projectResponse)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTestRun handles test run retrieval
+func (s *QMSEngineService) GetTestRun(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	runResponse, err := s.TestRunService.GetTestRun(ctx, id)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "GetTestRun error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runResponse)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+const (
+	CSVContentType  = "text/csv"
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ImportCases handles importing the test cases of a CSV or XLSX body into a project. The format,
+// dry run and column mapping are query parameters, e.g. ?format=csv&columns[title]=Summary
+func (s *QMSEngineService) ImportCases(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	// Form binding takes maps as JSON, columns[field] parameters are read apart
+	request := model.ImportCasesRequest{Columns: ctx.QueryMap("columns")}
+	if err := s.bindQuery(ctx, &request); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	// Workbooks are read whole anyway
+	sheet, err := s.readBody(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	trans, _ := ctx.Value(common.TranslatorKey).(ut.Translator)
+	response, err := s.TestCaseService.ImportCases(ctx, id, &request, bytes.NewReader(sheet), trans)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "ImportCases error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+const (
+	XMLContentType  = "application/xml"
+	JSONContentType = "application/json"
+)
+
+// ImportProject handles importing a TestRail, Zephyr Scale or Xray export into a project. The
+// tool, format and target project are query parameters, e.g. ?source=testrail&format=xml&projectId=3
+func (s *QMSEngineService) ImportProject(ctx *gin.Context) {
+	request := new(model.ImportProjectRequest)
+	if err := s.bindQuery(ctx, request); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	// Exports are parsed whole
+	export, err := s.readBody(ctx)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	trans, _ := ctx.Value(common.TranslatorKey).(ut.Translator)
+	response, err := s.ProjectService.ImportProject(ctx, request, bytes.NewReader(export), trans)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "ImportProject error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// JoinRunSession handles joining the session in which testers execute the cases of a run
+// together. The tester is the authenticated caller, so what they record cannot be attributed
+// to someone else. The request is upgraded to a WebSocket, errors after that are sent over it
+func (s *QMSEngineService) JoinRunSession(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	tester := common.Principal(ctx)
+	if tester == "" {
+		abortWithError(ctx, common.NewServiceError(common.ErrCode_Unauthorized, []common.ErrorDetail{{
+			ErrorCode: "AUTHENTICATION_REQUIRED",
+			Message:   "an API key or a client certificate is required",
+		}}))
+		return
+	}
+
+	trans, _ := ctx.Value(common.TranslatorKey).(ut.Translator)
+	if err := s.RunSessions.Join(ctx, ctx.Writer, ctx.Request, id, tester, trans); err != nil {
+		s.Logger.ErrorContext(ctx, "JoinRunSession error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// ListTestRuns handles listing the runs of a project, filtered by the status and limit query parameters
+func (s *QMSEngineService) ListTestRuns(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	var request model.ListTestRunsRequest
+	if err := s.bindQuery(ctx, &request); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	response, err := s.TestRunService.ListTestRuns(ctx, id, &request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "ListTestRuns error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
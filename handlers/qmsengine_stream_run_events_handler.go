@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/events"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+const (
+	// EventStreamContentType is the media type of server-sent event streams
+	EventStreamContentType = "text/event-stream"
+	// eventHeartbeat keeps proxies from cutting streams without events
+	eventHeartbeat = 15 * time.Second
+	// eventRetry is how long browsers wait before reconnecting a dropped stream
+	eventRetry = 3 * time.Second
+)
+
+// StreamRunEvents handles following the events of a test run as server-sent events. A client
+// reconnecting with Last-Event-ID gets the events it missed. When they are no longer kept, or
+// without Last-Event-ID, the stream starts with a run-status event holding the current run
+func (s *QMSEngineService) StreamRunEvents(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+	lastEventID, serviceErr := lastEventID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	// Subscribing before reading the run means no change falls in between
+	subscription := s.RunEvents.Subscribe(id, lastEventID)
+	defer subscription.Close()
+	runResponse, err := s.TestRunService.GetTestRun(ctx, id)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "StreamRunEvents error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	// The stream outlasts the server write timeout
+	_ = http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+	ctx.Header("Content-Type", sse.ContentType)
+	ctx.Header("Cache-Control", "no-cache")
+	// Keeps nginx from buffering the stream
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	if !subscription.Resumed {
+		err = sse.Encode(ctx.Writer, sse.Event{
+			Event: model.RunEventStatus,
+			Retry: uint(eventRetry.Milliseconds()),
+			Data:  runResponse,
+		})
+	}
+	for _, event := range subscription.Backlog {
+		if err == nil {
+			err = writeEvent(ctx.Writer, event)
+		}
+	}
+	if err == nil {
+		s.followEvents(ctx, subscription)
+	}
+}
+
+// followEvents writes the events of subscription as they come until the client goes away
+// or the subscription ends
+func (s *QMSEngineService) followEvents(ctx *gin.Context, subscription *events.Subscription) {
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		ctx.Writer.Flush()
+
+		var err error
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			err = writeEvent(ctx.Writer, event)
+		case <-heartbeat.C:
+			// A comment line, ignored by clients
+			_, err = io.WriteString(ctx.Writer, ":\n\n")
+		}
+		if err != nil {
+			s.Logger.DebugContext(ctx, "Run event stream ended", "tag", logTag, "error", err)
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event events.Event) error {
+	return sse.Encode(w, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: event.Type,
+		Data:  []byte(event.Data),
+	})
+}
+
+// lastEventID returns the Last-Event-ID header browsers send when they reconnect, zero without it
+func lastEventID(ctx *gin.Context) (int64, *common.ServiceError) {
+	header := ctx.GetHeader("Last-Event-ID")
+	if header == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_LAST_EVENT_ID",
+			Message:   "Last-Event-ID must be the ID of an event of the stream",
+			Path:      "Last-Event-ID",
+		}})
+	}
+	return id, nil
+}
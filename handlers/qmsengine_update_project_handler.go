@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// UpdateProject handles partial project updates, guarded by If-Match
+func (s *QMSEngineService) UpdateProject(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+	version, serviceErr := ifMatchVersion(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	request := new(model.UpdateProjectRequest)
+	if err := s.bindRequest(ctx, request); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	projectResponse, err := s.ProjectService.UpdateProject(ctx, id, version, request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "UpdateProject error", "tag", logTag, "error", err)
+		abortWithError(ctx, err)
+		return
+	}
+
+	setETag(ctx, projectResponse.Version)
+	ctx.JSON(http.StatusOK, projectResponse)
+}
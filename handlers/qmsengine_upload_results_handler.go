@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+const (
+	// NDJSONContentType is the media type of upload bodies and of their progress responses
+	NDJSONContentType = "application/x-ndjson"
+	// maxResultLineSize bounds one line of an upload, the body as a whole is unbounded
+	maxResultLineSize = 1 << 20
+)
+
+// UploadResults handles streaming results into an open test run. The body holds one result per
+// line and is read while progress lines are written back, one per committed batch and a last one
+// with done set. Errors found before the first progress line get a regular error response
+func (s *QMSEngineService) UploadResults(ctx *gin.Context) {
+	id, serviceErr := pathID(ctx)
+	if serviceErr != nil {
+		abortWithError(ctx, serviceErr)
+		return
+	}
+
+	// Uploads outlast the server timeouts, and HTTP/1 needs full duplex to answer mid-body.
+	// Writers that support neither, e.g. in tests, still get the response at the end
+	controller := http.NewResponseController(ctx.Writer)
+	_ = controller.EnableFullDuplex()
+	_ = controller.SetReadDeadline(time.Time{})
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	scanner := bufio.NewScanner(ctx.Request.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxResultLineSize)
+	stream := &ndjsonResults{service: s, ctx: ctx, scanner: scanner}
+
+	progress, err := s.TestRunService.UploadResults(ctx, id, stream)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "UploadResults error", "tag", logTag, "error", err,
+			"runId", id, "ackedSequence", progress.AckedSequence)
+		if !stream.started {
+			abortWithError(ctx, err)
+			return
+		}
+		progress.Done = true
+		progress.Error = common.AsServiceError(err)
+	}
+	_ = stream.Ack(progress)
+}
+
+// ndjsonResults reads the results of an NDJSON body and writes the progress lines
+type ndjsonResults struct {
+	service *QMSEngineService
+	ctx     *gin.Context
+	scanner *bufio.Scanner
+	line    int
+	started bool
+}
+
+// Next returns the result on the next non-blank line
+func (r *ndjsonResults) Next() (*model.TestResultRequest, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		result := new(model.TestResultRequest)
+		if err := json.Unmarshal(line, result); err != nil {
+			return nil, r.lineError(common.ErrCode_BadRequest, "INVALID_JSON", "the line is not a JSON result")
+		}
+		if err := normalize.Struct(result); err != nil {
+			return nil, r.lineError(common.ErrCode_BadRequest, "INVALID_RESULT", "the result cannot be normalized")
+		}
+		if err := r.service.Validator.Struct(result); err != nil {
+			validationErr := validationError(r.ctx, err)
+			for i := range validationErr.Errors {
+				validationErr.Errors[i].Meta = map[string]any{"line": r.line}
+			}
+			return nil, validationErr
+		}
+		return result, nil
+	}
+
+	err := r.scanner.Err()
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case err == nil:
+		return nil, io.EOF
+	case errors.Is(err, bufio.ErrTooLong):
+		return nil, r.lineError(common.ErrCode_PayloadTooLarge, "LINE_TOO_LONG", "the line exceeds 1 MiB")
+	case errors.As(err, &maxBytesErr):
+		return nil, common.NewServiceError(common.ErrCode_PayloadTooLarge, nil)
+	default:
+		r.service.Logger.WarnContext(r.ctx, "Failed to read upload body", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+}
+
+// Ack writes a progress line, starting the response on the first one
+func (r *ndjsonResults) Ack(progress *model.UploadResultsResponse) error {
+	if !r.started {
+		r.started = true
+		r.ctx.Header("Content-Type", NDJSONContentType)
+		r.ctx.Status(http.StatusOK)
+	}
+	encoded, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	if _, err = r.ctx.Writer.Write(append(encoded, '\n')); err != nil {
+		return err
+	}
+	r.ctx.Writer.Flush()
+	return nil
+}
+
+func (r *ndjsonResults) lineError(code common.ErrorCode, errorCode, message string) error {
+	return common.NewServiceError(code, []common.ErrorDetail{{
+		ErrorCode: errorCode,
+		Message:   message,
+		Meta:      map[string]any{"line": r.line},
+	}})
+}
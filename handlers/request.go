@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+// bindRequest binds the body into request, normalizes it and validates the normalized values
+func (s *QMSEngineService) bindRequest(ctx *gin.Context, request any) error {
+	return s.bind(ctx, request, ctx.ShouldBind)
+}
+
+// bindQuery is bindRequest for the query parameters of requests whose body is not a form
+func (s *QMSEngineService) bindQuery(ctx *gin.Context, request any) error {
+	return s.bind(ctx, request, ctx.ShouldBindQuery)
+}
+
+func (s *QMSEngineService) bind(ctx *gin.Context, request any, shouldBind func(any) error) error {
+	if err := shouldBind(request); err != nil {
+		s.Logger.ErrorContext(ctx, "Failed to parse request body", "tag", logTag, "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return common.NewServiceError(common.ErrCode_PayloadTooLarge, nil)
+		}
+		return common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	if err := normalize.Struct(request); err != nil {
+		s.Logger.ErrorContext(ctx, "Failed to normalize request", "tag", logTag, "error", err)
+		return common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		s.Logger.ErrorContext(ctx, "Validation error", "tag", logTag, "error", err)
+		return validationError(ctx, err)
+	}
+	return nil
+}
+
+// maxReadBodySize bounds the bodies read whole, even when their route group sets no smaller limit
+const maxReadBodySize = 32 << 20
+
+// readBody reads the whole body of an upload. Reading it first tells a body over the limit from
+// a malformed one, which the parsers could not
+func (s *QMSEngineService) readBody(ctx *gin.Context) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxReadBodySize))
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "Failed to read the uploaded body", "tag", logTag, "error", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, common.NewServiceError(common.ErrCode_PayloadTooLarge, nil)
+		}
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+	return body, nil
+}
@@ -0,0 +1,170 @@
+package collab
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/events"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+const logTag = "collab"
+
+// Hub serves the WebSocket sessions in which testers execute the cases of a run together.
+// Testers see who executes which case, soft locks keep two of them from starting the same case
+// unknowingly, and the steps, results and comments they record reach the others as they are stored
+type Hub struct {
+	bus         *events.Bus
+	executions  service.IExecutionService
+	validator   *validator.Validate
+	logger      *slog.Logger
+	checkOrigin func(r *http.Request) bool
+	upgrader    websocket.Upgrader
+	maxSessions int
+	// maxMessageSize is the largest message a tester may send, in bytes
+	maxMessageSize int64
+	now            func() time.Time
+
+	mu     sync.Mutex
+	rooms  map[int]*room
+	closed bool
+}
+
+// NewHub creates a hub spreading session changes over bus, which must not carry other events.
+// checkOrigin tells the browser origins allowed to connect, maxSessions bounds the sessions of a
+// run on this instance and maxMessageSize the messages of testers
+func NewHub(bus *events.Bus, executions service.IExecutionService, validator *validator.Validate,
+	checkOrigin func(r *http.Request) bool, maxSessions int, maxMessageSize int64, logger *slog.Logger) *Hub {
+	return &Hub{
+		bus:         bus,
+		executions:  executions,
+		validator:   validator,
+		logger:      logger,
+		checkOrigin: checkOrigin,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  int(min(maxMessageSize, 64*1024)),
+			WriteBufferSize: 64 * 1024,
+			// Join checks the origin before upgrading, to answer with a service error
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		maxSessions:    maxSessions,
+		maxMessageSize: maxMessageSize,
+		now:            time.Now,
+		rooms:          make(map[int]*room),
+	}
+}
+
+// Join upgrades the request to the WebSocket of tester in the session of a run and serves it
+// until it closes. It returns an error when the request cannot be upgraded, once upgraded errors
+// go to the tester, localized with trans
+func (h *Hub) Join(ctx context.Context, w http.ResponseWriter, r *http.Request, runID int, tester string,
+	trans ut.Translator) error {
+	if !websocket.IsWebSocketUpgrade(r) {
+		return common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "WEBSOCKET_REQUIRED",
+			Message:   "the session is a WebSocket, the request must ask to upgrade to it",
+		}})
+	}
+	if !h.checkOrigin(r) {
+		return common.NewServiceError(common.ErrCode_Forbidden, []common.ErrorDetail{{
+			ErrorCode: "ORIGIN_NOT_ALLOWED",
+			Message:   "the origin of the page is not allowed to join sessions",
+		}})
+	}
+
+	s := newSession(h, tester, trans)
+	// Entering first means no change falls between the snapshot and the session
+	room, testers, err := h.enter(runID, s)
+	if err != nil {
+		return err
+	}
+	snapshot, err := h.executions.GetRunSession(ctx, runID)
+	if err != nil {
+		h.exit(room, s)
+		return err
+	}
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has answered the request
+		h.logger.WarnContext(ctx, "Failed to upgrade to a session WebSocket", "tag", logTag, "error", err)
+		h.exit(room, s)
+		return nil
+	}
+
+	s.serve(ctx, conn, room, &welcome{SessionID: s.id, Testers: testers, RunSessionSnapshot: snapshot})
+	return nil
+}
+
+// Close ends every session, testers reconnect to another instance
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, room := range h.rooms {
+		room.mu.Lock()
+		for _, s := range room.sessions {
+			s.close(websocket.CloseGoingAway, "the server is shutting down")
+		}
+		room.mu.Unlock()
+	}
+}
+
+func (h *Hub) isClosed() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.closed
+}
+
+// enter adds s to the room of the run, starting the room when it is the first session
+func (h *Hub) enter(runID int, s *session) (*room, []Tester, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, nil, common.NewServiceError(common.ErrCode_ServiceUnavailable, nil)
+	}
+	room, ok := h.rooms[runID]
+	if !ok {
+		room = newRoom(h, runID)
+		h.rooms[runID] = room
+		go room.run(h.bus.Subscribe(runID, 0))
+	}
+
+	testers, ok := room.enter(s)
+	if !ok {
+		return nil, nil, common.NewServiceError(common.ErrCode_Unprocessable, []common.ErrorDetail{{
+			ErrorCode: "SESSION_FULL",
+			Message:   "the session of the run has as many testers as it can take",
+		}})
+	}
+	return room, testers, nil
+}
+
+// exit removes s from its room, stopping the room when it was the last session
+func (h *Hub) exit(room *room, s *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if room.exit(s) && h.rooms[room.runID] == room {
+		delete(h.rooms, room.runID)
+		close(room.done)
+	}
+}
+
+// newSessionID returns a random ID telling apart the sessions of all instances
+func newSessionID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
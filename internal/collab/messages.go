@@ -0,0 +1,93 @@
+package collab
+
+import (
+	"encoding/json"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// Request types, sent by testers as {"type", "ref", "data"}. The ref is echoed on the message
+// answering the request
+const (
+	// TypeStart takes the soft lock on the case in a model.StartCaseRequest, answered with
+	// locked or lock-held. A tester executes one case at a time, starting one releases the last
+	TypeStart = "start"
+	// TypeRelease releases the case being executed, answered with unlocked
+	TypeRelease = "release"
+	// TypeStep records a model.RecordStepRequest, TypeResult a model.RecordCaseResultRequest and
+	// TypeComment a model.AddCommentRequest. They are answered with ack, conflict or error
+	TypeStep    = "step"
+	TypeResult  = "result"
+	TypeComment = "comment"
+)
+
+// Message types, sent to testers as {"type", "ref", "data"}
+const (
+	// TypeWelcome starts a session with its ID, the testers present and the run
+	TypeWelcome = "welcome"
+	// TypeAck carries what a request stored, the other testers get it as an update
+	TypeAck      = "ack"
+	TypeConflict = "conflict"
+	TypeError    = "error"
+	// TypeLockHeld refuses a start, its tester is the one executing the case
+	TypeLockHeld = "lock-held"
+
+	// Updates, their data is an update
+	TypeJoined         = "joined"
+	TypeLeft           = "left"
+	TypeLocked         = "locked"
+	TypeUnlocked       = "unlocked"
+	TypeStepUpdated    = "step-updated"
+	TypeResultRecorded = "result-recorded"
+	TypeCommentAdded   = "comment-added"
+
+	// typeHere tells the other instances a tester is still connected, it is not sent to testers
+	typeHere = "here"
+)
+
+// request is a message from a tester
+type request struct {
+	Type string          `json:"type"`
+	Ref  string          `json:"ref"`
+	Data json.RawMessage `json:"data"`
+}
+
+// message is a message to a tester
+type message struct {
+	Type string `json:"type"`
+	Ref  string `json:"ref,omitempty"`
+	Data any    `json:"data,omitempty"`
+}
+
+// Tester is a tester in the session, CaseKey is the case they execute and hold the soft lock on
+type Tester struct {
+	SessionID string `json:"sessionId"`
+	Name      string `json:"name"`
+	CaseKey   string `json:"caseKey,omitempty"`
+}
+
+// welcome is the data of the first message of a session. Updates sent right after it may
+// repeat what it holds, comments carry their ID to tell
+type welcome struct {
+	SessionID string   `json:"sessionId"`
+	Testers   []Tester `json:"testers"`
+	*model.RunSessionSnapshot
+}
+
+// update tells testers what another one did
+type update struct {
+	Tester Tester `json:"tester"`
+	// TakenFrom is the tester a forced start took the case from
+	TakenFrom *Tester `json:"takenFrom,omitempty"`
+	// Data is the stored step, result or comment
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// change is what a session did, published to the rooms of the run on every instance
+type change struct {
+	Tester Tester `json:"tester"`
+	Force  bool   `json:"force,omitempty"`
+	// Ref is the ref of the request, echoed to the session that sent it
+	Ref  string          `json:"ref,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
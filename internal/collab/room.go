@@ -0,0 +1,289 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/project-weekend/qms-engine/internal/events"
+)
+
+const (
+	// hereInterval is how often an instance tells the others which of its testers are connected
+	hereInterval = 30 * time.Second
+	// hereExpiry is how long a tester of another instance stays present without being announced,
+	// after it the instance is taken for gone and the cases of the tester are released
+	hereExpiry = 3 * hereInterval
+)
+
+// room is the session of a run on this instance. Every room of the run applies the changes of
+// all instances in the order of the bus, so the testers and locks agree between instances
+type room struct {
+	hub   *Hub
+	runID int
+	done  chan struct{}
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	testers  map[string]*presence
+	// locks maps a case key to the session executing it
+	locks map[string]string
+	// lastID is the ID of the last change applied, the bus resumes after it
+	lastID int64
+}
+
+type presence struct {
+	Tester
+	seen time.Time
+}
+
+func newRoom(hub *Hub, runID int) *room {
+	return &room{
+		hub:      hub,
+		runID:    runID,
+		done:     make(chan struct{}),
+		sessions: make(map[string]*session),
+		testers:  make(map[string]*presence),
+		locks:    make(map[string]string),
+	}
+}
+
+// run applies the changes of subscription until the room is closed
+func (r *room) run(subscription *events.Subscription) {
+	heartbeat := time.NewTicker(hereInterval)
+	defer heartbeat.Stop()
+	defer func() {
+		// Nil once the hub closed while the room followed the bus again
+		if subscription != nil {
+			subscription.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case event, ok := <-subscription.Events:
+			if ok {
+				r.apply(event)
+				continue
+			}
+			if subscription = r.resubscribe(); subscription == nil {
+				return
+			}
+		case <-heartbeat.C:
+			r.announce()
+			r.expire()
+		}
+	}
+}
+
+// resubscribe follows the bus again after the room fell behind or the broker reconnected. When
+// changes were lost the testers of other instances are forgotten until they are announced again
+func (r *room) resubscribe() *events.Subscription {
+	if r.hub.isClosed() {
+		return nil
+	}
+	r.mu.Lock()
+	lastID := r.lastID
+	r.mu.Unlock()
+
+	subscription := r.hub.bus.Subscribe(r.runID, lastID)
+	if subscription.Resumed {
+		for _, event := range subscription.Backlog {
+			r.apply(event)
+		}
+		return subscription
+	}
+
+	r.mu.Lock()
+	for _, p := range r.testers {
+		if _, local := r.sessions[p.SessionID]; !local {
+			r.drop(p)
+		}
+	}
+	r.mu.Unlock()
+	r.announce()
+	return subscription
+}
+
+// announce publishes the testers connected to this instance along with their cases
+func (r *room) announce() {
+	r.mu.Lock()
+	testers := make([]Tester, 0, len(r.sessions))
+	for id := range r.sessions {
+		if p, ok := r.testers[id]; ok {
+			testers = append(testers, p.Tester)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, tester := range testers {
+		r.publish(context.Background(), typeHere, &change{Tester: tester})
+	}
+}
+
+// expire drops the testers of other instances that were not announced for hereExpiry
+func (r *room) expire() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := r.hub.now().Add(-hereExpiry)
+	for _, p := range r.testers {
+		if _, local := r.sessions[p.SessionID]; !local && p.seen.Before(cutoff) {
+			r.drop(p)
+		}
+	}
+}
+
+func (r *room) publish(ctx context.Context, changeType string, c *change) {
+	r.hub.bus.Publish(ctx, r.runID, changeType, c)
+}
+
+// apply updates the room with a change and tells the testers of this instance
+func (r *room) apply(event events.Event) {
+	var c change
+	if err := json.Unmarshal(event.Data, &c); err != nil {
+		r.hub.logger.Warn("Dropped a malformed session change", "tag", logTag, "error", err, "runId", r.runID)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID = event.ID
+	switch event.Type {
+	case TypeJoined, typeHere:
+		r.arrive(&c)
+	case TypeLeft:
+		if p, ok := r.testers[c.Tester.SessionID]; ok {
+			r.drop(p)
+		}
+	case TypeLocked:
+		r.lock(&c)
+	case TypeUnlocked:
+		r.unlock(&c)
+	default:
+		// The tester who stored it got an ack
+		r.broadcast(event.Type, &c, &update{Tester: c.Tester, Data: c.Data}, true)
+	}
+}
+
+// arrive adds a tester that joined or was announced. An announced case the tester lost track
+// of, because changes were lost, is taken again when it is free
+func (r *room) arrive(c *change) {
+	p := r.presence(c)
+	p.seen = r.hub.now()
+	caseKey := c.Tester.CaseKey
+	if caseKey != "" && p.CaseKey == "" && r.locks[caseKey] == "" {
+		r.take(p, caseKey)
+		r.broadcast(TypeLocked, c, &update{Tester: p.Tester}, false)
+	}
+}
+
+// presence returns the tester of c, adding them when they are new
+func (r *room) presence(c *change) *presence {
+	p, ok := r.testers[c.Tester.SessionID]
+	if !ok {
+		p = &presence{Tester: Tester{SessionID: c.Tester.SessionID, Name: c.Tester.Name}, seen: r.hub.now()}
+		r.testers[p.SessionID] = p
+		r.broadcast(TypeJoined, c, &update{Tester: p.Tester}, false)
+	}
+	return p
+}
+
+// lock gives the tester of c the case, unless another tester holds it and c does not force it
+func (r *room) lock(c *change) {
+	p := r.presence(c)
+	caseKey := c.Tester.CaseKey
+	holder, held := r.testers[r.locks[caseKey]]
+	if held && holder != p && !c.Force {
+		if s, local := r.sessions[p.SessionID]; local {
+			s.queue(message{Type: TypeLockHeld, Ref: c.Ref, Data: &update{Tester: holder.Tester}})
+		}
+		return
+	}
+
+	u := &update{}
+	if held && holder != p {
+		takenFrom := holder.Tester
+		u.TakenFrom = &takenFrom
+		holder.CaseKey = ""
+	}
+	r.take(p, caseKey)
+	u.Tester = p.Tester
+	r.broadcast(TypeLocked, c, u, false)
+}
+
+// unlock releases the case of the tester of c
+func (r *room) unlock(c *change) {
+	p, ok := r.testers[c.Tester.SessionID]
+	if !ok || p.CaseKey == "" {
+		return
+	}
+	released := p.Tester
+	r.release(p)
+	r.broadcast(TypeUnlocked, c, &update{Tester: released}, false)
+}
+
+// take gives p the case, releasing the one p executed before
+func (r *room) take(p *presence, caseKey string) {
+	r.release(p)
+	r.locks[caseKey] = p.SessionID
+	p.CaseKey = caseKey
+}
+
+func (r *room) release(p *presence) {
+	if p.CaseKey != "" && r.locks[p.CaseKey] == p.SessionID {
+		delete(r.locks, p.CaseKey)
+	}
+	p.CaseKey = ""
+}
+
+// drop removes a tester who left, releasing their case
+func (r *room) drop(p *presence) {
+	r.release(p)
+	delete(r.testers, p.SessionID)
+	r.broadcast(TypeLeft, &change{Tester: p.Tester}, &update{Tester: p.Tester}, false)
+}
+
+// broadcast sends an update to the testers of this instance. The session that made the change
+// gets its ref back, or nothing when skipOrigin is set
+func (r *room) broadcast(messageType string, c *change, u *update, skipOrigin bool) {
+	for id, s := range r.sessions {
+		msg := message{Type: messageType, Data: u}
+		if id == c.Tester.SessionID {
+			if skipOrigin {
+				continue
+			}
+			msg.Ref = c.Ref
+		}
+		s.queue(msg)
+	}
+}
+
+// enter adds s and returns the testers present, it fails when the room is full
+func (r *room) enter(s *session) ([]Tester, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.sessions) >= r.hub.maxSessions {
+		return nil, false
+	}
+	r.sessions[s.id] = s
+	testers := make([]Tester, 0, len(r.testers))
+	for _, p := range r.testers {
+		testers = append(testers, p.Tester)
+	}
+	return testers, true
+}
+
+// exit removes s and reports whether the room is left without sessions. The tester stays
+// present until the left change is applied
+func (r *room) exit(s *session) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, s.id)
+	return len(r.sessions) == 0
+}
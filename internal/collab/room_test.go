@@ -0,0 +1,245 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/events"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+const testRunID = 7
+
+// clock is the time of the hubs under test, shared with their rooms
+type clock struct {
+	mu sync.Mutex
+	at time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.at
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.at = c.at.Add(d)
+}
+
+// versionedResults records case results at increasing versions, a result sent at another
+// version than the current one is a RESULT_CONFLICT as in the execution service
+type versionedResults struct {
+	service.IExecutionService
+	mu       sync.Mutex
+	versions map[string]int
+}
+
+func (v *versionedResults) RecordCaseResult(_ context.Context, runID int, tester string,
+	request *model.RecordCaseResultRequest) (*model.CaseExecutionResponse, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	current := v.versions[request.CaseKey]
+	if request.Version != current {
+		return nil, common.NewServiceError(common.ErrCode_Conflict, []common.ErrorDetail{{
+			ErrorCode: "RESULT_CONFLICT",
+			Path:      "version",
+			Meta:      map[string]any{"currentVersion": current},
+		}})
+	}
+	v.versions[request.CaseKey] = current + 1
+	return &model.CaseExecutionResponse{RunID: runID, CaseKey: request.CaseKey, Status: request.Status,
+		RecordedBy: tester, Version: current + 1}, nil
+}
+
+// newInstances creates the session hubs of two instances, sharing one in-memory bus
+func newInstances(t *testing.T) (*Hub, *Hub, *clock) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	eventHub := events.NewHub(100, sendBuffer)
+	bus := events.NewBus(eventHub, events.NewMemoryBroker(eventHub), logger)
+	validate := validator.New()
+	if err := validate.RegisterValidation("no_control_chars", func(fl validator.FieldLevel) bool {
+		return !strings.ContainsFunc(fl.Field().String(), unicode.IsControl)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &clock{at: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)}
+	executions := &versionedResults{versions: map[string]int{}}
+	allow := func(*http.Request) bool { return true }
+	a := NewHub(bus, executions, validate, allow, 10, 4096, logger)
+	b := NewHub(bus, executions, validate, allow, 10, 4096, logger)
+	a.now, b.now = c.now, c.now
+	t.Cleanup(func() {
+		// The rooms stop once their hub is closed and the bus ends their subscriptions
+		a.Close()
+		b.Close()
+		eventHub.Close()
+	})
+	return a, b, c
+}
+
+// join enters tester into the session of the run on h as Join does, without a WebSocket
+func join(t *testing.T, h *Hub, tester string) (*session, *room) {
+	t.Helper()
+	s := newSession(h, tester, nil)
+	room, _, err := h.enter(testRunID, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	room.publish(context.Background(), TypeJoined, &change{Tester: Tester{SessionID: s.id, Name: tester}})
+	return s, room
+}
+
+// leave ends the session as serve does once the WebSocket closes
+func leave(h *Hub, room *room, s *session) {
+	s.close(websocket.CloseNormalClosure, "")
+	room.publish(context.Background(), TypeLeft, &change{Tester: Tester{SessionID: s.id, Name: s.name}})
+	h.exit(room, s)
+}
+
+// send handles a request of the tester of s
+func send(t *testing.T, s *session, room *room, requestType, ref string, data any) {
+	t.Helper()
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.handle(context.Background(), room, &request{Type: requestType, Ref: ref, Data: encoded})
+}
+
+// expect reads the next message of s, which must be of messageType and carry ref
+func expect(t *testing.T, s *session, messageType, ref string) message {
+	t.Helper()
+	select {
+	case msg := <-s.send:
+		if msg.Type != messageType || msg.Ref != ref {
+			t.Fatalf("%s got %s with ref %q, want %s with ref %q", s.name, msg.Type, msg.Ref, messageType, ref)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s got no message, want %s", s.name, messageType)
+		return message{}
+	}
+}
+
+// expectUpdate reads the next message of s as expect does and checks the tester of its update
+func expectUpdate(t *testing.T, s *session, messageType, ref string, tester *session, caseKey string) *update {
+	t.Helper()
+	u, ok := expect(t, s, messageType, ref).Data.(*update)
+	if !ok || u.Tester.SessionID != tester.id || u.Tester.CaseKey != caseKey {
+		t.Fatalf("%s got %s about %+v, want %s with case %q", s.name, messageType, u, tester.name, caseKey)
+	}
+	return u
+}
+
+// joinBoth has ana join on the first instance and budi on the second, each seeing budi join
+func joinBoth(t *testing.T, a, b *Hub) (ana *session, roomA *room, budi *session, roomB *room) {
+	t.Helper()
+	ana, roomA = join(t, a, "ana")
+	expectUpdate(t, ana, TypeJoined, "", ana, "")
+	budi, roomB = join(t, b, "budi")
+	expectUpdate(t, ana, TypeJoined, "", budi, "")
+	expectUpdate(t, budi, TypeJoined, "", budi, "")
+	return ana, roomA, budi, roomB
+}
+
+func TestLockContention(t *testing.T) {
+	a, b, _ := newInstances(t)
+	ana, roomA, budi, roomB := joinBoth(t, a, b)
+
+	send(t, ana, roomA, TypeStart, "1", model.StartCaseRequest{CaseKey: "C-1"})
+	expectUpdate(t, ana, TypeLocked, "1", ana, "C-1")
+	expectUpdate(t, budi, TypeJoined, "", ana, "")
+	expectUpdate(t, budi, TypeLocked, "", ana, "C-1")
+
+	// The other instance agrees the case is held, only the tester who asked hears of it
+	send(t, budi, roomB, TypeStart, "2", model.StartCaseRequest{CaseKey: "C-1"})
+	expectUpdate(t, budi, TypeLockHeld, "2", ana, "C-1")
+
+	send(t, budi, roomB, TypeStart, "3", model.StartCaseRequest{CaseKey: "C-1", Force: true})
+	for _, u := range []*update{
+		expectUpdate(t, budi, TypeLocked, "3", budi, "C-1"),
+		expectUpdate(t, ana, TypeLocked, "", budi, "C-1"),
+	} {
+		if u.TakenFrom == nil || u.TakenFrom.SessionID != ana.id {
+			t.Errorf("taken from %+v, want ana", u.TakenFrom)
+		}
+	}
+}
+
+func TestLeftReleasesTheCase(t *testing.T) {
+	a, b, _ := newInstances(t)
+	ana, roomA, budi, roomB := joinBoth(t, a, b)
+	send(t, ana, roomA, TypeStart, "1", model.StartCaseRequest{CaseKey: "C-1"})
+	expectUpdate(t, budi, TypeJoined, "", ana, "")
+	expectUpdate(t, budi, TypeLocked, "", ana, "C-1")
+
+	leave(a, roomA, ana)
+	expectUpdate(t, budi, TypeLeft, "", ana, "")
+	send(t, budi, roomB, TypeStart, "2", model.StartCaseRequest{CaseKey: "C-1"})
+	if u := expectUpdate(t, budi, TypeLocked, "2", budi, "C-1"); u.TakenFrom != nil {
+		t.Errorf("taken from %+v, want a free case", u.TakenFrom)
+	}
+}
+
+// TestHereExpiry keeps a tester of another instance while it is announced and drops them,
+// releasing their case, once it is not for hereExpiry
+func TestHereExpiry(t *testing.T) {
+	a, b, c := newInstances(t)
+	ana, roomA, budi, roomB := joinBoth(t, a, b)
+	send(t, budi, roomB, TypeStart, "1", model.StartCaseRequest{CaseKey: "C-1"})
+	expectUpdate(t, ana, TypeLocked, "", budi, "C-1")
+
+	c.advance(hereExpiry - time.Second)
+	roomA.expire()
+	roomB.announce()
+	// The start comes back after the announcement, no left came before it
+	send(t, ana, roomA, TypeStart, "2", model.StartCaseRequest{CaseKey: "C-2"})
+	expectUpdate(t, ana, TypeLocked, "2", ana, "C-2")
+
+	c.advance(hereExpiry - time.Second)
+	roomA.expire()
+	send(t, ana, roomA, TypeStart, "3", model.StartCaseRequest{CaseKey: "C-3"})
+	expectUpdate(t, ana, TypeLocked, "3", ana, "C-3")
+
+	c.advance(2 * time.Second)
+	roomA.expire()
+	expectUpdate(t, ana, TypeLeft, "", budi, "")
+	send(t, ana, roomA, TypeStart, "4", model.StartCaseRequest{CaseKey: "C-1"})
+	if u := expectUpdate(t, ana, TypeLocked, "4", ana, "C-1"); u.TakenFrom != nil {
+		t.Errorf("taken from %+v, want a free case", u.TakenFrom)
+	}
+}
+
+func TestResultConflict(t *testing.T) {
+	a, b, _ := newInstances(t)
+	ana, roomA, budi, roomB := joinBoth(t, a, b)
+
+	send(t, budi, roomB, TypeResult, "1", model.RecordCaseResultRequest{CaseKey: "C-1", Status: "passed"})
+	expect(t, budi, TypeAck, "1")
+	expectUpdate(t, ana, TypeResultRecorded, "", budi, "")
+
+	// Ana records at the version she saw before budi's result
+	send(t, ana, roomA, TypeResult, "2", model.RecordCaseResultRequest{CaseKey: "C-1", Status: "failed"})
+	serviceErr, ok := expect(t, ana, TypeConflict, "2").Data.(*common.ServiceError)
+	if !ok || len(serviceErr.Errors) != 1 || serviceErr.Errors[0].ErrorCode != "RESULT_CONFLICT" ||
+		serviceErr.Errors[0].Meta["currentVersion"] != 1 {
+		t.Errorf("conflict = %+v, want RESULT_CONFLICT at version 1", serviceErr)
+	}
+}
@@ -0,0 +1,246 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gorilla/websocket"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+const (
+	// sendBuffer is how many messages wait for a slow tester before the session is closed
+	sendBuffer = 64
+	// writeWait bounds writing a message
+	writeWait = 10 * time.Second
+	// pongWait is how long a session lives without hearing from the tester
+	pongWait = 60 * time.Second
+	// pingInterval keeps the connection alive, it must be shorter than pongWait
+	pingInterval = pongWait / 2
+)
+
+// session is the WebSocket of a tester in a room
+type session struct {
+	id    string
+	name  string
+	hub   *Hub
+	trans ut.Translator
+	send  chan message
+	done  chan struct{}
+
+	once        sync.Once
+	closeCode   int
+	closeReason string
+}
+
+func newSession(hub *Hub, name string, trans ut.Translator) *session {
+	return &session{
+		id:    newSessionID(),
+		name:  name,
+		hub:   hub,
+		trans: trans,
+		send:  make(chan message, sendBuffer),
+		done:  make(chan struct{}),
+	}
+}
+
+// serve runs the session on conn until it closes, starting with welcome. The tester joins the
+// room on every instance and leaves it when the session ends
+func (s *session) serve(ctx context.Context, conn *websocket.Conn, room *room, welcome *welcome) {
+	written := make(chan struct{})
+	go s.writeLoop(conn, message{Type: TypeWelcome, Data: welcome}, written)
+
+	room.publish(ctx, TypeJoined, &change{Tester: Tester{SessionID: s.id, Name: s.name}})
+	s.readLoop(ctx, conn, room)
+
+	s.close(websocket.CloseNormalClosure, "")
+	room.publish(context.WithoutCancel(ctx), TypeLeft, &change{Tester: Tester{SessionID: s.id, Name: s.name}})
+	s.hub.exit(room, s)
+	<-written
+}
+
+// queue sends msg to the tester without waiting. A tester too slow to take it is disconnected,
+// since the messages it missed cannot be replayed
+func (s *session) queue(msg message) {
+	select {
+	case s.send <- msg:
+	case <-s.done:
+	default:
+		s.close(websocket.CloseTryAgainLater, "the session fell behind, join it again")
+	}
+}
+
+// close ends the session with a close frame carrying code and reason
+func (s *session) close(code int, reason string) {
+	s.once.Do(func() {
+		s.closeCode = code
+		s.closeReason = reason
+		close(s.done)
+	})
+}
+
+// writeLoop writes first and the queued messages until the session closes
+func (s *session) writeLoop(conn *websocket.Conn, first message, written chan struct{}) {
+	defer close(written)
+	defer conn.Close()
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	err := s.write(conn, first)
+	for err == nil {
+		select {
+		case msg := <-s.send:
+			err = s.write(conn, msg)
+		case <-ping.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+		case <-s.done:
+			closeMessage := websocket.FormatCloseMessage(s.closeCode, s.closeReason)
+			_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(writeWait))
+			return
+		}
+	}
+	s.hub.logger.Debug("Session write failed", "tag", logTag, "error", err, "sessionId", s.id)
+	s.close(websocket.CloseAbnormalClosure, "")
+}
+
+func (s *session) write(conn *websocket.Conn, msg message) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(msg)
+}
+
+// readLoop handles the requests of the tester until the connection closes
+func (s *session) readLoop(ctx context.Context, conn *websocket.Conn, room *room) {
+	conn.SetReadLimit(s.hub.maxMessageSize)
+	alive := func(string) error { return conn.SetReadDeadline(time.Now().Add(pongWait)) }
+	conn.SetPongHandler(alive)
+
+	for {
+		if err := alive(""); err != nil {
+			return
+		}
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.hub.logger.DebugContext(ctx, "Session read failed", "tag", logTag, "error", err, "sessionId", s.id)
+			}
+			return
+		}
+
+		var req request
+		if err := json.Unmarshal(data, &req); err != nil {
+			s.fail("", common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+				ErrorCode: "INVALID_MESSAGE",
+				Message:   "messages must be JSON objects with a type, a ref and data",
+			}}))
+			continue
+		}
+		s.handle(ctx, room, &req)
+	}
+}
+
+// handle serves a request. Locks are changed by the room once the change comes back from the bus,
+// so every instance orders them the same. What the service stores is acked right away
+func (s *session) handle(ctx context.Context, room *room, req *request) {
+	tester := Tester{SessionID: s.id, Name: s.name}
+	switch req.Type {
+	case TypeStart:
+		var body model.StartCaseRequest
+		if s.decode(req, &body) {
+			tester.CaseKey = body.CaseKey
+			room.publish(ctx, TypeLocked, &change{Tester: tester, Force: body.Force, Ref: req.Ref})
+		}
+	case TypeRelease:
+		room.publish(ctx, TypeUnlocked, &change{Tester: tester, Ref: req.Ref})
+	case TypeStep:
+		var body model.RecordStepRequest
+		if s.decode(req, &body) {
+			s.record(ctx, room, req.Ref, TypeStepUpdated, func() (any, error) {
+				return s.hub.executions.RecordStep(ctx, room.runID, s.name, &body)
+			})
+		}
+	case TypeResult:
+		var body model.RecordCaseResultRequest
+		if s.decode(req, &body) {
+			s.record(ctx, room, req.Ref, TypeResultRecorded, func() (any, error) {
+				return s.hub.executions.RecordCaseResult(ctx, room.runID, s.name, &body)
+			})
+		}
+	case TypeComment:
+		var body model.AddCommentRequest
+		if s.decode(req, &body) {
+			s.record(ctx, room, req.Ref, TypeCommentAdded, func() (any, error) {
+				return s.hub.executions.AddComment(ctx, room.runID, s.name, &body)
+			})
+		}
+	default:
+		s.fail(req.Ref, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "UNKNOWN_MESSAGE_TYPE",
+			Message:   "the message type is not one of start, release, step, result or comment",
+			Path:      "type",
+		}}))
+	}
+}
+
+// record acks what store stored and publishes it to the other testers as an update of updateType
+func (s *session) record(ctx context.Context, room *room, ref string, updateType string, store func() (any, error)) {
+	stored, err := store()
+	if err != nil {
+		s.fail(ref, err)
+		return
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		s.fail(ref, err)
+		return
+	}
+	s.queue(message{Type: TypeAck, Ref: ref, Data: json.RawMessage(data)})
+	room.publish(ctx, updateType, &change{Tester: Tester{SessionID: s.id, Name: s.name}, Data: data})
+}
+
+// decode reads the data of req into body the way the transports bind requests, telling the
+// tester when it is invalid
+func (s *session) decode(req *request, body any) bool {
+	if err := json.Unmarshal(req.Data, body); err != nil {
+		s.fail(req.Ref, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_MESSAGE",
+			Message:   "the data of the message does not match its type",
+			Path:      "data",
+		}}))
+		return false
+	}
+	if err := normalize.Struct(body); err != nil {
+		s.fail(req.Ref, common.NewServiceError(common.ErrCode_BadRequest, nil))
+		return false
+	}
+	if err := s.hub.validator.Struct(body); err != nil {
+		s.fail(req.Ref, common.NewServiceError(common.ErrCode_BadRequest, common.ParseValidationErrors(err, s.trans)))
+		return false
+	}
+	return true
+}
+
+// fail sends err to the tester. A result recorded over another one is a conflict, its details
+// carry the current result to resolve it with
+func (s *session) fail(ref string, err error) {
+	serviceErr := common.AsServiceError(err)
+	if serviceErr.Code == string(common.ErrCode_InternalServerError) {
+		s.hub.logger.Error("Session request error", "tag", logTag, "error", err, "sessionId", s.id)
+	}
+	if s.trans != nil {
+		serviceErr = serviceErr.Localized(s.trans.Locale())
+	}
+
+	messageType := TypeError
+	if serviceErr.Code == string(common.ErrCode_Conflict) {
+		messageType = TypeConflict
+	}
+	s.queue(message{Type: messageType, Ref: ref, Data: serviceErr})
+}
@@ -2,27 +2,45 @@ package common
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
+	"strings"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
 type ErrorCode string
 
 const (
-	ErrCode_BadRequest          ErrorCode = "BAD_REQUEST"
-	ErrCode_Forbidden           ErrorCode = "FORBIDDEN"
-	ErrCode_ResourceNotFound    ErrorCode = "RESOURCE_NOT_FOUND"
-	ErrCode_InternalServerError ErrorCode = "INTERNAL_SERVER_ERROR"
-	ErrCode_Unregistered        ErrorCode = "UNREGISTERED_ERRCODE"
+	ErrCode_BadRequest           ErrorCode = "BAD_REQUEST"
+	ErrCode_Unauthorized         ErrorCode = "UNAUTHORIZED"
+	ErrCode_Forbidden            ErrorCode = "FORBIDDEN"
+	ErrCode_ResourceNotFound     ErrorCode = "RESOURCE_NOT_FOUND"
+	ErrCode_Conflict             ErrorCode = "CONFLICT"
+	ErrCode_PreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	ErrCode_PreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+	ErrCode_PayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrCode_Unprocessable        ErrorCode = "UNPROCESSABLE"
+	ErrCode_TooManyRequests      ErrorCode = "TOO_MANY_REQUESTS"
+	ErrCode_InternalServerError  ErrorCode = "INTERNAL_SERVER_ERROR"
+	ErrCode_ServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrCode_Unregistered         ErrorCode = "UNREGISTERED_ERRCODE"
 )
 
 var ErrorMappings = map[ErrorCode]ErrorMapping{
-	ErrCode_BadRequest:          {HTTPCode: http.StatusBadRequest, Message: "request has invalid parameter(s) or header(s)."},
-	ErrCode_Forbidden:           {HTTPCode: http.StatusForbidden, Message: "the operation is forbidden."},
-	ErrCode_ResourceNotFound:    {HTTPCode: http.StatusNotFound, Message: "resource not found."},
-	ErrCode_InternalServerError: {HTTPCode: http.StatusInternalServerError, Message: "There is a problem on our end. Please try again later."},
+	ErrCode_BadRequest:           {HTTPCode: http.StatusBadRequest, Message: "request has invalid parameter(s) or header(s)."},
+	ErrCode_Unauthorized:         {HTTPCode: http.StatusUnauthorized, Message: "authentication is required."},
+	ErrCode_Forbidden:            {HTTPCode: http.StatusForbidden, Message: "the operation is forbidden."},
+	ErrCode_ResourceNotFound:     {HTTPCode: http.StatusNotFound, Message: "resource not found."},
+	ErrCode_Conflict:             {HTTPCode: http.StatusConflict, Message: "the request conflicts with the current state of the resource."},
+	ErrCode_PreconditionFailed:   {HTTPCode: http.StatusPreconditionFailed, Message: "the resource was modified, reload it and retry."},
+	ErrCode_PreconditionRequired: {HTTPCode: http.StatusPreconditionRequired, Message: "the If-Match header is required."},
+	ErrCode_PayloadTooLarge:      {HTTPCode: http.StatusRequestEntityTooLarge, Message: "request body is too large."},
+	ErrCode_Unprocessable:        {HTTPCode: http.StatusUnprocessableEntity, Message: "the request is well-formed but cannot be processed."},
+	ErrCode_TooManyRequests:      {HTTPCode: http.StatusTooManyRequests, Message: "too many requests, retry later."},
+	ErrCode_InternalServerError:  {HTTPCode: http.StatusInternalServerError, Message: "There is a problem on our end. Please try again later."},
+	ErrCode_ServiceUnavailable:   {HTTPCode: http.StatusServiceUnavailable, Message: "the service is temporarily unavailable, retry later."},
+	ErrCode_Unregistered:         {HTTPCode: http.StatusInternalServerError, Message: "There is a problem on our end. Please try again later."},
 }
 
 type ErrorMapping struct {
@@ -35,20 +53,33 @@ type ServiceError struct {
 	Code       string        `json:"code,omitempty"`
 	Message    string        `json:"message,omitempty"`
 	Errors     []ErrorDetail `json:"error,omitempty"`
+	// cause is the error that was wrapped, it is logged but never sent to clients
+	cause error
 }
 
 // Error implements the error interface
 func (e ServiceError) Error() string {
-	if e.Message != "" {
-		return e.Message
+	message := e.Message
+	if message == "" {
+		message = e.Code
 	}
-	return e.Code
+	if e.cause != nil {
+		return message + ": " + e.cause.Error()
+	}
+	return message
+}
+
+// Unwrap returns the wrapped error
+func (e ServiceError) Unwrap() error {
+	return e.cause
 }
 
 type ErrorDetail struct {
 	ErrorCode string `json:"errorCode,omitempty"`
 	Message   string `json:"message,omitempty"`
 	Path      string `json:"path,omitempty"`
+	// Meta carries machine-readable context, e.g. the current version of a resource
+	Meta map[string]any `json:"meta,omitempty"`
 }
 
 func NewServiceError(errCode ErrorCode, errDetails []ErrorDetail) *ServiceError {
@@ -65,16 +96,23 @@ func NewServiceError(errCode ErrorCode, errDetails []ErrorDetail) *ServiceError
 	}
 }
 
-// ParseValidationErrors converts validator.ValidationErrors to []ErrorDetail
-func ParseValidationErrors(err error) []ErrorDetail {
+// ParseValidationErrors converts validator.ValidationErrors to []ErrorDetail. Paths use the json
+// field names and messages are translated with trans, or left in English when it is nil
+func ParseValidationErrors(err error, trans ut.Translator) []ErrorDetail {
 	var errorDetails []ErrorDetail
 	var validationErrs validator.ValidationErrors
 	if errors.As(err, &validationErrs) {
 		for _, fieldErr := range validationErrs {
+			message := fieldErr.Error()
+			if trans != nil {
+				message = fieldErr.Translate(trans)
+			}
+			// Drop the name of the request struct, e.g. CreateProjectRequest.name becomes name
+			_, path, _ := strings.Cut(fieldErr.Namespace(), ".")
 			errorDetails = append(errorDetails, ErrorDetail{
 				ErrorCode: "VALIDATION_ERROR",
-				Message:   fmt.Sprintf("Field '%s' failed validation: %s", fieldErr.Field(), fieldErr.Tag()),
-				Path:      fieldErr.Namespace(),
+				Message:   message,
+				Path:      path,
 			})
 		}
 	}
@@ -82,10 +120,42 @@ func ParseValidationErrors(err error) []ErrorDetail {
 	return errorDetails
 }
 
+// AsServiceError returns the ServiceError in err's chain. Any other error becomes an
+// INTERNAL_SERVER_ERROR wrapping it, so the cause is logged but not leaked. It returns nil for a nil err
 func AsServiceError(err error) *ServiceError {
+	if err == nil {
+		return nil
+	}
 	var serviceErr *ServiceError
 	if errors.As(err, &serviceErr) {
 		return serviceErr
 	}
-	return nil
+	serviceErr = NewServiceError(ErrCode_InternalServerError, nil)
+	serviceErr.cause = err
+	return serviceErr
+}
+
+// Problem is the RFC 7807 representation of a ServiceError, with the code and
+// details as extension members
+type Problem struct {
+	Type     string        `json:"type"`
+	Title    string        `json:"title"`
+	Status   int           `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Instance string        `json:"instance,omitempty"`
+	Code     string        `json:"code"`
+	Errors   []ErrorDetail `json:"errors,omitempty"`
+}
+
+// Problem converts the error to an RFC 7807 problem about instance, the request path
+func (e ServiceError) Problem(instance string) *Problem {
+	return &Problem{
+		Type:     "urn:qms-engine:error:" + strings.ToLower(e.Code),
+		Title:    http.StatusText(e.HTTPStatus),
+		Status:   e.HTTPStatus,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Errors:   e.Errors,
+	}
 }
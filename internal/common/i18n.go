@@ -0,0 +1,40 @@
+package common
+
+const (
+	LocaleEnglish    = "en"
+	LocaleIndonesian = "id"
+	// DefaultLocale is used when Accept-Language names no supported locale
+	DefaultLocale = LocaleEnglish
+
+	// LocaleKey holds the locale of the request in the gin context
+	LocaleKey = "locale"
+	// TranslatorKey holds the ut.Translator of the request locale in the gin context
+	TranslatorKey = "translator"
+)
+
+// localizedErrorMessages translates the ErrorMappings messages, English is ErrorMappings itself
+var localizedErrorMessages = map[string]map[ErrorCode]string{
+	LocaleIndonesian: {
+		ErrCode_BadRequest:           "permintaan memiliki parameter atau header yang tidak valid.",
+		ErrCode_Unauthorized:         "autentikasi diperlukan.",
+		ErrCode_Forbidden:            "operasi ini tidak diizinkan.",
+		ErrCode_ResourceNotFound:     "sumber daya tidak ditemukan.",
+		ErrCode_Conflict:             "permintaan bertentangan dengan kondisi sumber daya saat ini.",
+		ErrCode_PreconditionFailed:   "sumber daya telah diubah, muat ulang lalu coba lagi.",
+		ErrCode_PreconditionRequired: "header If-Match wajib diisi.",
+		ErrCode_PayloadTooLarge:      "isi permintaan terlalu besar.",
+		ErrCode_Unprocessable:        "format permintaan benar tetapi tidak dapat diproses.",
+		ErrCode_TooManyRequests:      "terlalu banyak permintaan, coba lagi nanti.",
+		ErrCode_InternalServerError:  "Terjadi masalah di sisi kami. Silakan coba lagi nanti.",
+		ErrCode_ServiceUnavailable:   "layanan sedang tidak tersedia, coba lagi nanti.",
+		ErrCode_Unregistered:         "Terjadi masalah di sisi kami. Silakan coba lagi nanti.",
+	},
+}
+
+// Localized returns a copy of the error with its message in locale, when a translation exists
+func (e ServiceError) Localized(locale string) *ServiceError {
+	if message, ok := localizedErrorMessages[locale][ErrorCode(e.Code)]; ok {
+		e.Message = message
+	}
+	return &e
+}
@@ -0,0 +1,14 @@
+package common
+
+import "context"
+
+// PrincipalKey holds the authenticated caller in the gin context: key:<name> for an API key,
+// cert:<common name> for a verified client certificate
+const PrincipalKey = "principal"
+
+// Principal returns the authenticated caller of a request, empty for anonymous ones.
+// A gin context exposes its keys as values
+func Principal(ctx context.Context) string {
+	principal, _ := ctx.Value(PrincipalKey).(string)
+	return principal
+}
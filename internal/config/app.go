@@ -6,9 +6,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
+	"google.golang.org/grpc"
+
 	"github.com/project-weekend/qms-engine/handlers"
+	"github.com/project-weekend/qms-engine/internal/grpcapi"
 	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+	"github.com/project-weekend/qms-engine/internal/service/dashboard"
+	"github.com/project-weekend/qms-engine/internal/service/execution"
 	"github.com/project-weekend/qms-engine/internal/service/project"
+	"github.com/project-weekend/qms-engine/internal/service/testrun"
+	qmsenginev1 "github.com/project-weekend/qms-engine/pkg/pb/qmsengine/v1"
 	"github.com/project-weekend/qms-engine/server/config"
 )
 
@@ -18,22 +25,73 @@ type AppBootstrap struct {
 	DB        *sqlx.DB
 	Validate  *validator.Validate
 	AppEngine *gin.Engine
+	Lifecycle *Lifecycle
+	// LiveConfig is the configuration as changed by reloads, Config is the one at startup
+	LiveConfig *LiveConfig
+	// GRPCServer serves the gRPC API, nil when it is disabled
+	GRPCServer *grpc.Server
 }
 
-func Bootstrap(app *AppBootstrap) {
+// Bootstrap wires the repositories, services and transports, it fails when a route cannot be served
+func Bootstrap(app *AppBootstrap) error {
 	// setup repository
 	projectRepository := mysql.NewProjectRepository(app.Logger)
+	testRunRepository := mysql.NewTestRunRepository(app.Logger)
+	testResultRepository := mysql.NewTestResultRepository(app.Logger)
+	caseExecutionRepository := mysql.NewCaseExecutionRepository(app.Logger)
+	caseExecutionStepRepository := mysql.NewCaseExecutionStepRepository(app.Logger)
+	runCommentRepository := mysql.NewRunCommentRepository(app.Logger)
+	testSuiteRepository := mysql.NewTestSuiteRepository(app.Logger)
+	testCaseRepository := mysql.NewTestCaseRepository(app.Logger)
+	testCaseStepRepository := mysql.NewTestCaseStepRepository(app.Logger)
+	importMappingRepository := mysql.NewImportMappingRepository(app.Logger)
 
 	// setup service
-	projectService := project.NewProjectService(app.Logger, app.DB, projectRepository)
+	runEvents := NewRunEvents(app.Config, app.Lifecycle, app.Logger)
+	projectService := project.NewProjectService(app.Logger, app.DB, app.Validate, projectRepository,
+		testSuiteRepository, testCaseRepository, testCaseStepRepository, testRunRepository, testResultRepository,
+		importMappingRepository, app.Config.Imports.BatchSize, app.Config.Imports.MaxRows)
+	testRunService := testrun.NewTestRunService(app.Logger, app.DB, projectRepository,
+		testRunRepository, testResultRepository, runEvents, app.Config.Results.BatchSize, app.LiveConfig.QualityGate)
+	dashboardService := dashboard.NewDashboardService(app.Logger, app.DB, projectRepository,
+		testRunRepository, testResultRepository)
+	executionService := execution.NewExecutionService(app.Logger, app.DB, testRunRepository,
+		caseExecutionRepository, caseExecutionStepRepository, runCommentRepository)
+	runSessions := NewRunSessions(app.Config, app.LiveConfig, app.Lifecycle, executionService,
+		app.Validate, app.Logger)
+
+	testCaseService := NewTestCaseService(app.Config, app.DB, app.Validate, app.Logger)
 
 	// service injection
-	services := handlers.NewQMSEngineService(app.Logger, app.Validate, projectService)
+	services := handlers.NewQMSEngineService(app.Logger, app.Validate, projectService, testRunService,
+		runEvents, runSessions, testCaseService)
+
+	// setup request limits
+	rateLimiter := NewRateLimiter(app.Config, app.LiveConfig, app.Lifecycle, app.Logger)
+	idempotencyMiddleware := NewIdempotencyMiddleware(app.Config, app.DB, app.Lifecycle, app.Logger)
+	contractMiddleware := NewContractMiddleware(app.LiveConfig, app.Logger)
 
 	routeConfig := handlers.RouteConfig{
 		AppEngine:        app.AppEngine,
+		Limits:           rateLimiter.Limit,
+		Contract:         contractMiddleware.Handler(),
+		Idempotency:      idempotencyMiddleware.Handler(),
 		QMSEngineService: services,
 	}
 
 	routeConfig.RegisterRoutes()
+	if err := RegisterGraphQLRoutes(app.Config, app.AppEngine, dashboardService,
+		rateLimiter.Limit("graphql"), app.Logger); err != nil {
+		return err
+	}
+
+	if app.GRPCServer != nil {
+		qmsenginev1.RegisterProjectServiceServer(app.GRPCServer,
+			grpcapi.NewProjectServer(app.Logger, app.Validate, projectService))
+		qmsenginev1.RegisterTestRunServiceServer(app.GRPCServer,
+			grpcapi.NewTestRunServer(app.Logger, app.Validate, testRunService))
+		qmsenginev1.RegisterTestCaseServiceServer(app.GRPCServer,
+			grpcapi.NewTestCaseServer(app.Logger, app.Validate, testCaseService))
+	}
+	return nil
 }
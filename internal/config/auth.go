@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// AuthMiddleware sets the principal of requests carrying a valid API key or a verified client
+// certificate. The keys follow the live configuration, an unknown key is refused with 401
+func AuthMiddleware(live *LiveConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+			name, ok := apiKeyName(live.Current().Auth.APIKeys, apiKey)
+			if !ok {
+				abortWithError(c, common.ErrCode_Unauthorized, "INVALID_API_KEY", "the API key is not valid")
+				return
+			}
+			c.Set(common.PrincipalKey, "key:"+name)
+		} else if tlsState := c.Request.TLS; tlsState != nil && len(tlsState.VerifiedChains) > 0 {
+			c.Set(common.PrincipalKey, "cert:"+tlsState.VerifiedChains[0][0].Subject.CommonName)
+		}
+		c.Next()
+	}
+}
+
+// RequirePrincipal refuses the requests AuthMiddleware did not identify with 401, it guards
+// the endpoints meant for operators
+func RequirePrincipal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if common.Principal(c) == "" {
+			abortWithError(c, common.ErrCode_Unauthorized, "AUTHENTICATION_REQUIRED",
+				"an API key or a client certificate is required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// apiKeyName returns the name of apiKey among the configured keys. Keys without a name are named
+// by a hash prefix, so that the key itself does not end up in logs and stores
+func apiKeyName(configured []string, apiKey string) (string, bool) {
+	found, foundName := false, ""
+	for _, entry := range configured {
+		name, key, named := strings.Cut(entry, ":")
+		if !named {
+			key = entry
+			sum := sha256.Sum256([]byte(entry))
+			name = hex.EncodeToString(sum[:8])
+		}
+		// Every key is compared so that the time taken does not tell which one matched
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 && !found {
+			found, foundName = true, name
+		}
+	}
+	return foundName, found
+}
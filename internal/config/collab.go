@@ -0,0 +1,46 @@
+package config
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/project-weekend/qms-engine/internal/collab"
+	"github.com/project-weekend/qms-engine/internal/service"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+const runSessionsKeyPrefix = "qms:sessions:"
+
+// NewRunSessions creates the hub of collaborative run sessions, sharing their changes over a bus
+// of their own. Browsers may join from the page origin or the origins allowed by CORS. Sessions
+// end when the HTTP server starts draining
+func NewRunSessions(appCfg *config.Config, live *LiveConfig, lifecycle *Lifecycle,
+	executionService service.IExecutionService, validate *validator.Validate, logger *slog.Logger) *collab.Hub {
+	bus := newEventBus(appCfg, "run-sessions", runSessionsKeyPrefix, lifecycle, logger)
+	checkOrigin := func(r *http.Request) bool {
+		return allowedOrigin(r, live.Current().CORS.AllowOrigins)
+	}
+	sessionsCfg := appCfg.Sessions
+	hub := collab.NewHub(bus, executionService, validate, checkOrigin,
+		sessionsCfg.MaxTestersPerRun, sessionsCfg.MaxMessageSizeInKB*1024, logger)
+	// Drain hooks run in reverse order, so sessions end before the bus closes under them
+	lifecycle.OnDrain(hub.Close)
+	return hub
+}
+
+// allowedOrigin reports whether the page opening a WebSocket may do so. Clients other than
+// browsers send no Origin
+func allowedOrigin(r *http.Request, allowOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return true
+	}
+	return slices.Contains(allowOrigins, "*") || slices.Contains(allowOrigins, origin)
+}
@@ -1,14 +1,195 @@
 package config
 
-import "github.com/project-weekend/qms-engine/server/config"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
-func LoadConfig() *config.Config {
-	v := NewViper()
+	"github.com/go-playground/validator/v10"
+
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// LoadConfig loads and validates the configuration. A *ConfigError lists every problem found
+func LoadConfig(opts ConfigOptions) (*config.Config, error) {
+	v, _, err := NewViper(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	var conf config.Config
-	if err := v.Unmarshal(&conf); err != nil {
-		panic(err)
+	if err = v.Unmarshal(&conf); err != nil {
+		return nil, &ConfigError{Problems: []string{err.Error()}}
+	}
+	if err = ValidateConfig(&conf); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
+
+// ConfigError lists every problem found in the configuration
+type ConfigError struct {
+	Problems []string
+}
+
+// Error implements the error interface
+func (e *ConfigError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// ValidateConfig checks the validate tags of every config section and the rules spanning sections
+func ValidateConfig(conf *config.Config) error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonTagName)
+	validate.RegisterStructValidation(validateConfigRules, config.Config{})
+
+	err := validate.Struct(conf)
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	problems := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		problems = append(problems, describeConfigProblem(fieldErr))
 	}
+	return &ConfigError{Problems: problems}
+}
+
+// validateConfigRules holds the rules that span config sections
+func validateConfigRules(sl validator.StructLevel) {
+	conf := sl.Current().Interface().(config.Config)
 
-	return &conf
+	if isProduction(conf.Env) && conf.Database.Password == "" {
+		sl.ReportError(conf.Database.Password, "database.password", "Password", "required_in_production", "")
+	}
+	if isProduction(conf.Env) && conf.Contract.ValidateResponses {
+		sl.ReportError(conf.Contract.ValidateResponses, "contract.validateResponses", "ValidateResponses",
+			"not_in_production", "")
+	}
+	if conf.Logger.Output != "stdout" && conf.Logger.File.Path == "" {
+		sl.ReportError(conf.Logger.File.Path, "logger.file.path", "Path", "required_for_file_output", "")
+	}
+	if conf.CORS.AllowCredentials && slices.Contains(conf.CORS.AllowOrigins, "*") {
+		sl.ReportError(conf.CORS.AllowOrigins, "cors.allowOrigins", "AllowOrigins", "wildcard_with_credentials", "")
+	}
+	tlsCfg := conf.Server.TLS
+	if (tlsCfg.ClientCAFile != "" || len(tlsCfg.ClientCertPaths) > 0) && !tlsCfg.Enabled {
+		sl.ReportError(tlsCfg.Enabled, "server.tls.enabled", "Enabled", "required_for_client_certs", "")
+	}
+	if (conf.Limits.Store == "redis" || conf.Idempotency.Store == "redis") && conf.RedisConfig.Addr == "" {
+		sl.ReportError(conf.RedisConfig.Addr, "redisConfig.addr", "Addr", "required_for_redis_store", "")
+	}
+	if len(tlsCfg.ClientCertPaths) > 0 && tlsCfg.ClientCAFile == "" {
+		sl.ReportError(tlsCfg.ClientCAFile, "server.tls.clientCAFile", "ClientCAFile", "required_for_client_certs", "")
+	}
+	if conf.GRPC.Enabled && len(conf.GRPC.APIKeys) == 0 {
+		sl.ReportError(conf.GRPC.APIKeys, "grpc.apiKeys", "APIKeys", "required_for_grpc", "")
+	}
+}
+
+func describeConfigProblem(fieldErr validator.FieldError) string {
+	// Drop the leading "Config." of the namespace
+	_, key, _ := strings.Cut(fieldErr.Namespace(), ".")
+	value := fmt.Sprintf("%v", fieldErr.Value())
+
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", key)
+	case "required_if":
+		field, want, _ := strings.Cut(fieldErr.Param(), " ")
+		return fmt.Sprintf("%s is required when %s is %s", key, siblingKey(key, field), want)
+	case "required_in_production":
+		return fmt.Sprintf("%s is required in production, set it with %s or %s%s",
+			key, envVarName(key), envVarName(key), fileEnvSuffix)
+	case "not_in_production":
+		return fmt.Sprintf("%s must be off in production", key)
+	case "required_for_file_output":
+		return fmt.Sprintf("%s is required when logger.output writes to a file", key)
+	case "wildcard_with_credentials":
+		return fmt.Sprintf("%s must list explicit origins when cors.allowCredentials is true, browsers reject \"*\"", key)
+	case "required_for_client_certs":
+		return fmt.Sprintf("%s is required when server.tls.clientCertPaths or clientCAFile is set", key)
+	case "required_for_grpc":
+		return fmt.Sprintf("%s is required when grpc.enabled is true, set it with %s", key, envVarName(key))
+	case "required_for_redis_store":
+		return fmt.Sprintf("%s is required when limits.store or idempotency.store is redis", key)
+	case "cidr|ip":
+		return fmt.Sprintf("%s must be an IP or CIDR, got %q", key, value)
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s, got %s", key, fieldErr.Param(), value)
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s, got %s", key, fieldErr.Param(), value)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s, got %s", key, fieldErr.Param(), value)
+	case "ltefield":
+		return fmt.Sprintf("%s must not exceed %s, got %s", key, siblingKey(key, fieldErr.Param()), value)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s], got %q", key, fieldErr.Param(), value)
+	default:
+		return fmt.Sprintf("%s is not a valid %s, got %q", key, fieldErr.Tag(), value)
+	}
+}
+
+// siblingKey returns the dotted key of a struct field next to key, the config
+// json keys are the field names in lower camel case
+func siblingKey(key, field string) string {
+	parent := key[:strings.LastIndex(key, ".")+1]
+	return parent + strings.ToLower(field[:1]) + field[1:]
+}
+
+// jsonTagName names fields after their json key so problems match the config file
+func jsonTagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func isProduction(env string) bool {
+	return env == "production" || env == "prod"
+}
+
+// PrintConfig writes the effective value of every config key along with the layer it came from.
+// With redacted set, secrets are masked the same way the logger masks them
+func PrintConfig(w io.Writer, opts ConfigOptions, redacted bool) error {
+	v, sources, err := NewViper(opts)
+	if err != nil {
+		return err
+	}
+
+	var extraKeys []string
+	if err = v.UnmarshalKey("logger.redactKeys", &extraKeys); err != nil {
+		return err
+	}
+	sensitive := newRedactKeys(extraKeys)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, key := range appConfigKeys {
+		value := fmt.Sprint(v.Get(key.path))
+		if redacted {
+			value = sensitive.redactValue(key.path, value)
+		}
+
+		source := sources[key.path]
+		if source == "" {
+			source = "unset"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key.path, value, source)
+	}
+	return tw.Flush()
+}
+
+// redactValue masks value when the last segment of the dotted key is sensitive
+func (k redactKeys) redactValue(path, value string) string {
+	if k.isSensitive(path[strings.LastIndex(path, ".")+1:]) {
+		return redactedValue
+	}
+	return scrubCredentials(value)
 }
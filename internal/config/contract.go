@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/openapi"
+)
+
+// ContractMiddleware checks API traffic against the OpenAPI document when the live contract
+// config turns it on, so that drift between the models and clients shows in integration tests
+type ContractMiddleware struct {
+	live      *LiveConfig
+	validator *openapi.Validator
+	logger    *slog.Logger
+}
+
+// NewContractMiddleware creates the middleware checking against the generated document
+func NewContractMiddleware(live *LiveConfig, logger *slog.Logger) *ContractMiddleware {
+	return &ContractMiddleware{
+		live:      live,
+		validator: openapi.NewValidator(NewOpenAPIDocument()),
+		logger:    logger,
+	}
+}
+
+// Handler returns the gin middleware. It runs after the body size limit, undocumented routes pass through
+func (m *ContractMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contract := m.live.Current().Contract
+		op := m.validator.Operation(c.Request.Method, c.FullPath())
+		if op == nil || (!contract.ValidateRequests && !contract.ValidateResponses) {
+			c.Next()
+			return
+		}
+
+		if contract.ValidateResponses {
+			writer := &bufferedWriter{ResponseWriter: c.Writer}
+			c.Writer = writer
+			defer m.checkResponse(c, op, writer)
+		}
+		if contract.ValidateRequests && !m.checkRequest(c, op) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// checkRequest aborts with BAD_REQUEST when the request breaks op, listing the violations
+func (m *ContractMiddleware) checkRequest(c *gin.Context, op *openapi.Operation) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		// Left to the handler, which reports oversized and broken bodies
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		return true
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	pathParams := make(map[string]string, len(c.Params))
+	for _, param := range c.Params {
+		pathParams[param.Key] = param.Value
+	}
+	violations := m.validator.ValidateRequest(op, openapi.Request{
+		PathParams:  pathParams,
+		Query:       c.Request.URL.Query(),
+		Header:      c.Request.Header,
+		ContentType: c.ContentType(),
+		Body:        body,
+	})
+	if len(violations) == 0 {
+		return true
+	}
+
+	_ = c.Error(common.NewServiceError(common.ErrCode_BadRequest, contractErrorDetails("CONTRACT_VIOLATION", violations)))
+	c.Abort()
+	return false
+}
+
+// checkResponse sends the buffered response, or an INTERNAL_SERVER_ERROR listing the
+// violations when it breaks op
+func (m *ContractMiddleware) checkResponse(c *gin.Context, op *openapi.Operation, writer *bufferedWriter) {
+	// Errors are normally written by ErrorMiddleware, they are written here to be checked
+	if len(c.Errors) > 0 && !writer.Written() {
+		renderError(c, c.Errors.Last().Err)
+	}
+	c.Writer = writer.ResponseWriter
+
+	violations := m.validator.ValidateResponse(op, writer.Status(), writer.Header().Get("Content-Type"),
+		writer.body.Bytes())
+	if len(violations) == 0 {
+		writer.flush()
+		return
+	}
+
+	m.logger.ErrorContext(c, "Response breaks the OpenAPI document", "route", c.FullPath(),
+		"status", writer.Status(), "violations", violations)
+	c.Writer.Header().Del("ETag")
+	renderError(c, common.NewServiceError(common.ErrCode_InternalServerError,
+		contractErrorDetails("RESPONSE_CONTRACT_VIOLATION", violations)))
+}
+
+func contractErrorDetails(errorCode string, violations []openapi.Violation) []common.ErrorDetail {
+	details := make([]common.ErrorDetail, 0, len(violations))
+	for _, violation := range violations {
+		details = append(details, common.ErrorDetail{
+			ErrorCode: errorCode,
+			Message:   violation.Message,
+			Path:      violation.Path,
+			Meta:      map[string]any{"in": violation.In},
+		})
+	}
+	return details
+}
+
+// bufferedWriter holds the response back until it is checked. The status and headers go
+// to the wrapped writer, which sends them with the body on flush
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	pending bool
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.pending = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.pending = true
+	return w.body.WriteString(s)
+}
+
+// WriteHeaderNow is deferred to flush so that the response can still be replaced
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.pending = true
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.pending || w.ResponseWriter.Written()
+}
+
+func (w *bufferedWriter) flush() {
+	if !w.pending {
+		return
+	}
+	if w.body.Len() == 0 || w.Status() == http.StatusNoContent {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// CORSMiddleware applies the configured cross-origin policy, Apply replaces the policy at runtime
+type CORSMiddleware struct {
+	handler atomic.Pointer[gin.HandlerFunc]
+}
+
+// NewCORSMiddleware creates the middleware with the given policy
+func NewCORSMiddleware(corsCfg config.CORSConfig) (*CORSMiddleware, error) {
+	m := &CORSMiddleware{}
+	if err := m.Apply(corsCfg); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Apply validates the policy and swaps it in, the current policy is kept when it is invalid
+func (m *CORSMiddleware) Apply(corsCfg config.CORSConfig) error {
+	// Without origins only same-origin requests are served, which needs no CORS headers
+	var handler gin.HandlerFunc = func(c *gin.Context) { c.Next() }
+	if len(corsCfg.AllowOrigins) > 0 {
+		corsConfig := cors.Config{
+			AllowAllOrigins:  slices.Equal(corsCfg.AllowOrigins, []string{"*"}),
+			AllowMethods:     corsCfg.AllowMethods,
+			AllowHeaders:     corsCfg.AllowHeaders,
+			ExposeHeaders:    corsCfg.ExposeHeaders,
+			AllowCredentials: corsCfg.AllowCredentials,
+			MaxAge:           time.Duration(corsCfg.MaxAgeInSec) * time.Second,
+		}
+		if !corsConfig.AllowAllOrigins {
+			corsConfig.AllowOrigins = corsCfg.AllowOrigins
+		}
+		if err := corsConfig.Validate(); err != nil {
+			return err
+		}
+		handler = cors.New(corsConfig)
+	}
+
+	m.handler.Store(&handler)
+	return nil
+}
+
+// Handler returns the gin middleware delegating to the current policy
+func (m *CORSMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		(*m.handler.Load())(c)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
@@ -29,6 +30,7 @@ func NewDatabase(appCfg *config.Config, logger *slog.Logger) *sqlx.DB {
 	db, err := sqlx.Connect("mysql", dsn)
 	if err != nil {
 		logger.Error("Failed to connect to database", "error", err)
+		_ = FlushLogger(context.Background(), logger)
 		log.Fatal(err)
 	}
 
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// problemContentType is the RFC 7807 media type, sent to clients that accept it
+const problemContentType = "application/problem+json"
+
+// ErrorMiddleware writes the response of the last error that handlers and middleware
+// attached with c.Error. Errors other than *common.ServiceError become INTERNAL_SERVER_ERROR
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		renderError(c, c.Errors.Last().Err)
+	}
+}
+
+// NotFoundHandler reports unknown routes as RESOURCE_NOT_FOUND
+func NotFoundHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		abortWithError(c, common.ErrCode_ResourceNotFound, "", "")
+	}
+}
+
+// renderError writes err as JSON, or as an RFC 7807 problem when the client prefers application/problem+json
+func renderError(c *gin.Context, err error) {
+	serviceErr := common.AsServiceError(err).Localized(c.GetString(common.LocaleKey))
+	if c.NegotiateFormat(gin.MIMEJSON, problemContentType) == problemContentType {
+		c.Header("Content-Type", problemContentType)
+		c.Render(serviceErr.HTTPStatus, render.JSON{Data: serviceErr.Problem(c.Request.URL.Path)})
+		return
+	}
+	c.JSON(serviceErr.HTTPStatus, serviceErr)
+}
+
+// abortWithError aborts with the service error of code, with a detail when errorCode is set.
+// The response is written by ErrorMiddleware
+func abortWithError(c *gin.Context, code common.ErrorCode, errorCode, message string) {
+	var details []common.ErrorDetail
+	if errorCode != "" {
+		details = []common.ErrorDetail{{ErrorCode: errorCode, Message: message}}
+	}
+	_ = c.Error(common.NewServiceError(code, details))
+	c.Abort()
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/project-weekend/qms-engine/internal/events"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+const (
+	runEventsKeyPrefix = "qms:events:"
+	// runEventsIdle is how long the history of a run without subscribers and events is kept
+	runEventsIdle = 10 * time.Minute
+)
+
+// NewRunEvents creates the bus of test run events with the configured broker. The streams
+// following it end when the HTTP server starts draining
+func NewRunEvents(appCfg *config.Config, lifecycle *Lifecycle, logger *slog.Logger) *events.Bus {
+	return newEventBus(appCfg, "run-events", runEventsKeyPrefix, lifecycle, logger)
+}
+
+// newEventBus creates a bus named name with the configured broker, keeping its Redis keys under prefix
+func newEventBus(appCfg *config.Config, name, prefix string, lifecycle *Lifecycle, logger *slog.Logger) *events.Bus {
+	eventsCfg := appCfg.Events
+	hub := events.NewHub(eventsCfg.HistorySize, eventsCfg.BufferSize)
+	lifecycle.Go(name+"-sweeper", func(ctx context.Context) {
+		hub.Run(ctx, runEventsIdle)
+	})
+	lifecycle.OnDrain(hub.Close)
+
+	var broker events.Broker
+	if eventsCfg.Broker == "redis" {
+		client := NewRedisClient(appCfg)
+		lifecycle.OnShutdown(name+"-redis", func(context.Context) error {
+			return client.Close()
+		})
+		redisBroker := events.NewRedisBroker(client, prefix, hub, logger)
+		lifecycle.Go(name+"-subscriber", redisBroker.Run)
+		broker = redisBroker
+	} else {
+		broker = events.NewMemoryBroker(hub)
+	}
+	return events.NewBus(hub, broker, logger)
+}
@@ -1,19 +1,24 @@
 package config
 
 import (
+	"context"
+	stdlog "log"
 	"log/slog"
 	"net/http"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+
 	"github.com/project-weekend/qms-engine/server/config"
 )
 
-// NewGinEngine initializes and configures a new Gin engine with middleware
-func NewGinEngine(config *config.Config, log *slog.Logger) *gin.Engine {
+// NewGinEngine initializes and configures a new Gin engine with middleware.
+// The CORS policy follows live, the other settings are fixed at startup
+func NewGinEngine(appCfg *config.Config, live *LiveConfig, log *slog.Logger) *gin.Engine {
 	// Set Gin mode based on environment
-	if config.Env == "production" || config.Env == "prod" {
+	if isProduction(appCfg.Env) {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
@@ -22,24 +27,45 @@ func NewGinEngine(config *config.Config, log *slog.Logger) *gin.Engine {
 	// Create new Gin engine
 	engine := gin.New()
 
+	// Only believe forwarding headers from our own proxies, so c.ClientIP() cannot be spoofed
+	engine.RemoteIPHeaders = appCfg.Server.RemoteIPHeaders
+	if err := engine.SetTrustedProxies(appCfg.Server.TrustedProxies); err != nil {
+		log.Error("Invalid trusted proxies", "error", err)
+		_ = FlushLogger(context.Background(), log)
+		stdlog.Fatal(err)
+	}
+
 	// Add custom recovery middleware
 	engine.Use(RecoveryMiddleware(log))
 
 	// Add custom logging middleware
 	engine.Use(LoggingMiddleware(log))
 
+	// Add error middleware, handlers report errors with c.Error
+	engine.Use(ErrorMiddleware())
+	engine.NoRoute(NotFoundHandler())
+
 	// Add CORS middleware
-	engine.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// Add health check endpoints
-	engine.GET("/health", HealthCheckHandler())
+	corsMiddleware, err := NewCORSMiddleware(appCfg.CORS)
+	if err != nil {
+		log.Error("Invalid CORS configuration", "error", err)
+		_ = FlushLogger(context.Background(), log)
+		stdlog.Fatal(err)
+	}
+	live.Subscribe("cors", func(conf *config.Config) error {
+		return corsMiddleware.Apply(conf.CORS)
+	})
+	engine.Use(corsMiddleware.Handler())
+
+	// Require client certificates where mTLS is configured
+	if len(appCfg.Server.TLS.ClientCertPaths) > 0 {
+		engine.Use(ClientCertMiddleware(appCfg.Server.TLS))
+	}
+
+	// Identify callers by API key or client certificate
+	engine.Use(AuthMiddleware(live))
+
+	// Add ping endpoint, health checks are registered by RegisterHealthRoutes
 	engine.GET("/ping", PingHandler())
 
 	log.Info("Gin engine initialized successfully")
@@ -86,26 +112,14 @@ func RecoveryMiddleware(log *slog.Logger) gin.HandlerFunc {
 					"ip", c.ClientIP(),
 				)
 
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error": "Internal server error",
-				})
+				c.Abort()
+				renderError(c, common.NewServiceError(common.ErrCode_InternalServerError, nil))
 			}
 		}()
 		c.Next()
 	}
 }
 
-// HealthCheckHandler returns a handler for health check endpoint
-func HealthCheckHandler() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "healthy",
-			"usecase": "qms-engine",
-			"time":    time.Now().Format(time.RFC3339),
-		})
-	}
-}
-
 // PingHandler returns a handler for ping endpoint
 func PingHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/graphqlapi"
+	"github.com/project-weekend/qms-engine/internal/service"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// GraphQLPath is where the GraphQL endpoint is served
+const GraphQLPath = "/graphql"
+
+// RegisterGraphQLRoutes serves GraphQLPath when GraphQL is enabled. Its fields are authorized
+// by the rules of the REST paths serving the same data, limit is the middleware of its route group
+func RegisterGraphQLRoutes(appCfg *config.Config, engine *gin.Engine, dashboardService service.IDashboardService,
+	limit gin.HandlerFunc, logger *slog.Logger) error {
+	graphqlCfg := appCfg.GraphQL
+	if !graphqlCfg.Enabled {
+		return nil
+	}
+
+	tlsCfg := appCfg.Server.TLS
+	authorize := func(ctx context.Context, path string) error {
+		var state *tls.ConnectionState
+		if req := graphqlapi.HTTPRequest(ctx); req != nil {
+			state = req.TLS
+		}
+		_, err := checkClientCert(tlsCfg, path, state)
+		return err
+	}
+
+	server, err := graphqlapi.NewServer(logger, dashboardService, authorize, graphqlapi.Limits{
+		MaxDepth:      graphqlCfg.MaxDepth,
+		MaxComplexity: graphqlCfg.MaxComplexity,
+		Introspection: graphqlCfg.Introspection,
+	})
+	if err != nil {
+		return err
+	}
+	engine.POST(GraphQLPath, limit, server.Handler)
+	return nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/project-weekend/qms-engine/internal/grpcapi"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// NewGRPCServer creates the gRPC server with the interceptors, or returns nil when gRPC is disabled.
+// Services are registered by Bootstrap
+func NewGRPCServer(appCfg *config.Config, logger *slog.Logger) *grpc.Server {
+	grpcCfg := appCfg.GRPC
+	if !grpcCfg.Enabled {
+		return nil
+	}
+	unary, stream := grpcapi.Interceptors(logger, grpcCfg.APIKeys)
+	server := grpc.NewServer(unary, stream, grpc.MaxRecvMsgSize(grpcCfg.MaxRecvMsgSizeInKB*1024))
+	if grpcCfg.Reflection {
+		reflection.Register(server)
+	}
+	return server
+}
+
+// ServeGRPC listens on the gRPC port and serves until shutdown, when in-flight calls are drained.
+// It fails when the port cannot be bound
+func ServeGRPC(appCfg *config.Config, server *grpc.Server, lifecycle *Lifecycle, logger *slog.Logger) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", appCfg.Host, appCfg.GRPC.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	// Calls in flight are drained when shutdown starts, the hook cuts those still running at the deadline
+	lifecycle.Go("grpc-server", func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			server.GracefulStop()
+		}()
+		logger.Info(fmt.Sprintf("Starting gRPC server on %s", listener.Addr()))
+		if err := server.Serve(listener); err != nil {
+			logger.Error("gRPC server stopped", "error", err)
+		}
+	})
+	lifecycle.OnShutdown("grpc-server", func(context.Context) error {
+		server.Stop()
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"expvar"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	migrations "github.com/project-weekend/qms-engine/db/mysql"
+	"github.com/project-weekend/qms-engine/internal/health"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// NewHealthRegistry registers the checks for every configured dependency
+func NewHealthRegistry(appCfg *config.Config, db *sqlx.DB, logger *slog.Logger) *health.Registry {
+	healthCfg := appCfg.Health
+	registry := health.NewRegistry(
+		time.Duration(healthCfg.TimeoutInMs)*time.Millisecond,
+		time.Duration(healthCfg.CacheTTLInMs)*time.Millisecond,
+	)
+	critical := func(name string) bool {
+		return slices.Contains(healthCfg.Critical, name)
+	}
+
+	registry.Register("database", critical("database"), health.DatabaseChecker(db))
+
+	if version, err := migrations.LatestVersion(); err != nil {
+		logger.Error("Failed to read migration versions, skipping migration check", "error", err)
+	} else {
+		registry.Register("migrations", critical("migrations"), health.MigrationChecker(db, version))
+	}
+
+	if appCfg.RedisConfig.Addr != "" {
+		registry.Register("redis", critical("redis"),
+			health.RedisChecker(appCfg.RedisConfig.Addr, appCfg.RedisConfig.TLSEnabled))
+	}
+	if appCfg.Kafka.ProducerEnabled && appCfg.Kafka.BootstrapServers != "" {
+		registry.Register("kafka", critical("kafka"), health.KafkaChecker(appCfg.Kafka.BootstrapServers))
+	}
+
+	return registry
+}
+
+// RegisterHealthRoutes adds the liveness and readiness endpoints, and the expvar
+// counters such as the gRPC call metrics. The counters, which also hold the command
+// line of the process, are only served to authenticated callers
+func RegisterHealthRoutes(engine *gin.Engine, lifecycle *Lifecycle, registry *health.Registry) {
+	engine.GET("/health", HealthCheckHandler())
+	engine.GET("/health/live", HealthCheckHandler())
+	engine.GET("/health/ready", ReadinessHandler(lifecycle, registry))
+	engine.GET("/debug/vars", RequirePrincipal(), gin.WrapH(expvar.Handler()))
+}
+
+// HealthCheckHandler returns a handler for the liveness endpoint, it only tells
+// that the process is able to serve requests and never checks dependencies
+func HealthCheckHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "healthy",
+			"usecase": "qms-engine",
+			"time":    time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// ReadinessHandler returns a handler for the readiness endpoint, it responds 503
+// while the service is starting or draining, or when a critical dependency is down
+func ReadinessHandler(lifecycle *Lifecycle, registry *health.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !lifecycle.Ready() {
+			c.JSON(http.StatusServiceUnavailable, health.Report{
+				Status:    health.StatusDown,
+				CheckedAt: time.Now(),
+			})
+			return
+		}
+
+		report := registry.Check(c.Request.Context())
+		status := http.StatusOK
+		if report.Status == health.StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// NewHTTPServer creates the HTTP server serving handler with the configured timeouts.
+// With TLS enabled the server gets a TLSConfig whose certificate follows the files on disk
+func NewHTTPServer(appCfg *config.Config, handler http.Handler, lifecycle *Lifecycle,
+	logger *slog.Logger) (*http.Server, error) {
+	serverCfg := appCfg.Server
+	server := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", appCfg.Host, appCfg.Port),
+		Handler:           handler,
+		ReadTimeout:       time.Duration(serverCfg.ReadTimeoutInSec) * time.Second,
+		ReadHeaderTimeout: time.Duration(serverCfg.ReadHeaderTimeoutInSec) * time.Second,
+		WriteTimeout:      time.Duration(serverCfg.WriteTimeoutInSec) * time.Second,
+		IdleTimeout:       time.Duration(serverCfg.IdleTimeoutInSec) * time.Second,
+	}
+	server.RegisterOnShutdown(lifecycle.Drain)
+
+	if serverCfg.TLS.Enabled {
+		certReloader, err := NewCertReloader(serverCfg.TLS, logger)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = certReloader.TLSConfig()
+		lifecycle.Go("tls-cert-reloader", certReloader.Run)
+	}
+	return server, nil
+}
+
+// Serve serves HTTPS on listener when the server has a TLSConfig and plain HTTP otherwise
+func Serve(server *http.Server, listener net.Listener) error {
+	if server.TLSConfig != nil {
+		// The certificate comes from TLSConfig
+		return server.ServeTLS(listener, "", "")
+	}
+	return server.Serve(listener)
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/idempotency"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+const (
+	// IdempotencyKeyHeader makes retries of a POST or PATCH return the response of the first attempt
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from the store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyPrefix   = "qms:idempotency:"
+	maxIdempotencyKeyLen   = 255
+	idempotencyPollDelay   = 100 * time.Millisecond
+	idempotencyPurgePeriod = time.Hour
+)
+
+// replayedHeaders are the response headers stored with the body and written back on replay,
+// others describe the transfer of the first response rather than its outcome
+var replayedHeaders = []string{
+	"ETag", "Location", "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+}
+
+// IdempotencyMiddleware stores the first response of POST and PATCH requests carrying an
+// Idempotency-Key and replays it to the retries of the same principal
+type IdempotencyMiddleware struct {
+	store  idempotency.Store
+	cfg    config.Idempotency
+	logger *slog.Logger
+}
+
+// NewIdempotencyMiddleware creates the middleware with the configured store
+func NewIdempotencyMiddleware(appCfg *config.Config, db *sqlx.DB, lifecycle *Lifecycle,
+	logger *slog.Logger) *IdempotencyMiddleware {
+	var store idempotency.Store
+	if appCfg.Idempotency.Store == "redis" {
+		client := NewRedisClient(appCfg)
+		lifecycle.OnShutdown("idempotency-redis", func(context.Context) error {
+			return client.Close()
+		})
+		store = idempotency.NewRedisStore(client, idempotencyKeyPrefix)
+	} else {
+		mysqlStore := idempotency.NewMySQLStore(db)
+		lifecycle.Go("idempotency-purge", func(ctx context.Context) {
+			mysqlStore.Run(ctx, idempotencyPurgePeriod)
+		})
+		store = mysqlStore
+	}
+	return &IdempotencyMiddleware{store: store, cfg: appCfg.Idempotency, logger: logger}
+}
+
+// Handler returns the gin middleware, requests without the header pass through
+func (m *IdempotencyMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		method := c.Request.Method
+		if key == "" || (method != http.MethodPost && method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			abortWithError(c, common.ErrCode_BadRequest, "INVALID_IDEMPOTENCY_KEY", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortWithError(c, common.ErrCode_PayloadTooLarge, "", "")
+				return
+			}
+			abortWithError(c, common.ErrCode_BadRequest, "", "")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped by the verified principal, or the IP of anonymous clients, so that
+		// nobody can read back the responses of another client
+		storeKey := hashHex(clientKey(c), key)
+		fingerprint := hashHex(method, c.Request.URL.Path, c.Request.URL.RawQuery, string(body))
+		token := lockToken()
+		if record, ok := m.begin(c, storeKey, token, fingerprint); ok {
+			m.run(c, storeKey, token)
+		} else if record != nil {
+			m.replay(c, record, fingerprint)
+		}
+	}
+}
+
+// begin locks the key, waiting for a request holding it to finish. It reports whether the
+// key was locked, otherwise it returns the stored record or has aborted the request
+func (m *IdempotencyMiddleware) begin(c *gin.Context, storeKey, token, fingerprint string) (*idempotency.Record, bool) {
+	lockTTL := time.Duration(m.cfg.LockTimeoutInSec) * time.Second
+	deadline := time.Now().Add(time.Duration(m.cfg.WaitTimeoutInSec) * time.Second)
+	for {
+		record, err := m.store.Begin(c.Request.Context(), storeKey, token, fingerprint, lockTTL)
+		switch {
+		case err == nil:
+			return record, record == nil
+		case !errors.Is(err, idempotency.ErrInProgress):
+			m.logger.ErrorContext(c, "Idempotency store failed", "error", err)
+			abortWithError(c, common.ErrCode_InternalServerError, "", "")
+			return nil, false
+		case time.Now().After(deadline):
+			abortWithError(c, common.ErrCode_Conflict, "IDEMPOTENCY_KEY_IN_PROGRESS",
+				"a request with this Idempotency-Key is in progress, retry later")
+			return nil, false
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			c.Abort()
+			return nil, false
+		case <-time.After(idempotencyPollDelay):
+		}
+	}
+}
+
+// run runs the handlers and stores their response. Server errors are not stored,
+// the key is released so that a retry runs the request again
+func (m *IdempotencyMiddleware) run(c *gin.Context, storeKey, token string) {
+	writer := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	// The request may be cancelled by the client, the outcome must be stored anyway
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := m.store.Release(ctx, storeKey, token); err != nil {
+			m.logger.ErrorContext(c, "Failed to release idempotency key", "error", err)
+		}
+	}()
+
+	c.Next()
+	// Errors are normally written by ErrorMiddleware, they are written here to be recorded
+	if len(c.Errors) > 0 && !writer.Written() {
+		renderError(c, c.Errors.Last().Err)
+	}
+	if writer.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	response := idempotency.Response{
+		StatusCode:  writer.Status(),
+		ContentType: writer.Header().Get("Content-Type"),
+		Header:      replayedHeader(writer.Header()),
+		Body:        writer.body.Bytes(),
+	}
+	ttl := time.Duration(m.cfg.TTLInSec) * time.Second
+	err := m.store.Complete(ctx, storeKey, token, response, ttl)
+	if errors.Is(err, idempotency.ErrLockLost) {
+		// The request outlived its lock and another one holds the key now, its record stays
+		m.logger.WarnContext(c, "Idempotency lock expired before the response was stored")
+		return
+	}
+	if err != nil {
+		m.logger.ErrorContext(c, "Failed to store idempotent response", "error", err)
+		return
+	}
+	completed = true
+}
+
+// replay writes the stored response, refusing a key reused for a different request
+func (m *IdempotencyMiddleware) replay(c *gin.Context, record *idempotency.Record, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		abortWithError(c, common.ErrCode_Unprocessable, "IDEMPOTENCY_KEY_REUSED",
+			"the Idempotency-Key was already used for a different request")
+		return
+	}
+
+	// Headers the retry already got, such as its own rate limit, are not replaced by the stored ones
+	header := c.Writer.Header()
+	for name, values := range record.Response.Header {
+		if header.Get(name) == "" {
+			header[name] = values
+		}
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.Response.StatusCode, record.Response.ContentType, record.Response.Body)
+	c.Abort()
+}
+
+// replayedHeader returns the replayedHeaders of header, nil when it has none of them
+func replayedHeader(header http.Header) http.Header {
+	var kept http.Header
+	for _, name := range replayedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			if kept == nil {
+				kept = http.Header{}
+			}
+			kept[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return kept
+}
+
+// recordingWriter keeps a copy of the response body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// lockToken returns a random token identifying the lock of one request
+func lockToken() string {
+	token := make([]byte, 16)
+	// crypto/rand.Read does not fail
+	_, _ = rand.Read(token)
+	return hex.EncodeToString(token)
+}
+
+// hashHex returns the hex sha256 of the parts, separated so that they cannot run into each other
+func hashHex(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
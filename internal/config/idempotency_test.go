@@ -0,0 +1,264 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/idempotency"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// memoryIdempotencyStore keeps the records in a map, the way the MySQL and Redis stores do
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+}
+
+func (s *memoryIdempotencyStore) Begin(_ context.Context, key, token, fingerprint string,
+	_ time.Duration) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		s.records[key] = &idempotency.Record{Fingerprint: fingerprint, Token: token}
+		return nil, nil
+	}
+	stored := *record
+	if stored.Response == nil {
+		return &stored, idempotency.ErrInProgress
+	}
+	return &stored, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key, token string, response idempotency.Response,
+	_ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok || record.Token != token || record.Response != nil {
+		return idempotency.ErrLockLost
+	}
+	// Stored as the MySQL and Redis stores do, through JSON
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	record.Response = new(idempotency.Response)
+	return json.Unmarshal(encoded, record.Response)
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok && record.Token == token && record.Response == nil {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// idempotentServer serves POST /project behind the idempotency middleware. Every request gets
+// a RateLimit-Remaining counting down, as the limiter would, the handler answers with statuses
+// in turn and is held until release is closed when it is not nil
+type idempotentServer struct {
+	engine   *gin.Engine
+	calls    int
+	statuses []int
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func newIdempotentServer(statuses ...int) *idempotentServer {
+	gin.SetMode(gin.TestMode)
+	server := &idempotentServer{statuses: statuses}
+	middleware := &IdempotencyMiddleware{
+		store:  &memoryIdempotencyStore{records: map[string]*idempotency.Record{}},
+		cfg:    config.Idempotency{TTLInSec: 60, LockTimeoutInSec: 60},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	var mu sync.Mutex
+	remaining := 100
+	server.engine = gin.New()
+	server.engine.Use(ErrorMiddleware(), func(c *gin.Context) {
+		mu.Lock()
+		remaining--
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+		mu.Unlock()
+		if principal := c.GetHeader("X-Test-Principal"); principal != "" {
+			c.Set(common.PrincipalKey, principal)
+		}
+	}, middleware.Handler())
+	server.engine.POST("/project", func(c *gin.Context) {
+		mu.Lock()
+		server.calls++
+		call := server.calls
+		mu.Unlock()
+		if server.release != nil {
+			server.started <- struct{}{}
+			<-server.release
+		}
+		status := server.statuses[min(call, len(server.statuses))-1]
+		if status >= http.StatusInternalServerError {
+			_ = c.Error(common.NewServiceError(common.ErrCode_InternalServerError, nil))
+			return
+		}
+		c.Header("ETag", `"1"`)
+		c.Header("Location", "/api/v1/project/"+strconv.Itoa(call))
+		c.Header("X-Call", strconv.Itoa(call))
+		c.JSON(status, gin.H{"id": call})
+	})
+	return server
+}
+
+func (s *idempotentServer) post(principal, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/project", strings.NewReader(body))
+	request.Header.Set("X-Test-Principal", principal)
+	request.Header.Set(IdempotencyKeyHeader, key)
+	recorder := httptest.NewRecorder()
+	s.engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// errorCode returns the code of the first detail of an error response
+func errorCode(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+	var serviceErr common.ServiceError
+	if err := json.Unmarshal(recorder.Body.Bytes(), &serviceErr); err != nil || len(serviceErr.Errors) == 0 {
+		t.Fatalf("body = %s, want an error detail", recorder.Body)
+	}
+	return serviceErr.Errors[0].ErrorCode
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	server := newIdempotentServer(http.StatusCreated)
+	first := server.post("key:ci", "k1", `{"name":"Checkout"}`)
+	retry := server.post("key:ci", "k1", `{"name":"Checkout"}`)
+
+	if server.calls != 1 {
+		t.Fatalf("calls = %d, want 1", server.calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	for name, want := range map[string]string{
+		"ETag":                   `"1"`,
+		"Location":               "/api/v1/project/1",
+		"Content-Type":           first.Header().Get("Content-Type"),
+		IdempotentReplayedHeader: "true",
+		// The retry keeps its own rate limit, headers outside the allowlist are not stored
+		"RateLimit-Remaining": "98",
+		"X-Call":              "",
+	} {
+		if got := retry.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		principal  string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantCalls  int
+	}{
+		{
+			name:       "a key reused for another request is refused",
+			statuses:   []int{http.StatusCreated},
+			principal:  "key:ci",
+			body:       `{"name":"Payments"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "IDEMPOTENCY_KEY_REUSED",
+			wantCalls:  1,
+		},
+		{
+			name:       "keys are scoped to the principal",
+			statuses:   []int{http.StatusCreated},
+			principal:  "key:other",
+			body:       `{"name":"Checkout"}`,
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "server errors are not stored, the retry runs again",
+			statuses:   []int{http.StatusInternalServerError, http.StatusCreated},
+			principal:  "key:ci",
+			body:       `{"name":"Checkout"}`,
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "client errors are stored",
+			statuses:   []int{http.StatusConflict, http.StatusCreated},
+			principal:  "key:ci",
+			body:       `{"name":"Checkout"}`,
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newIdempotentServer(tt.statuses...)
+			server.post("key:ci", "k1", `{"name":"Checkout"}`)
+			retry := server.post(tt.principal, "k1", tt.body)
+			if retry.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", retry.Code, tt.wantStatus)
+			}
+			if tt.wantCode != "" {
+				if code := errorCode(t, retry); code != tt.wantCode {
+					t.Errorf("code = %s, want %s", code, tt.wantCode)
+				}
+			}
+			if server.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", server.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestIdempotencyConcurrentRequest sends a retry while the first request is still running,
+// with no time to wait for it the retry gets a 409
+func TestIdempotencyConcurrentRequest(t *testing.T) {
+	server := newIdempotentServer(http.StatusCreated)
+	server.started, server.release = make(chan struct{}), make(chan struct{})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- server.post("key:ci", "k1", `{"name":"Checkout"}`)
+	}()
+	<-server.started
+
+	retry := server.post("key:ci", "k1", `{"name":"Checkout"}`)
+	if retry.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", retry.Code)
+	}
+	if code := errorCode(t, retry); code != "IDEMPOTENCY_KEY_IN_PROGRESS" {
+		t.Errorf("code = %s, want IDEMPOTENCY_KEY_IN_PROGRESS", code)
+	}
+
+	close(server.release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first status = %d, want 201", first.Code)
+	}
+	if again := server.post("key:ci", "k1", `{"name":"Checkout"}`); again.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("a retry after the first request finished was not replayed")
+	}
+	if server.calls != 1 {
+		t.Errorf("calls = %d, want 1", server.calls)
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+	"github.com/project-weekend/qms-engine/internal/service/testcase"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// NewTestCaseService creates the service reading and importing test cases, for the server and the import command
+func NewTestCaseService(appCfg *config.Config, db *sqlx.DB, validate *validator.Validate,
+	logger *slog.Logger) *testcase.TestCaseServiceImpl {
+	return testcase.NewTestCaseService(logger, db, validate, mysql.NewProjectRepository(logger),
+		mysql.NewTestSuiteRepository(logger), mysql.NewTestCaseRepository(logger),
+		mysql.NewTestCaseStepRepository(logger),
+		appCfg.Imports.BatchSize, appCfg.Imports.MaxRows)
+}
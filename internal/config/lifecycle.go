@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// Lifecycle tracks the readiness of the service and stops its components on shutdown
+type Lifecycle struct {
+	logger  *slog.Logger
+	ready   atomic.Bool
+	mu      sync.Mutex
+	hooks   []shutdownHook
+	drains  []func()
+	drained bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewLifecycle creates a Lifecycle that starts out not ready
+func NewLifecycle(logger *slog.Logger) *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifecycle{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Ready reports whether the service should receive traffic
+func (l *Lifecycle) Ready() bool {
+	return l.ready.Load()
+}
+
+// SetReady flips the readiness flag
+func (l *Lifecycle) SetReady(ready bool) {
+	l.ready.Store(ready)
+}
+
+// Go runs a background worker until shutdown. The worker must return once ctx is done
+func (l *Lifecycle) Go(name string, worker func(ctx context.Context)) {
+	l.workers.Add(1)
+	go func() {
+		defer l.workers.Done()
+		worker(l.ctx)
+		l.logger.Debug("Background worker stopped", "worker", name)
+	}()
+}
+
+// OnShutdown registers a hook that releases a resource. Hooks run in reverse order
+// of registration, so a component stops before the ones it depends on
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// OnDrain registers fn to run when the HTTP server starts draining. Handlers that hold their
+// connection open, like event streams, end there, the server would otherwise wait for them.
+// Hooks run in reverse order of registration, like shutdown hooks
+func (l *Lifecycle) OnDrain(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.drains = append(l.drains, fn)
+}
+
+// Drain runs the drain hooks, only the first call does
+func (l *Lifecycle) Drain() {
+	l.mu.Lock()
+	drains := l.drains
+	if l.drained {
+		drains = nil
+	}
+	l.drained = true
+	l.mu.Unlock()
+
+	for i := len(drains) - 1; i >= 0; i-- {
+		drains[i]()
+	}
+}
+
+// Shutdown stops background workers, then runs the shutdown hooks. It gives up waiting
+// for workers once ctx is done but still runs every hook
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.SetReady(false)
+	l.cancel()
+
+	var errs []error
+	done := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("stop background workers: %w", ctx.Err()))
+	}
+
+	l.mu.Lock()
+	hooks := l.hooks
+	l.hooks = nil
+	l.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if err := hook.fn(ctx); err != nil {
+			l.logger.Error("Shutdown hook failed", "component", hook.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+			continue
+		}
+		l.logger.Info("Component stopped", "component", hook.name)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "20060102T150405.000"
+	megabyte         = 1024 * 1024
+)
+
+// RotatingFileOptions configures a RotatingFile
+type RotatingFileOptions struct {
+	// Path of the active log file, backups are written next to it
+	Path string
+	// MaxSize rotates the file once it would grow beyond this many bytes, 0 disables it
+	MaxSize int64
+	// MaxAge rotates the file once it has been open for this long, 0 disables it
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep, 0 keeps all of them
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// RotatingFile is an io.WriteCloser that rotates the underlying file by size and age
+type RotatingFile struct {
+	opts     RotatingFileOptions
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+	cleanup  sync.WaitGroup
+	// maintenance serializes compression and pruning of backups
+	maintenance sync.Mutex
+}
+
+// NewRotatingFile opens, or creates, the log file at opts.Path
+func NewRotatingFile(opts RotatingFileOptions) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+
+	f := &RotatingFile{opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first when a limit is reached
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	// A file that could not be reopened after a rotation is retried on every write
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			if f.file == nil {
+				return 0, err
+			}
+			// The record still goes to the file that could not be rotated
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the active file and waits for pending compression
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cleanup.Wait()
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+func (f *RotatingFile) shouldRotate(incoming int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.opts.MaxSize > 0 && f.size+incoming > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.MaxAge > 0 && time.Since(f.openedAt) >= f.opts.MaxAge
+}
+
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+
+	ext := filepath.Ext(f.opts.Path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.opts.Path, ext), time.Now().Format(backupTimeFormat), ext)
+	renameErr := os.Rename(f.opts.Path, backup)
+	// Writing goes on in the same path whether the rename worked or not
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return fmt.Errorf("rotate log file: %w", renameErr)
+	}
+
+	f.cleanup.Add(1)
+	go func() {
+		defer f.cleanup.Done()
+		f.compressAndPrune(backup)
+	}()
+	return nil
+}
+
+// compressAndPrune runs in the background, failures are reported on stderr
+// since the log file itself is the thing being maintained
+func (f *RotatingFile) compressAndPrune(backup string) {
+	f.maintenance.Lock()
+	defer f.maintenance.Unlock()
+
+	if f.opts.Compress {
+		if err := gzipFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "compress log file %s: %v\n", backup, err)
+		}
+	}
+	if f.opts.MaxBackups <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(f.opts.Path)
+	backups, err := filepath.Glob(strings.TrimSuffix(f.opts.Path, ext) + "-*" + ext + "*")
+	if err != nil {
+		return
+	}
+	// The timestamp format sorts chronologically
+	sort.Strings(backups)
+	for len(backups) > f.opts.MaxBackups {
+		if err = os.Remove(backups[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "remove log file %s: %v\n", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err = os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/handlers"
+	"github.com/project-weekend/qms-engine/internal/openapi"
+)
+
+const (
+	// OpenAPIPath serves the OpenAPI document, DocsPath renders it with the script at DocsBundlePath
+	OpenAPIPath    = "/openapi.json"
+	DocsPath       = "/docs"
+	DocsBundlePath = "/docs/redoc.standalone.js"
+
+	apiVersion = "1.0.0"
+)
+
+// NewOpenAPIDocument generates the OpenAPI document of the API routes
+func NewOpenAPIDocument() *openapi.Document {
+	generator := openapi.NewGenerator(openapi.Info{
+		Title:       "QMS Engine API",
+		Version:     apiVersion,
+		Description: "Test management API of the QMS engine",
+	}, map[string]string{
+		"slug":             slugPattern.String(),
+		"project_key":      projectKeyPattern.String(),
+		"tag_list":         slugPattern.String(),
+		"no_control_chars": `^\P{Cc}*$`,
+	})
+	return generator.Generate(handlers.APIRoutes())
+}
+
+// MarshalOpenAPIDocument renders the document as indented JSON, the form committed to api/openapi.json
+func MarshalOpenAPIDocument(doc *openapi.Document) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// RegisterOpenAPIRoutes serves the document and its rendering
+func RegisterOpenAPIRoutes(engine *gin.Engine) error {
+	document, err := MarshalOpenAPIDocument(NewOpenAPIDocument())
+	if err != nil {
+		return err
+	}
+	engine.GET(OpenAPIPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", document)
+	})
+	engine.GET(DocsPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+	})
+	engine.GET(DocsBundlePath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/javascript; charset=utf-8", openapi.RedocBundle)
+	})
+	return nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/handlers"
+	"github.com/project-weekend/qms-engine/internal/openapi"
+)
+
+// apiPathPrefix holds the routes that must be documented
+const apiPathPrefix = "/api/"
+
+// TestOpenAPIRoutesMatchRegistered fails when a route is registered without being documented,
+// or documented without being registered
+func TestOpenAPIRoutesMatchRegistered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	pass := func(*gin.Context) {}
+	routeConfig := handlers.RouteConfig{
+		AppEngine:        engine,
+		Limits:           func(string) gin.HandlerFunc { return pass },
+		Contract:         pass,
+		Idempotency:      pass,
+		QMSEngineService: &handlers.QMSEngineService{},
+	}
+	routeConfig.RegisterRoutes()
+
+	var registered []string
+	for _, route := range engine.Routes() {
+		if strings.HasPrefix(route.Path, apiPathPrefix) {
+			registered = append(registered, route.Method+" "+route.Path)
+		}
+	}
+	if err := openapi.Drift(handlers.APIRoutes(), registered); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestOpenAPIDocumentCommitted fails when api/openapi.json was not regenerated after a change
+func TestOpenAPIDocumentCommitted(t *testing.T) {
+	committed, err := os.ReadFile("../../api/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := MarshalOpenAPIDocument(NewOpenAPIDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(committed, generated) {
+		t.Error("api/openapi.json is out of date, run make openapi")
+	}
+}
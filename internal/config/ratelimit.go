@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/ratelimit"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+const (
+	// APIKeyHeader carries the API key of CI uploaders and other machine clients
+	APIKeyHeader = "X-API-Key"
+	// DefaultRouteGroup holds the limits of routes outside any other group
+	DefaultRouteGroup = "default"
+
+	rateLimitKeyPrefix = "qms:ratelimit:"
+	// rateLimitIdleBuckets is how long an unused in-memory bucket is kept
+	rateLimitIdleBuckets = 10 * time.Minute
+)
+
+// RateLimiter enforces the request rate and body size limits of the route groups.
+// The limits follow the live configuration, the store is fixed at startup
+type RateLimiter struct {
+	live   *LiveConfig
+	store  ratelimit.Store
+	logger *slog.Logger
+}
+
+// NewRateLimiter creates the limiter with the configured store
+func NewRateLimiter(appCfg *config.Config, live *LiveConfig, lifecycle *Lifecycle, logger *slog.Logger) *RateLimiter {
+	var store ratelimit.Store
+	if appCfg.Limits.Store == "redis" {
+		client := NewRedisClient(appCfg)
+		lifecycle.OnShutdown("rate-limit-redis", func(context.Context) error {
+			return client.Close()
+		})
+		store = ratelimit.NewRedisStore(client, rateLimitKeyPrefix)
+	} else {
+		memoryStore := ratelimit.NewMemoryStore()
+		lifecycle.Go("rate-limit-sweeper", func(ctx context.Context) {
+			memoryStore.Run(ctx, rateLimitIdleBuckets)
+		})
+		store = memoryStore
+	}
+	return &RateLimiter{live: live, store: store, logger: logger}
+}
+
+// Limit returns the middleware enforcing the limits of group. It runs before the handlers
+// bind the body, so oversized bodies are refused before they are read
+func (l *RateLimiter) Limit(group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limits := l.limits(group)
+		if limits.RequestsPerMinute > 0 && !l.allow(c, group, limits) {
+			return
+		}
+
+		if limits.MaxBodySizeInKB > 0 {
+			maxBytes := int64(limits.MaxBodySizeInKB) * 1024
+			if c.Request.ContentLength > maxBytes {
+				abortWithError(c, common.ErrCode_PayloadTooLarge, "", "")
+				return
+			}
+			// Chunked bodies have no length up front, reading past the limit fails the bind
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		}
+		c.Next()
+	}
+}
+
+func (l *RateLimiter) limits(group string) config.RouteLimits {
+	groups := l.live.Current().Limits.Groups
+	if limits, ok := groups[group]; ok {
+		return limits
+	}
+	return groups[DefaultRouteGroup]
+}
+
+// allow takes a token for the client and sets the RateLimit headers, aborting with
+// 429 when the client is out of tokens. Requests are let through when the store fails
+func (l *RateLimiter) allow(c *gin.Context, group string, limits config.RouteLimits) bool {
+	limit := ratelimit.PerMinute(limits.RequestsPerMinute, limits.Burst)
+	result, err := l.store.Take(c.Request.Context(), group+":"+clientKey(c), limit)
+	if err != nil {
+		l.logger.WarnContext(c, "Rate limit store failed, request let through", "group", group, "error", err)
+		return true
+	}
+
+	header := c.Writer.Header()
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=60;burst=%d", limits.RequestsPerMinute, limit.Burst))
+	header.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	if result.Allowed {
+		return true
+	}
+
+	header.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	abortWithError(c, common.ErrCode_TooManyRequests, "", "")
+	return false
+}
+
+// clientKey identifies the client by the principal AuthMiddleware verified, anonymous clients by IP.
+// Headers are never used as they are, a client could pick a new key for every request
+func clientKey(c *gin.Context) string {
+	if principal := common.Principal(c); principal != "" {
+		return principal
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
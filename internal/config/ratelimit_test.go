@@ -0,0 +1,178 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/ratelimit"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// fakeStore answers every take with result and err, keys records the buckets taken from
+type fakeStore struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (s *fakeStore) Take(_ context.Context, key string, _ ratelimit.Limit) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	return s.result, s.err
+}
+
+var testRouteLimits = map[string]config.RouteLimits{
+	DefaultRouteGroup: {RequestsPerMinute: 60, Burst: 5},
+	"uploads":         {RequestsPerMinute: 6},
+	"unlimited":       {},
+}
+
+// limited serves GET / behind the limits of group, principal is set as AuthMiddleware would
+func limited(store ratelimit.Store, group, principal string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	limiter := &RateLimiter{
+		live:   NewLiveConfig(&config.Config{Limits: config.LimitsConfig{Groups: testRouteLimits}}),
+		store:  store,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	engine := gin.New()
+	engine.Use(ErrorMiddleware(), func(c *gin.Context) {
+		if principal != "" {
+			c.Set(common.PrincipalKey, principal)
+		}
+	})
+	engine.GET("/", limiter.Limit(group), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return engine
+}
+
+var rateLimitHeaders = []string{"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		group       string
+		store       *fakeStore
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "allowed requests tell what is left",
+			group:      DefaultRouteGroup,
+			store:      &fakeStore{result: ratelimit.Result{Allowed: true, Remaining: 4, Reset: 1500 * time.Millisecond}},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"RateLimit-Policy": "60;w=60;burst=5", "RateLimit-Limit": "5",
+				"RateLimit-Remaining": "4", "RateLimit-Reset": "2",
+			},
+		},
+		{
+			name:       "refused requests tell when to retry",
+			group:      DefaultRouteGroup,
+			store:      &fakeStore{result: ratelimit.Result{RetryAfter: 200 * time.Millisecond, Reset: 5 * time.Second}},
+			wantStatus: http.StatusTooManyRequests,
+			wantHeaders: map[string]string{
+				"RateLimit-Policy": "60;w=60;burst=5", "RateLimit-Limit": "5",
+				"RateLimit-Remaining": "0", "RateLimit-Reset": "5", "Retry-After": "1",
+			},
+		},
+		{
+			name:       "the burst defaults to the rate",
+			group:      "uploads",
+			store:      &fakeStore{result: ratelimit.Result{Allowed: true, Remaining: 5, Reset: 10 * time.Second}},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"RateLimit-Policy": "6;w=60;burst=6", "RateLimit-Limit": "6",
+				"RateLimit-Remaining": "5", "RateLimit-Reset": "10",
+			},
+		},
+		{
+			name:        "requests are let through without headers when the store fails",
+			group:       DefaultRouteGroup,
+			store:       &fakeStore{err: errors.New("connection refused")},
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{},
+		},
+		{
+			name:        "groups without a rate are not limited",
+			group:       "unlimited",
+			store:       &fakeStore{},
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			limited(tt.store, tt.group, "").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			headers := map[string]string{}
+			for _, name := range rateLimitHeaders {
+				if value := recorder.Header().Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+			if !reflect.DeepEqual(headers, tt.wantHeaders) {
+				t.Errorf("headers = %v, want %v", headers, tt.wantHeaders)
+			}
+		})
+	}
+}
+
+func TestRateLimiterKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     string
+		principal string
+		apiKey    string
+		wantKey   string
+	}{
+		{name: "authenticated clients are limited by principal", group: DefaultRouteGroup, principal: "key:ci", wantKey: "default:key:ci"},
+		{name: "certificates are principals too", group: DefaultRouteGroup, principal: "cert:runner-1", wantKey: "default:cert:runner-1"},
+		{name: "anonymous clients are limited by IP", group: DefaultRouteGroup, wantKey: "default:ip:192.0.2.1"},
+		{name: "an unverified API key does not pick the bucket", group: DefaultRouteGroup, apiKey: "made-up", wantKey: "default:ip:192.0.2.1"},
+		{name: "every group has its own buckets", group: "uploads", principal: "key:ci", wantKey: "uploads:key:ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{result: ratelimit.Result{Allowed: true}}
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				request.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			limited(store, tt.group, tt.principal).ServeHTTP(httptest.NewRecorder(), request)
+			if !reflect.DeepEqual(store.keys, []string{tt.wantKey}) {
+				t.Errorf("keys = %v, want [%s]", store.keys, tt.wantKey)
+			}
+		})
+	}
+}
+
+// TestRateLimiterMemoryStore runs the middleware against the in-process store, a client
+// spending its burst does not spend the tokens of another
+func TestRateLimiterMemoryStore(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	statuses := map[string][]int{}
+	for _, principal := range []string{"key:ci", "key:ci", "key:ci", "key:ci", "key:ci", "key:ci", "key:other"} {
+		recorder := httptest.NewRecorder()
+		limited(store, DefaultRouteGroup, principal).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		statuses[principal] = append(statuses[principal], recorder.Code)
+	}
+	want := map[string][]int{
+		"key:ci":    {200, 200, 200, 200, 200, 429},
+		"key:other": {200},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// NewRedisClient creates a Redis client from redisConfig, connections are opened on first use
+func NewRedisClient(appCfg *config.Config) *redis.Client {
+	redisCfg := appCfg.RedisConfig
+	opts := &redis.Options{
+		Addr:            redisCfg.Addr,
+		PoolSize:        redisCfg.PoolSize,
+		ConnMaxIdleTime: time.Duration(redisCfg.IdleTimeoutInSec) * time.Second,
+		ReadTimeout:     time.Duration(redisCfg.ReadTimeoutInSec) * time.Second,
+		WriteTimeout:    time.Duration(redisCfg.WriteTimeoutInSec) * time.Second,
+	}
+	if redisCfg.TLSEnabled {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return redis.NewClient(opts)
+}
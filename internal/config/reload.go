@@ -0,0 +1,276 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+const (
+	// reloadDebounce groups the burst of events an editor or a ConfigMap update produces
+	reloadDebounce = 500 * time.Millisecond
+	// auditTimeout bounds writing the audit record of a reload
+	auditTimeout = 5 * time.Second
+)
+
+// LiveConfig holds the configuration currently in effect. Components that support runtime
+// changes subscribe to it, everything else keeps using the config it was created with
+type LiveConfig struct {
+	mu          sync.Mutex
+	current     atomic.Pointer[config.Config]
+	subscribers []configSubscriber
+}
+
+type configSubscriber struct {
+	name  string
+	apply func(conf *config.Config) error
+}
+
+// NewLiveConfig creates a LiveConfig starting with conf
+func NewLiveConfig(conf *config.Config) *LiveConfig {
+	live := &LiveConfig{}
+	live.current.Store(conf)
+	return live
+}
+
+// Current returns the configuration in effect, it must not be modified
+func (l *LiveConfig) Current() *config.Config {
+	return l.current.Load()
+}
+
+// QualityGate returns the thresholds of the quality gate in effect
+func (l *LiveConfig) QualityGate() *model.EvaluateGateRequest {
+	gate := l.Current().QualityGate
+	return &model.EvaluateGateRequest{MinPassRate: &gate.MinPassRate, MaxFailed: gate.MaxFailed}
+}
+
+// Subscribe registers apply to be called with every new configuration
+func (l *LiveConfig) Subscribe(name string, apply func(conf *config.Config) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, configSubscriber{name: name, apply: apply})
+}
+
+// Update swaps in next and notifies the subscribers. When one of them rejects it,
+// the previous configuration is restored and re-applied to the subscribers already notified
+func (l *LiveConfig) Update(next *config.Config) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	prev := l.current.Swap(next)
+	for i, sub := range l.subscribers {
+		if err := sub.apply(next); err != nil {
+			l.current.Store(prev)
+			var rollbackErrs []error
+			for j := i; j >= 0; j-- {
+				if rollbackErr := l.subscribers[j].apply(prev); rollbackErr != nil {
+					rollbackErrs = append(rollbackErrs, rollbackErr)
+				}
+			}
+			return errors.Join(fmt.Errorf("%s rejected the configuration: %w", sub.name, err), errors.Join(rollbackErrs...))
+		}
+	}
+	return nil
+}
+
+// ConfigReloader reloads the configuration when a config file changes or on SIGHUP. Every reload
+// changing the configuration is recorded in the config_reloads table
+type ConfigReloader struct {
+	opts     ConfigOptions
+	live     *LiveConfig
+	db       *sqlx.DB
+	audit    *mysql.ConfigReloadRepository
+	instance string
+	logger   *slog.Logger
+	// mu serializes reloads triggered by files and signals
+	mu sync.Mutex
+}
+
+// NewConfigReloader creates a reloader loading the configuration the same way LoadConfig does
+func NewConfigReloader(opts ConfigOptions, live *LiveConfig, db *sqlx.DB, logger *slog.Logger) *ConfigReloader {
+	instance, _ := os.Hostname()
+	return &ConfigReloader{
+		opts:     opts,
+		live:     live,
+		db:       db,
+		audit:    mysql.NewConfigReloadRepository(logger),
+		instance: instance,
+		logger:   logger,
+	}
+}
+
+// Run watches the config directory and SIGHUP until ctx is done
+func (r *ConfigReloader) Run(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var fileEvents <-chan fsnotify.Event
+	watcher, err := r.watch()
+	if err != nil {
+		r.logger.Error("Failed to watch config files, reload with SIGHUP only", "error", err)
+	} else {
+		defer watcher.Close()
+		fileEvents = watcher.Events
+	}
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			_ = r.Reload("SIGHUP")
+		case event := <-fileEvents:
+			if strings.HasSuffix(event.Name, ".json") || strings.Contains(event.Name, "..data") {
+				debounce.Reset(reloadDebounce)
+			}
+		case <-debounce.C:
+			_ = r.Reload("file change")
+		}
+	}
+}
+
+// watch watches the directory of the base config file, which also holds the overlay files.
+// Watching the directory rather than the files survives editors and Kubernetes replacing them
+func (r *ConfigReloader) watch() (*fsnotify.Watcher, error) {
+	v, _, err := NewViper(r.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err = watcher.Add(filepath.Dir(v.ConfigFileUsed())); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// Reload loads and validates the configuration, then applies the reloadable keys that changed.
+// Changes to other keys are reported and left for the next restart
+func (r *ConfigReloader) Reload(trigger string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reload := &entity.ConfigReload{Instance: r.instance, Trigger: trigger}
+	loaded, err := LoadConfig(r.opts)
+	if err != nil {
+		r.logger.Error("Configuration reload rejected", "trigger", trigger, "error", err)
+		reload.Outcome, reload.Error = entity.ConfigReloadRejected, err.Error()
+		r.record(reload)
+		return err
+	}
+
+	current := r.live.Current()
+	next, changes, restartKeys := mergeReloadable(current, loaded)
+	reload.Changes, reload.RestartKeys = changes, restartKeys
+	if len(restartKeys) > 0 {
+		r.logger.Warn("Configuration changes need a restart to take effect", "trigger", trigger, "keys", restartKeys)
+	}
+	if len(changes) == 0 {
+		if len(restartKeys) > 0 {
+			reload.Outcome = entity.ConfigReloadApplied
+			r.record(reload)
+		}
+		return nil
+	}
+
+	if err = r.live.Update(next); err != nil {
+		r.logger.Error("Configuration reload rolled back", "trigger", trigger, "changes", changes, "error", err)
+		reload.Outcome, reload.Error = entity.ConfigReloadRolledBack, err.Error()
+		r.record(reload)
+		return err
+	}
+	r.logger.Info("Configuration reloaded", "trigger", trigger, "changes", changes)
+	reload.Outcome = entity.ConfigReloadApplied
+	r.record(reload)
+	return nil
+}
+
+// record writes the audit record of a reload. A failed write is logged, the reload stands
+func (r *ConfigReloader) record(reload *entity.ConfigReload) {
+	ctx, cancel := context.WithTimeout(context.Background(), auditTimeout)
+	defer cancel()
+
+	err := func() error {
+		tx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if _, err = r.audit.Save(tx, reload); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		r.logger.Error("Failed to record configuration reload", "trigger", reload.Trigger,
+			"outcome", reload.Outcome, "error", err)
+	}
+}
+
+// mergeReloadable returns a copy of current with the reloadable keys of loaded, a description
+// of every applied change and the changed keys that cannot be reloaded
+func mergeReloadable(current, loaded *config.Config) (*config.Config, []string, []string) {
+	next := *current
+	nextValue := reflect.ValueOf(&next).Elem()
+	currentValue := reflect.ValueOf(current).Elem()
+	loadedValue := reflect.ValueOf(loaded).Elem()
+	sensitive := newRedactKeys(current.Logger.RedactKeys)
+
+	var changes, restartKeys []string
+	for _, key := range appConfigKeys {
+		oldField := configField(currentValue, key.path)
+		newField := configField(loadedValue, key.path)
+		if reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			continue
+		}
+		if !key.reloadable {
+			restartKeys = append(restartKeys, key.path)
+			continue
+		}
+
+		configField(nextValue, key.path).Set(newField)
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", key.path,
+			sensitive.redactValue(key.path, fmt.Sprint(oldField.Interface())),
+			sensitive.redactValue(key.path, fmt.Sprint(newField.Interface()))))
+	}
+	return &next, changes, restartKeys
+}
+
+// configField walks a config struct along a dotted json path
+func configField(v reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if jsonTagName(t.Field(i)) == name {
+				v = v.Field(i)
+				break
+			}
+		}
+	}
+	return v
+}
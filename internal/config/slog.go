@@ -2,35 +2,167 @@ package config
 
 import (
 	"context"
+	"encoding"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/project-weekend/qms-engine/server/config"
 )
 
-func NewLogger(appCfg *config.Config) *slog.Logger {
-	level := mapLogLevel(appCfg.Logger.LogLevel)
+// NewLogger creates the service logger. The returned LogControl changes its level and format at runtime
+func NewLogger(appCfg *config.Config) (*slog.Logger, *LogControl) {
+	output, outputErr := newLogOutput(appCfg.Logger)
+
+	control := &LogControl{
+		level:  &slog.LevelVar{},
+		output: output,
+		format: appCfg.Logger.LogFormat,
+	}
+	control.level.Set(mapLogLevel(appCfg.Logger.LogLevel))
+	control.handler = NewSwitchHandler(control.newFormatHandler(appCfg.Logger.LogFormat))
+
+	var handler slog.Handler = control.handler
+	if closer, ok := output.(io.Closer); ok && output != os.Stdout {
+		handler = &sinkHandler{Handler: handler, closer: closer}
+	}
+	handler = NewRedactHandler(handler, appCfg.Logger.RedactKeys)
+
+	asyncOpts := AsyncHandlerOptions{
+		BufferSize:     appCfg.Logger.BufferSize,
+		OverflowPolicy: appCfg.Logger.OverflowPolicy,
+	}
+	if appCfg.Logger.StacktraceLevel > 0 {
+		asyncOpts.StacktraceLevel = mapLogLevel(appCfg.Logger.StacktraceLevel)
+	}
+	handler = NewAsyncHandler(handler, asyncOpts)
+
+	if sampling := appCfg.Logger.Sampling; sampling.First > 0 {
+		handler = NewSamplingHandler(handler, SamplingOptions{
+			Window:     time.Duration(sampling.WindowInMs) * time.Millisecond,
+			First:      uint64(sampling.First),
+			Thereafter: uint64(sampling.Thereafter),
+		})
+	}
+
+	logger := slog.New(handler)
+	if outputErr != nil {
+		logger.Error("Failed to open log file, logging to stdout", "error", outputErr)
+	}
+	return logger, control
+}
+
+// LogControl changes the level and format of a logger created by NewLogger
+type LogControl struct {
+	mu      sync.Mutex
+	level   *slog.LevelVar
+	output  io.Writer
+	format  string
+	handler *SwitchHandler
+}
+
+// Apply switches to the level and format of logCfg, the other logger settings need a restart
+func (c *LogControl) Apply(logCfg config.Logger) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.level.Set(mapLogLevel(logCfg.LogLevel))
+	if logCfg.LogFormat != c.format {
+		c.handler.Swap(c.newFormatHandler(logCfg.LogFormat))
+		c.format = logCfg.LogFormat
+	}
+	return nil
+}
+
+// newFormatHandler creates the handler writing the given format to the log output
+func (c *LogControl) newFormatHandler(format string) slog.Handler {
 	opts := &slog.HandlerOptions{
-		Level: level,
+		Level: c.level,
 	}
 
-	var handler slog.Handler
 	// Read format from config and create appropriate handler
-	format := appCfg.Logger.LogFormat
 	switch {
 	case format == "json":
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		return slog.NewJSONHandler(c.output, opts)
+	case format == "logfmt":
+		return NewCGLSHandler(c.output, &CGLSOptions{HandlerOptions: *opts, Logfmt: true})
+	case format == "cglsdebug":
+		// Colors only make sense on a terminal, never in a log file
+		opts.AddSource = true
+		return NewCGLSHandler(c.output, &CGLSOptions{HandlerOptions: *opts, Color: c.output == os.Stdout})
 	case strings.HasPrefix(format, "cgls"):
-		handler = NewCGLSHandler(os.Stdout, opts)
+		return NewCGLSHandler(c.output, &CGLSOptions{HandlerOptions: *opts})
 	default:
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		return slog.NewTextHandler(c.output, opts)
+	}
+}
+
+// newLogOutput returns the writer selected by logger.output, falling back to stdout
+// when the log file cannot be opened
+func newLogOutput(logCfg config.Logger) (io.Writer, error) {
+	if logCfg.Output != "file" && logCfg.Output != "both" {
+		return os.Stdout, nil
+	}
+
+	file, err := NewRotatingFile(RotatingFileOptions{
+		Path:       logCfg.File.Path,
+		MaxSize:    int64(logCfg.File.MaxSizeInMB) * megabyte,
+		MaxAge:     time.Duration(logCfg.File.MaxAgeInHours) * time.Hour,
+		MaxBackups: logCfg.File.MaxBackups,
+		Compress:   logCfg.File.Compress,
+	})
+	if err != nil {
+		return os.Stdout, err
+	}
+
+	if logCfg.Output == "both" {
+		return &multiWriteCloser{Writer: io.MultiWriter(os.Stdout, file), closer: file}, nil
 	}
+	return file, nil
+}
+
+// FlushLogger drains buffered log records and closes the log file,
+// it should be called right before the process exits
+func FlushLogger(ctx context.Context, logger *slog.Logger) error {
+	return closeHandler(ctx, logger.Handler())
+}
+
+// closeHandler closes h if it buffers records or owns an output
+func closeHandler(ctx context.Context, h slog.Handler) error {
+	if c, ok := h.(interface{ Close(context.Context) error }); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// sinkHandler ties the lifetime of a log output to the handler writing to it
+type sinkHandler struct {
+	slog.Handler
+	closer io.Closer
+}
+
+// Close closes the log output
+func (h *sinkHandler) Close(_ context.Context) error {
+	return h.closer.Close()
+}
+
+type multiWriteCloser struct {
+	io.Writer
+	closer io.Closer
+}
 
-	return slog.New(handler)
+func (w *multiWriteCloser) Close() error {
+	return w.closer.Close()
 }
 
 func mapLogLevel(logLevel int) slog.Level {
@@ -46,21 +178,35 @@ func mapLogLevel(logLevel int) slog.Level {
 	}
 }
 
+// CGLSOptions configures a CGLSHandler
+type CGLSOptions struct {
+	slog.HandlerOptions
+	// Color renders the level with ANSI colors
+	Color bool
+	// Logfmt writes plain logfmt (time=... level=... msg=...) instead of the bracketed CGLS layout
+	Logfmt bool
+}
+
 // CGLSHandler is a custom slog handler that formats logs in CGLS format
 type CGLSHandler struct {
-	opts  *slog.HandlerOptions
-	w     io.Writer
-	attrs []slog.Attr
-	group string
+	opts *CGLSOptions
+	mu   *sync.Mutex
+	w    io.Writer
+	// preformatted holds the attributes added by WithAttrs, already rendered
+	preformatted []byte
+	// groups are the groups opened by WithGroup, prefix is their dotted form
+	groups []string
+	prefix string
 }
 
 // NewCGLSHandler creates a new CGLS format handler
-func NewCGLSHandler(w io.Writer, opts *slog.HandlerOptions) *CGLSHandler {
+func NewCGLSHandler(w io.Writer, opts *CGLSOptions) *CGLSHandler {
 	if opts == nil {
-		opts = &slog.HandlerOptions{}
+		opts = &CGLSOptions{}
 	}
 	return &CGLSHandler{
 		opts: opts,
+		mu:   &sync.Mutex{},
 		w:    w,
 	}
 }
@@ -79,116 +225,230 @@ func (h *CGLSHandler) Handle(_ context.Context, r slog.Record) error {
 	buf := make([]byte, 0, 1024)
 
 	// Format: [YYYY-MM-DD HH:MM:SS.mmm] [LEVEL] [file:line] message key=value key=value
-
-	// Timestamp
-	timestamp := r.Time.Format("2006-01-02 15:04:05.000")
-	buf = fmt.Appendf(buf, "[%s] ", timestamp)
-
-	// Level with color coding (optional)
-	levelStr := h.formatLevel(r.Level)
-	buf = fmt.Appendf(buf, "[%s] ", levelStr)
-
-	// Source location
-	if h.opts.AddSource {
+	// or, in logfmt mode: time=... level=... source=file:line msg=... key=value
+	if !r.Time.IsZero() {
+		buf = h.appendBuiltin(buf, slog.Time(slog.TimeKey, r.Time))
+	}
+	buf = h.appendBuiltin(buf, slog.Any(slog.LevelKey, r.Level))
+	if h.opts.AddSource && r.PC != 0 {
 		fs := runtime.CallersFrames([]uintptr{r.PC})
 		f, _ := fs.Next()
 		if f.File != "" {
-			// Get just the filename, not the full path
-			file := f.File
-			if idx := strings.LastIndex(file, "/"); idx >= 0 {
-				file = file[idx+1:]
-			}
-			buf = fmt.Appendf(buf, "[%s:%d] ", file, f.Line)
+			buf = h.appendBuiltin(buf, slog.Any(slog.SourceKey, &slog.Source{Function: f.Function, File: f.File, Line: f.Line}))
 		}
 	}
-
-	// Message
-	buf = fmt.Appendf(buf, "%s", r.Message)
+	buf = h.appendBuiltin(buf, slog.String(slog.MessageKey, r.Message))
 
 	// Add handler's attributes
-	for _, attr := range h.attrs {
-		buf = h.appendAttr(buf, attr)
-	}
+	buf = append(buf, h.preformatted...)
 
 	// Add record's attributes
 	r.Attrs(func(attr slog.Attr) bool {
-		buf = h.appendAttr(buf, attr)
+		buf = h.appendAttr(buf, attr, h.prefix, h.groups)
 		return true
 	})
 
 	buf = append(buf, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	_, err := h.w.Write(buf)
 	return err
 }
 
+// appendBuiltin appends the time, level, source or message after applying ReplaceAttr
+func (h *CGLSHandler) appendBuiltin(buf []byte, attr slog.Attr) []byte {
+	key := attr.Key
+	if h.opts.ReplaceAttr != nil {
+		attr = h.opts.ReplaceAttr(nil, attr)
+		attr.Value = attr.Value.Resolve()
+		if attr.Key == "" {
+			return buf
+		}
+	}
+
+	if h.opts.Logfmt {
+		if len(buf) > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = appendQuoted(buf, attr.Key)
+		buf = append(buf, '=')
+		return appendQuoted(buf, h.formatBuiltin(key, attr.Value))
+	}
+
+	if key == slog.MessageKey {
+		return append(buf, h.formatBuiltin(key, attr.Value)...)
+	}
+	return fmt.Appendf(buf, "[%s] ", h.formatBuiltin(key, attr.Value))
+}
+
+// formatBuiltin renders a built-in value, falling back to the generic rendering
+// when ReplaceAttr changed its type
+func (h *CGLSHandler) formatBuiltin(key string, value slog.Value) string {
+	switch key {
+	case slog.TimeKey:
+		if value.Kind() == slog.KindTime {
+			if h.opts.Logfmt {
+				return value.Time().Format(time.RFC3339Nano)
+			}
+			return value.Time().Format("2006-01-02 15:04:05.000")
+		}
+	case slog.LevelKey:
+		if level, ok := value.Any().(slog.Level); ok {
+			return h.formatLevel(level)
+		}
+	case slog.SourceKey:
+		if src, ok := value.Any().(*slog.Source); ok {
+			// Get just the filename, not the full path
+			return fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line)
+		}
+	}
+	return formatValue(value)
+}
+
 // formatLevel returns a formatted level string
 func (h *CGLSHandler) formatLevel(level slog.Level) string {
+	var str, color string
 	switch level {
 	case slog.LevelDebug:
-		return "DEBUG"
+		str, color = "DEBUG", ansiCyan
 	case slog.LevelInfo:
-		return "INFO "
+		str, color = "INFO ", ansiGreen
 	case slog.LevelWarn:
-		return "WARN "
+		str, color = "WARN ", ansiYellow
 	case slog.LevelError:
-		return "ERROR"
+		str, color = "ERROR", ansiRed
 	default:
-		return level.String()
+		str, color = level.String(), ansiMagenta
 	}
+
+	if h.opts.Logfmt {
+		str = strings.TrimSpace(str)
+	}
+	if h.opts.Color {
+		return color + str + ansiReset
+	}
+	return str
 }
 
-// appendAttr appends a formatted attribute to the buffer
-func (h *CGLSHandler) appendAttr(buf []byte, attr slog.Attr) []byte {
+const (
+	ansiReset   = "\x1b[0m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// appendAttr appends a formatted attribute to the buffer, flattening groups into dotted keys
+func (h *CGLSHandler) appendAttr(buf []byte, attr slog.Attr, prefix string, groups []string) []byte {
+	attr.Value = attr.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
 	if attr.Equal(slog.Attr{}) {
 		return buf
 	}
 
-	key := attr.Key
-	if h.group != "" {
-		key = h.group + "." + key
+	if attr.Value.Kind() == slog.KindGroup {
+		members := attr.Value.Group()
+		if len(members) == 0 {
+			return buf
+		}
+		// A group with an empty key is inlined
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, member := range members {
+			buf = h.appendAttr(buf, member, prefix, groups)
+		}
+		return buf
 	}
 
 	buf = append(buf, ' ')
-	buf = append(buf, key...)
+	buf = appendQuoted(buf, prefix+attr.Key)
 	buf = append(buf, '=')
-
-	value := attr.Value.String()
-	// Quote the value if it contains spaces
-	if strings.Contains(value, " ") {
-		buf = fmt.Appendf(buf, "%q", value)
-	} else {
-		buf = append(buf, value...)
-	}
-
-	return buf
+	return appendQuoted(buf, formatValue(attr.Value))
 }
 
 // WithAttrs returns a new handler with additional attributes
 func (h *CGLSHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newHandler := &CGLSHandler{
-		opts:  h.opts,
-		w:     h.w,
-		attrs: make([]slog.Attr, 0, len(h.attrs)+len(attrs)),
-		group: h.group,
-	}
-	newHandler.attrs = append(newHandler.attrs, h.attrs...)
-	newHandler.attrs = append(newHandler.attrs, attrs...)
+	if len(attrs) == 0 {
+		return h
+	}
+	newHandler := h.clone()
+	for _, attr := range attrs {
+		newHandler.preformatted = h.appendAttr(newHandler.preformatted, attr, h.prefix, h.groups)
+	}
 	return newHandler
 }
 
-// WithGroup returns a new handler with a group name
+// WithGroup returns a new handler that nests all following attributes in the group
 func (h *CGLSHandler) WithGroup(name string) slog.Handler {
 	if name == "" {
 		return h
 	}
-	newGroup := name
-	if h.group != "" {
-		newGroup = h.group + "." + name
-	}
+	newHandler := h.clone()
+	newHandler.groups = append(newHandler.groups, name)
+	newHandler.prefix = h.prefix + name + "."
+	return newHandler
+}
+
+func (h *CGLSHandler) clone() *CGLSHandler {
 	return &CGLSHandler{
-		opts:  h.opts,
-		w:     h.w,
-		attrs: h.attrs,
-		group: newGroup,
+		opts:         h.opts,
+		mu:           h.mu,
+		w:            h.w,
+		preformatted: slices.Clip(h.preformatted),
+		groups:       slices.Clip(h.groups),
+		prefix:       h.prefix,
+	}
+}
+
+// formatValue renders an attribute value as text
+func formatValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		switch a := v.Any().(type) {
+		case error:
+			return a.Error()
+		case encoding.TextMarshaler:
+			text, err := a.MarshalText()
+			if err != nil {
+				return "!ERROR:" + err.Error()
+			}
+			return string(text)
+		case []byte:
+			return string(a)
+		default:
+			return fmt.Sprintf("%+v", a)
+		}
+	default:
+		return v.String()
+	}
+}
+
+// appendQuoted appends s, quoting it when it is empty or contains spaces, '=', quotes
+// or non-printable characters
+func appendQuoted(buf []byte, s string) []byte {
+	if needsQuoting(s) {
+		return strconv.AppendQuote(buf, s)
+	}
+	return append(buf, s...)
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
 	}
+	return false
 }
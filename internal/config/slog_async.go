@@ -0,0 +1,250 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// OverflowDrop discards new records while the buffer is full
+	OverflowDrop = "drop"
+	// OverflowBlock makes the caller wait until the buffer has room
+	OverflowBlock = "block"
+
+	defaultLogBufferSize = 1024
+	maxStacktraceFrames  = 64
+)
+
+// ErrAsyncHandlerClosed is returned when a record is handled after Close
+var ErrAsyncHandlerClosed = errors.New("async log handler is closed")
+
+// AsyncHandlerOptions configures an AsyncHandler
+type AsyncHandlerOptions struct {
+	// BufferSize is the capacity of the ring buffer
+	BufferSize int
+	// OverflowPolicy is either OverflowDrop or OverflowBlock
+	OverflowPolicy string
+	// StacktraceLevel attaches a stack trace to records at or above this level, nil disables it
+	StacktraceLevel slog.Leveler
+}
+
+// AsyncHandler hands records over to a single writer goroutine through a bounded ring buffer
+// so that logging never performs I/O on the caller's goroutine. One writer keeps records in order
+type AsyncHandler struct {
+	next slog.Handler
+	core *asyncCore
+}
+
+// asyncCore is shared between an AsyncHandler and all handlers derived from it
+type asyncCore struct {
+	opts    AsyncHandlerOptions
+	ring    *recordRing
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+	closed  atomic.Bool
+	root    slog.Handler
+}
+
+type asyncEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// NewAsyncHandler wraps next and starts the writer
+func NewAsyncHandler(next slog.Handler, opts AsyncHandlerOptions) *AsyncHandler {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultLogBufferSize
+	}
+	if opts.OverflowPolicy != OverflowBlock {
+		opts.OverflowPolicy = OverflowDrop
+	}
+
+	core := &asyncCore{
+		opts: opts,
+		ring: newRecordRing(opts.BufferSize),
+		root: next,
+	}
+	core.wg.Add(1)
+	go core.work()
+
+	return &AsyncHandler{next: next, core: core}
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle enqueues a copy of the record for the writer
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.core.closed.Load() {
+		return ErrAsyncHandlerClosed
+	}
+
+	r = r.Clone()
+	if lvl := h.core.opts.StacktraceLevel; lvl != nil && r.Level >= lvl.Level() {
+		r.AddAttrs(slog.String("stacktrace", stacktrace()))
+	}
+
+	entry := asyncEntry{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.next,
+		record:  r,
+	}
+	if !h.core.ring.push(entry, h.core.opts.OverflowPolicy == OverflowBlock) {
+		h.core.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler sharing the same buffer and writer
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), core: h.core}
+}
+
+// WithGroup returns a handler sharing the same buffer and writer
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), core: h.core}
+}
+
+// Dropped returns the number of records discarded because the buffer was full
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.core.dropped.Load()
+}
+
+// Close stops accepting records and returns once every buffered record is written. ctx bounds
+// the wait, a writer stuck on its sink is left behind with ctx.Err() returned
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	if !h.core.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	h.core.ring.close()
+	drained := make(chan struct{})
+	go func() {
+		h.core.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if dropped := h.Dropped(); dropped > 0 {
+		r := slog.NewRecord(time.Now(), slog.LevelWarn, "Log records dropped due to full buffer", 0)
+		r.AddAttrs(slog.Uint64("dropped", dropped))
+		_ = h.core.root.Handle(ctx, r)
+	}
+	return closeHandler(ctx, h.core.root)
+}
+
+func (c *asyncCore) work() {
+	defer c.wg.Done()
+	for {
+		entry, ok := c.ring.pop()
+		if !ok {
+			return
+		}
+		// There is nobody to report a write error to, the record is lost either way
+		_ = entry.handler.Handle(entry.ctx, entry.record)
+	}
+}
+
+// stacktrace renders the stack of the goroutine that emitted the record,
+// leaving out the logging frames
+func stacktrace() string {
+	pcs := make([]uintptr, maxStacktraceFrames)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var all []runtime.Frame
+	lastLogFrame := -1
+	for {
+		f, more := frames.Next()
+		if strings.HasPrefix(f.Function, "log/slog.") {
+			lastLogFrame = len(all)
+		}
+		all = append(all, f)
+		if !more {
+			break
+		}
+	}
+
+	var b strings.Builder
+	for _, f := range all[lastLogFrame+1:] {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+	}
+	return b.String()
+}
+
+// recordRing is a fixed size FIFO of log entries guarded by a mutex
+type recordRing struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []asyncEntry
+	head     int
+	size     int
+	closed   bool
+}
+
+func newRecordRing(capacity int) *recordRing {
+	r := &recordRing{items: make([]asyncEntry, capacity)}
+	r.notEmpty = sync.NewCond(&r.mu)
+	r.notFull = sync.NewCond(&r.mu)
+	return r
+}
+
+// push adds an entry, waiting for room when block is set. It returns false when the entry was dropped
+func (r *recordRing) push(e asyncEntry, block bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.size == len(r.items) && block && !r.closed {
+		r.notFull.Wait()
+	}
+	if r.closed || r.size == len(r.items) {
+		return false
+	}
+
+	r.items[(r.head+r.size)%len(r.items)] = e
+	r.size++
+	r.notEmpty.Signal()
+	return true
+}
+
+// pop removes the oldest entry, waiting for one to arrive. It returns false once closed and drained
+func (r *recordRing) pop() (asyncEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.size == 0 && !r.closed {
+		r.notEmpty.Wait()
+	}
+	if r.size == 0 {
+		return asyncEntry{}, false
+	}
+
+	e := r.items[r.head]
+	r.items[r.head] = asyncEntry{}
+	r.head = (r.head + 1) % len(r.items)
+	r.size--
+	r.notFull.Signal()
+	return e, true
+}
+
+func (r *recordRing) close() {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.notEmpty.Broadcast()
+	r.notFull.Broadcast()
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSamplingKeys bounds the messages counted at once. Once reached, messages not counted yet
+// are logged unsampled until the windows of others run out
+const maxSamplingKeys = 4096
+
+// SamplingOptions configures a SamplingHandler
+type SamplingOptions struct {
+	// Window is the period after which the per-message counters reset
+	Window time.Duration
+	// First records with the same level and message are always logged within a window
+	First uint64
+	// Thereafter only every Nth record is logged, 0 drops all of them
+	Thereafter uint64
+}
+
+// SamplingHandler limits how often records with the same level and message are logged,
+// so that a failing dependency does not flood the output with identical errors
+type SamplingHandler struct {
+	next slog.Handler
+	core *samplerCore
+}
+
+type samplerCore struct {
+	opts     SamplingOptions
+	mu       sync.Mutex
+	counters map[samplingKey]*samplingCounter
+	sampled  atomic.Uint64
+}
+
+// samplingKey is what records are counted by, the exact level and message
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+type samplingCounter struct {
+	resetAt time.Time
+	count   uint64
+}
+
+// NewSamplingHandler wraps next with per-message sampling
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	if opts.Window <= 0 {
+		opts.Window = time.Second
+	}
+	return &SamplingHandler{next: next, core: &samplerCore{
+		opts:     opts,
+		counters: make(map[samplingKey]*samplingCounter),
+	}}
+}
+
+// Enabled reports whether the next handler handles records at the given level
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle forwards the record unless its message exceeded the sampling budget
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.core.allow(r.Level, r.Message, r.Time) {
+		h.core.sampled.Add(1)
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler sharing the same counters
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), core: h.core}
+}
+
+// WithGroup returns a handler sharing the same counters
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), core: h.core}
+}
+
+// Sampled returns the number of records that were not logged
+func (h *SamplingHandler) Sampled() uint64 {
+	return h.core.sampled.Load()
+}
+
+// Close closes the next handler if it owns resources
+func (h *SamplingHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+func (c *samplerCore) allow(level slog.Level, msg string, now time.Time) bool {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := samplingKey{level: level, msg: msg}
+	counter, ok := c.counters[key]
+	if !ok {
+		if len(c.counters) >= maxSamplingKeys && !c.prune(now) {
+			return true
+		}
+		counter = &samplingCounter{}
+		c.counters[key] = counter
+	}
+	if now.After(counter.resetAt) {
+		counter.resetAt = now.Add(c.opts.Window)
+		counter.count = 0
+	}
+
+	counter.count++
+	if counter.count <= c.opts.First {
+		return true
+	}
+	return c.opts.Thereafter > 0 && (counter.count-c.opts.First)%c.opts.Thereafter == 0
+}
+
+// prune forgets the messages whose window ran out, it reports whether any was
+func (c *samplerCore) prune(now time.Time) bool {
+	pruned := false
+	for key, counter := range c.counters {
+		if now.After(counter.resetAt) {
+			delete(c.counters, key)
+			pruned = true
+		}
+	}
+	return pruned
+}
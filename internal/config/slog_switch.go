@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// handlerSwitch holds the handler that a SwitchHandler and all handlers derived from it delegate to
+type handlerSwitch struct {
+	current atomic.Pointer[slog.Handler]
+}
+
+// SwitchHandler delegates to a handler that can be replaced at runtime, e.g. to change the
+// output format. Attributes and groups added through it are replayed on the new handler
+type SwitchHandler struct {
+	sw *handlerSwitch
+	// ops are the WithAttrs and WithGroup calls that derived this handler from the root
+	ops   []func(slog.Handler) slog.Handler
+	cache atomic.Pointer[derivedHandler]
+}
+
+// derivedHandler is the result of replaying ops on a given base handler
+type derivedHandler struct {
+	base    *slog.Handler
+	handler slog.Handler
+}
+
+// NewSwitchHandler creates a switchable handler delegating to h
+func NewSwitchHandler(h slog.Handler) *SwitchHandler {
+	sw := &handlerSwitch{}
+	sw.current.Store(&h)
+	return &SwitchHandler{sw: sw}
+}
+
+// Swap replaces the handler for this SwitchHandler and every handler derived from it
+func (h *SwitchHandler) Swap(next slog.Handler) {
+	h.sw.current.Store(&next)
+}
+
+// Enabled reports whether the current handler handles records at the given level
+func (h *SwitchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler().Enabled(ctx, level)
+}
+
+// Handle passes the record to the current handler
+func (h *SwitchHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+// WithAttrs returns a handler that adds attrs to whichever handler is current
+func (h *SwitchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(func(next slog.Handler) slog.Handler {
+		return next.WithAttrs(attrs)
+	})
+}
+
+// WithGroup returns a handler that opens the group on whichever handler is current
+func (h *SwitchHandler) WithGroup(name string) slog.Handler {
+	return h.derive(func(next slog.Handler) slog.Handler {
+		return next.WithGroup(name)
+	})
+}
+
+func (h *SwitchHandler) derive(op func(slog.Handler) slog.Handler) *SwitchHandler {
+	ops := make([]func(slog.Handler) slog.Handler, 0, len(h.ops)+1)
+	ops = append(ops, h.ops...)
+	return &SwitchHandler{sw: h.sw, ops: append(ops, op)}
+}
+
+// handler returns the current handler with ops applied, rebuilding it only after a swap
+func (h *SwitchHandler) handler() slog.Handler {
+	base := h.sw.current.Load()
+	if cached := h.cache.Load(); cached != nil && cached.base == base {
+		return cached.handler
+	}
+
+	handler := *base
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	h.cache.Store(&derivedHandler{base: base, handler: handler})
+	return handler
+}
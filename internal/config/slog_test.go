@@ -0,0 +1,248 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+	"time"
+)
+
+func TestCGLSHandlerLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewCGLSHandler(&buf, &CGLSOptions{Logfmt: true})
+	if err := slogtest.TestHandler(h, func() []map[string]any { return parseLines(t, &buf, parseLogfmt) }); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCGLSHandlerBracketed(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewCGLSHandler(&buf, &CGLSOptions{})
+	if err := slogtest.TestHandler(h, func() []map[string]any { return parseLines(t, &buf, parseBracketed) }); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestLoggerHandlerChain runs the handlers NewLogger stacks on top of the format handler
+func TestLoggerHandlerChain(t *testing.T) {
+	var buf *bytes.Buffer
+	var async *AsyncHandler
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		buf = &bytes.Buffer{}
+		var h slog.Handler = NewSwitchHandler(NewCGLSHandler(buf, &CGLSOptions{Logfmt: true}))
+		h = NewRedactHandler(h, nil)
+		async = NewAsyncHandler(h, AsyncHandlerOptions{BufferSize: 16, OverflowPolicy: OverflowBlock})
+		return NewSamplingHandler(async, SamplingOptions{Window: time.Minute, First: 100})
+	}, func(t *testing.T) map[string]any {
+		if err := async.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		lines := parseLines(t, buf, parseLogfmt)
+		if len(lines) != 1 {
+			t.Fatalf("want 1 line, got %d: %q", len(lines), buf.String())
+		}
+		return lines[0]
+	})
+}
+
+func parseLines(t *testing.T, buf *bytes.Buffer, parse func(string) (map[string]any, error)) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		record, err := parse(line)
+		if err != nil {
+			t.Fatalf("parse %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// parseLogfmt reads a logfmt line, dotted keys become nested groups
+func parseLogfmt(line string) (map[string]any, error) {
+	record := map[string]any{}
+	for line != "" {
+		key, rest, err := readToken(line)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(rest, "=") {
+			return nil, strconv.ErrSyntax
+		}
+		value, rest, err := readToken(rest[1:])
+		if err != nil {
+			return nil, err
+		}
+		setPath(record, key, value)
+		line = strings.TrimPrefix(rest, " ")
+	}
+	return record, nil
+}
+
+// parseBracketed reads the [time] [LEVEL] message key=value layout
+func parseBracketed(line string) (map[string]any, error) {
+	record := map[string]any{}
+	for strings.HasPrefix(line, "[") {
+		end := strings.Index(line, "] ")
+		if end < 0 {
+			return nil, strconv.ErrSyntax
+		}
+		field := strings.TrimSpace(line[1:end])
+		key := slog.TimeKey
+		var level slog.Level
+		if level.UnmarshalText([]byte(field)) == nil {
+			key = slog.LevelKey
+		}
+		record[key] = field
+		line = line[end+2:]
+	}
+
+	msg, rest, _ := strings.Cut(line, " ")
+	record[slog.MessageKey] = msg
+	attrs, err := parseLogfmt(rest)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range attrs {
+		record[key] = value
+	}
+	return record, nil
+}
+
+// readToken reads a bare or quoted key or value
+func readToken(s string) (token, rest string, err error) {
+	if strings.HasPrefix(s, `"`) {
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", "", err
+		}
+		token, err = strconv.Unquote(quoted)
+		return token, s[len(quoted):], err
+	}
+	end := strings.IndexAny(s, "= ")
+	if end < 0 {
+		end = len(s)
+	}
+	return s[:end], s[end:], nil
+}
+
+func setPath(record map[string]any, key, value string) {
+	parts := strings.Split(key, ".")
+	group := record
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := group[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			group[part] = next
+		}
+		group = next
+	}
+	group[parts[len(parts)-1]] = value
+}
+
+// recordingHandler keeps the records it handles, handling waits on release when it is set
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+	release chan struct{}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	if h.release != nil {
+		<-h.release
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func hasStacktrace(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == "stacktrace"
+		return !found
+	})
+	return found
+}
+
+func TestAsyncHandlerStacktrace(t *testing.T) {
+	tests := []struct {
+		name            string
+		stacktraceLevel int
+		level           slog.Level
+		want            bool
+	}{
+		{name: "info records have none at the error level", stacktraceLevel: 9, level: slog.LevelInfo},
+		{name: "warnings have none at the error level", stacktraceLevel: 9, level: slog.LevelWarn},
+		{name: "errors have one at the error level", stacktraceLevel: 9, level: slog.LevelError, want: true},
+		{name: "records at the warning level have one", stacktraceLevel: 7, level: slog.LevelWarn, want: true},
+		{name: "zero disables them", level: slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &recordingHandler{}
+			opts := AsyncHandlerOptions{BufferSize: 4}
+			if tt.stacktraceLevel > 0 {
+				opts.StacktraceLevel = mapLogLevel(tt.stacktraceLevel)
+			}
+			h := NewAsyncHandler(next, opts)
+			slog.New(h).Log(context.Background(), tt.level, "request served")
+			if err := h.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if len(next.records) != 1 || hasStacktrace(next.records[0]) != tt.want {
+				t.Errorf("records = %v, want one with a stack trace %v", next.records, tt.want)
+			}
+		})
+	}
+}
+
+// TestConfiguredStacktraceLevel keeps the request log lines of the base configuration free of stack traces
+func TestConfiguredStacktraceLevel(t *testing.T) {
+	content, err := os.ReadFile("../../config_files/service-config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Logger struct {
+			StacktraceLevel int `json:"stacktraceLevel"`
+		} `json:"logger"`
+	}
+	if err = json.Unmarshal(content, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if level := cfg.Logger.StacktraceLevel; level > 0 && mapLogLevel(level) < slog.LevelError {
+		t.Errorf("stacktraceLevel %d maps to %s, stack traces would be added below errors", level, mapLogLevel(level))
+	}
+}
+
+func TestAsyncHandlerCloseHonorsContext(t *testing.T) {
+	next := &recordingHandler{release: make(chan struct{})}
+	defer close(next.release)
+	h := NewAsyncHandler(next, AsyncHandlerOptions{BufferSize: 4, OverflowPolicy: OverflowBlock})
+	slog.New(h).Info("stuck on the sink")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := h.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the deadline of the context", err)
+	}
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/server/config"
+)
+
+// CertReloader serves the TLS certificate and client CAs from files, reloading them when they are rotated
+type CertReloader struct {
+	tlsCfg  config.TLSConfig
+	logger  *slog.Logger
+	current atomic.Pointer[tls.Config]
+}
+
+// NewCertReloader loads the certificate files, failing when they are unusable
+func NewCertReloader(tlsCfg config.TLSConfig, logger *slog.Logger) (*CertReloader, error) {
+	r := &CertReloader{tlsCfg: tlsCfg, logger: logger}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns the server TLS config, every handshake uses the files loaded last
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Run reloads the files when they change until ctx is done. A failed reload keeps the previous files
+func (r *CertReloader) Run(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		r.logger.Error("Failed to watch TLS files, rotated certificates need a restart", "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	for _, dir := range r.dirs() {
+		if err = watcher.Add(dir); err != nil {
+			r.logger.Error("Failed to watch TLS files, rotated certificates need a restart", "error", err)
+			return
+		}
+	}
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-watcher.Events:
+			debounce.Reset(reloadDebounce)
+		case <-debounce.C:
+			if err = r.load(); err != nil {
+				r.logger.Error("TLS certificate reload rejected", "error", err)
+				continue
+			}
+			r.logger.Info("TLS certificate reloaded", "certFile", r.tlsCfg.CertFile)
+		}
+	}
+}
+
+// dirs are the directories holding the files, watched rather than the files so that
+// replacing a file or a Kubernetes secret update is noticed
+func (r *CertReloader) dirs() []string {
+	dirs := []string{filepath.Dir(r.tlsCfg.CertFile), filepath.Dir(r.tlsCfg.KeyFile)}
+	if r.tlsCfg.ClientCAFile != "" {
+		dirs = append(dirs, filepath.Dir(r.tlsCfg.ClientCAFile))
+	}
+	slices.Sort(dirs)
+	return slices.Compact(dirs)
+}
+
+func (r *CertReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.tlsCfg.CertFile, r.tlsCfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	next := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		// The config replaces the server's own for the handshake, HTTP/2 has to be offered here
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if r.tlsCfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.tlsCfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("client CA file holds no PEM certificate")
+		}
+		// Certificates are verified whenever they are sent, ClientCertMiddleware
+		// decides which paths cannot do without one
+		next.ClientCAs = pool
+		next.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	r.current.Store(next)
+	return nil
+}
+
+// ClientCertMiddleware requires a verified client certificate on the configured path prefixes
+func ClientCertMiddleware(tlsCfg config.TLSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject, err := checkClientCert(tlsCfg, c.Request.URL.Path, c.Request.TLS)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if subject != "" {
+			c.Set(clientCertSubjectKey, subject)
+		}
+		c.Next()
+	}
+}
+
+// checkClientCert returns the subject of the client certificate when path requires one,
+// and the FORBIDDEN error when the connection of state has no allowed certificate
+func checkClientCert(tlsCfg config.TLSConfig, path string, state *tls.ConnectionState) (string, error) {
+	if !hasPathPrefix(path, tlsCfg.ClientCertPaths) {
+		return "", nil
+	}
+
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return "", common.NewServiceError(common.ErrCode_Forbidden, []common.ErrorDetail{{
+			ErrorCode: "CLIENT_CERT_REQUIRED",
+			Message:   "a client certificate signed by a trusted CA is required",
+		}})
+	}
+
+	subject := state.VerifiedChains[0][0].Subject.CommonName
+	if len(tlsCfg.ClientCertSubjects) > 0 && !slices.Contains(tlsCfg.ClientCertSubjects, subject) {
+		return "", common.NewServiceError(common.ErrCode_Forbidden, []common.ErrorDetail{{
+			ErrorCode: "CLIENT_CERT_NOT_ALLOWED",
+			Message:   fmt.Sprintf("client certificate %q is not allowed", subject),
+		}})
+	}
+	return subject, nil
+}
+
+// clientCertSubjectKey holds the common name of the verified client certificate in the gin context
+const clientCertSubjectKey = "clientCertSubject"
+
+// hasPathPrefix reports whether path is one of prefixes or below one, matching whole segments
+// so that /api/v1/runs does not cover /api/v1/runsX
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,145 @@
 package config
 
 import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	idtranslations "github.com/go-playground/validator/v10/translations/id"
+	"golang.org/x/text/language"
+
+	"github.com/project-weekend/qms-engine/internal/common"
 )
 
+var (
+	slugPattern       = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+)
+
+// maxTags bounds the tag_list rule
+const maxTags = 20
+
+// customValidations are the rules added to the validator's built-in ones
+var customValidations = map[string]validator.Func{
+	// slug is lowercase words of letters and digits joined by single hyphens, e.g. checkout-flow
+	"slug": func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	},
+	// project_key is an upper case prefix of test case IDs, e.g. QMS in QMS-42
+	"project_key": func(fl validator.FieldLevel) bool {
+		return projectKeyPattern.MatchString(fl.Field().String())
+	},
+	"no_control_chars": func(fl validator.FieldLevel) bool {
+		return !strings.ContainsFunc(fl.Field().String(), unicode.IsControl)
+	},
+	// tag_list is at most maxTags distinct slugs
+	"tag_list": func(fl validator.FieldLevel) bool {
+		tags, ok := fl.Field().Interface().([]string)
+		if !ok || len(tags) > maxTags {
+			return false
+		}
+		seen := make(map[string]struct{}, len(tags))
+		for _, tag := range tags {
+			if _, dup := seen[tag]; dup || !slugPattern.MatchString(tag) {
+				return false
+			}
+			seen[tag] = struct{}{}
+		}
+		return true
+	},
+}
+
+// customTranslations are the messages of customValidations by locale
+var customTranslations = map[string]map[string]string{
+	common.LocaleEnglish: {
+		"slug":             "{0} must be lowercase letters and digits separated by single hyphens",
+		"project_key":      "{0} must be 2 to 10 upper case letters and digits starting with a letter",
+		"no_control_chars": "{0} must not contain control characters",
+		"tag_list":         "{0} must be at most 20 distinct lowercase tags made of letters, digits and hyphens",
+	},
+	common.LocaleIndonesian: {
+		"slug":             "{0} harus berupa huruf kecil dan angka yang dipisahkan satu tanda hubung",
+		"project_key":      "{0} harus terdiri dari 2 sampai 10 huruf besar dan angka yang diawali huruf",
+		"no_control_chars": "{0} tidak boleh mengandung karakter kontrol",
+		"tag_list":         "{0} harus berisi paling banyak 20 tag unik berhuruf kecil, angka dan tanda hubung",
+	},
+}
+
+// NewValidator creates the request validator with the custom rules, errors name fields after their json key
 func NewValidator() *validator.Validate {
-	return validator.New()
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonTagName)
+	for tag, fn := range customValidations {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			// Only an empty tag or a nil func fail, neither can come from the map above
+			panic(err)
+		}
+	}
+	return validate
+}
+
+// NewTranslator registers the English and Indonesian validation messages on validate
+func NewTranslator(validate *validator.Validate) (*ut.UniversalTranslator, error) {
+	uni := ut.New(en.New(), en.New(), id.New())
+
+	enTrans, _ := uni.GetTranslator(common.LocaleEnglish)
+	if err := entranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		return nil, err
+	}
+	idTrans, _ := uni.GetTranslator(common.LocaleIndonesian)
+	if err := idtranslations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+		return nil, err
+	}
+
+	for locale, messages := range customTranslations {
+		trans, _ := uni.GetTranslator(locale)
+		for tag, message := range messages {
+			if err := registerTranslation(validate, trans, tag, message); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return uni, nil
+}
+
+func registerTranslation(validate *validator.Validate, trans ut.Translator, tag, message string) error {
+	return validate.RegisterTranslation(tag, trans,
+		func(trans ut.Translator) error {
+			return trans.Add(tag, message, true)
+		},
+		func(trans ut.Translator, fieldErr validator.FieldError) string {
+			translated, err := trans.T(tag, fieldErr.Field())
+			if err != nil {
+				return fieldErr.Error()
+			}
+			return translated
+		})
+}
+
+// supportedLocales are matched against Accept-Language, the first one is the fallback
+var supportedLocales = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+// LocaleMiddleware picks the locale of the request from Accept-Language and stores it,
+// along with its validation translator, in the gin context
+func LocaleMiddleware(uni *ut.UniversalTranslator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := common.DefaultLocale
+		if accepted, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language")); err == nil && len(accepted) > 0 {
+			tag, _, _ := supportedLocales.Match(accepted...)
+			base, _ := tag.Base()
+			locale = base.String()
+		}
+
+		trans, _ := uni.GetTranslator(locale)
+		c.Set(common.LocaleKey, locale)
+		c.Set(common.TranslatorKey, trans)
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
 }
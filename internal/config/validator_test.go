@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+func TestCustomValidations(t *testing.T) {
+	key := func(s string) *string { return &s }
+	var tooManyTags []string
+	for i := range maxTags + 1 {
+		tooManyTags = append(tooManyTags, "tag-"+strconv.Itoa(i))
+	}
+
+	tests := []struct {
+		name    string
+		request any
+		wantTag string
+	}{
+		{name: "project keys are upper cased", request: &model.CreateProjectRequest{Name: "checkout", Key: " qms "}},
+		{name: "projects need no key", request: &model.CreateProjectRequest{Name: "checkout"}},
+		{name: "a project key starts with a letter", request: &model.CreateProjectRequest{Name: "checkout", Key: "1QMS"}, wantTag: "project_key"},
+		{name: "a project key is at most 10 characters", request: &model.CreateProjectRequest{Name: "checkout", Key: "QUALITYMGMT"}, wantTag: "project_key"},
+		{name: "an updated key cannot be empty", request: &model.UpdateProjectRequest{Key: key(" ")}, wantTag: "project_key"},
+		{name: "tags are lower cased", request: &model.TestCaseRow{ExternalID: "C-1", Title: "Log in", Tags: []string{"Auth", "smoke-test"}}},
+		{name: "tags are slugs", request: &model.TestCaseRow{ExternalID: "C-1", Title: "Log in", Tags: []string{"smoke test"}}, wantTag: "tag_list"},
+		{name: "tags are distinct", request: &model.TestCaseRow{ExternalID: "C-1", Title: "Log in", Tags: []string{"auth", "AUTH"}}, wantTag: "tag_list"},
+		{name: "tags are at most 48 characters", request: &model.TestCaseRow{ExternalID: "C-1", Title: "Log in", Tags: []string{strings.Repeat("a", 49)}}, wantTag: "max"},
+		{name: "a case has at most 20 tags", request: &model.TestCaseRow{ExternalID: "C-1", Title: "Log in", Tags: tooManyTags}, wantTag: "tag_list"},
+		{name: "project names must not hold control characters", request: &model.CreateProjectRequest{Name: "check\x00out"}, wantTag: "no_control_chars"},
+	}
+	validate := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := normalize.Struct(tt.request); err != nil {
+				t.Fatal(err)
+			}
+			err := validate.Struct(tt.request)
+			if tt.wantTag == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "'"+tt.wantTag+"' tag") {
+				t.Errorf("err = %v, want a failed %s rule", err, tt.wantTag)
+			}
+		})
+	}
+}
@@ -2,27 +2,273 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
 
 	"github.com/spf13/viper"
+
+	"github.com/project-weekend/qms-engine/server/config"
 )
 
-func NewViper() *viper.Viper {
-	config := viper.New()
+const (
+	configName = "service-config"
+	envPrefix  = "QMS"
+	// fileEnvSuffix marks an environment variable holding the path of a file with the value,
+	// e.g. QMS_DATABASE_PASSWORD_FILE=/run/secrets/db-password
+	fileEnvSuffix = "_FILE"
+)
+
+// ConfigOptions selects where the configuration is loaded from
+type ConfigOptions struct {
+	// File is the base config file, by default service-config.json is searched in config_files/
+	File string
+	// Env selects the overlay file service-config.<env>.json next to the base file, "production"
+	// selecting the prod one. By default it is taken from QMS_ENV or the env key of the base file
+	Env string
+}
+
+// ConfigSources maps every config key to the layer that supplied its effective value
+type ConfigSources map[string]string
 
-	config.SetConfigName("service-config")
+// NewViper reads the base config file, merges the environment overlay file on top of it and
+// applies QMS_* environment variables last. Keys missing from every layer take their default tag
+func NewViper(opts ConfigOptions) (*viper.Viper, ConfigSources, error) {
+	config := viper.New()
 	config.SetConfigType("json")
 
-	// Add multiple config paths to handle different working directories
-	config.AddConfigPath("./config_files")                   // From project root
-	config.AddConfigPath("../config_files")                  // From subdirectory
-	config.AddConfigPath("../../config_files")               // From nested subdirectory
-	config.AddConfigPath(filepath.Join(".", "config_files")) // Alternative format
+	if opts.File != "" {
+		config.SetConfigFile(opts.File)
+	} else {
+		config.SetConfigName(configName)
+		// Add multiple config paths to handle different working directories
+		config.AddConfigPath("./config_files")                   // From project root
+		config.AddConfigPath("../config_files")                  // From subdirectory
+		config.AddConfigPath("../../config_files")               // From nested subdirectory
+		config.AddConfigPath(filepath.Join(".", "config_files")) // Alternative format
+	}
 
 	err := config.ReadInConfig()
+	if err == nil {
+		err = applyLegacyKeys(config)
+	}
 	if err != nil {
-		panic(fmt.Errorf("fatal error reading config file: %w", err))
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	keys := appConfigKeys
+	sources := make(ConfigSources, len(keys))
+	applyDefaults(config, keys, sources)
+	markSources(sources, keys, config, "file "+config.ConfigFileUsed())
+
+	if err = mergeOverlay(config, keys, sources, resolveEnv(opts, config)); err != nil {
+		return nil, nil, fmt.Errorf("failed to read config overlay: %w", err)
 	}
+	if err = applyEnvOverrides(config, keys, sources); err != nil {
+		return nil, nil, fmt.Errorf("failed to read config from environment: %w", err)
+	}
+
+	return config, sources, nil
+}
 
-	return config
+func resolveEnv(opts ConfigOptions, v *viper.Viper) string {
+	if opts.Env != "" {
+		return opts.Env
+	}
+	if env := os.Getenv(envVarName("env")); env != "" {
+		return env
+	}
+	return v.GetString("env")
+}
+
+// mergeOverlay merges <base>.<env>.json when it exists
+func mergeOverlay(v *viper.Viper, keys []configKey, sources ConfigSources, env string) error {
+	if env == "" {
+		return nil
+	}
+
+	// Both names of production share the shipped service-config.prod.json
+	if env == "production" {
+		env = "prod"
+	}
+	base := v.ConfigFileUsed()
+	ext := filepath.Ext(base)
+	overlayFile := fmt.Sprintf("%s.%s%s", strings.TrimSuffix(base, ext), env, ext)
+	if _, err := os.Stat(overlayFile); os.IsNotExist(err) {
+		return nil
+	}
+
+	overlay := viper.New()
+	overlay.SetConfigFile(overlayFile)
+	overlay.SetConfigType("json")
+	if err := overlay.ReadInConfig(); err != nil {
+		return err
+	}
+	if err := applyLegacyKeys(overlay); err != nil {
+		return err
+	}
+	if err := v.MergeConfigMap(overlay.AllSettings()); err != nil {
+		return err
+	}
+
+	markSources(sources, keys, overlay, "file "+overlayFile)
+	return nil
+}
+
+// legacyKeys maps keys config files used to have to the keys now read. Viper splits dotted keys
+// into nested maps, so the dotted Kafka keys are renamed and kept as aliases
+var legacyKeys = map[string]string{
+	"kafka.bootstrap.servers": "kafka.bootstrapServers",
+	"kafka.group.id":          "kafka.groupId",
+	"kafka.auto.offset.reset": "kafka.autoOffsetReset",
+	"kafka.producer.enabled":  "kafka.producerEnabled",
+}
+
+// applyLegacyKeys copies the legacy keys of a config file to their new keys, the new key wins
+// when the file has both
+func applyLegacyKeys(v *viper.Viper) error {
+	for legacy, key := range legacyKeys {
+		if !v.InConfig(legacy) || v.InConfig(key) {
+			continue
+		}
+		segments := strings.Split(key, ".")
+		var value any = v.Get(legacy)
+		for i := len(segments) - 1; i >= 0; i-- {
+			value = map[string]any{segments[i]: value}
+		}
+		if err := v.MergeConfigMap(value.(map[string]any)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEnvOverrides sets every key that has a QMS_<KEY> or QMS_<KEY>_FILE variable,
+// the file variant wins so that mounted secrets take precedence
+func applyEnvOverrides(v *viper.Viper, keys []configKey, sources ConfigSources) error {
+	for _, key := range keys {
+		// Maps have no flat representation in a variable
+		if key.kind == reflect.Map {
+			continue
+		}
+		name := envVarName(key.path)
+		value, ok := os.LookupEnv(name)
+		source := "env " + name
+
+		if path, fileOk := os.LookupEnv(name + fileEnvSuffix); fileOk {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s%s: %w", name, fileEnvSuffix, err)
+			}
+			value, ok = strings.TrimRight(string(content), "\r\n"), true
+			source = fmt.Sprintf("env %s%s (%s)", name, fileEnvSuffix, path)
+		}
+		if !ok {
+			continue
+		}
+
+		if key.kind == reflect.Slice {
+			v.Set(key.path, splitList(value))
+		} else {
+			v.Set(key.path, value)
+		}
+		sources[key.path] = source
+	}
+	return nil
+}
+
+func applyDefaults(v *viper.Viper, keys []configKey, sources ConfigSources) {
+	for _, key := range keys {
+		if key.def == "" {
+			continue
+		}
+		if key.kind == reflect.Slice {
+			v.SetDefault(key.path, splitList(key.def))
+		} else {
+			v.SetDefault(key.path, key.def)
+		}
+		sources[key.path] = "default"
+	}
+}
+
+func markSources(sources ConfigSources, keys []configKey, v *viper.Viper, source string) {
+	for _, key := range keys {
+		if v.InConfig(key.path) {
+			sources[key.path] = source
+		}
+	}
+}
+
+// appConfigKeys are all the keys of config.Config
+var appConfigKeys = configKeys(reflect.TypeOf(config.Config{}), "", false)
+
+// configKey is a leaf of the config.Config tree, addressed by its dotted json path
+type configKey struct {
+	path       string
+	kind       reflect.Kind
+	def        string
+	reloadable bool
+}
+
+// configKeys lists the leaves of t in declaration order, a reload tag on a section applies to all its keys
+func configKeys(t reflect.Type, prefix string, reloadable bool) []configKey {
+	var keys []configKey
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		path := prefix + name
+		fieldReloadable := reloadable || field.Tag.Get("reload") == "true"
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, path+".", fieldReloadable)...)
+			continue
+		}
+		keys = append(keys, configKey{
+			path:       path,
+			kind:       field.Type.Kind(),
+			def:        field.Tag.Get("default"),
+			reloadable: fieldReloadable,
+		})
+	}
+	return keys
+}
+
+// envVarName converts a config path to its environment variable,
+// e.g. redisConfig.readTimeoutInSec becomes QMS_REDIS_CONFIG_READ_TIMEOUT_IN_SEC
+func envVarName(path string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for _, segment := range strings.Split(path, ".") {
+		b.WriteByte('_')
+		runes := []rune(segment)
+		for i, r := range runes {
+			if i > 0 && unicode.IsUpper(r) {
+				prevLower := !unicode.IsUpper(runes[i-1])
+				acronymEnd := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if prevLower || acronymEnd {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
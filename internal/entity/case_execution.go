@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// CaseExecution is the manual execution of a case in a run
+type CaseExecution struct {
+	ID         int       `json:"id" db:"id"`
+	RunID      int       `json:"run_id" db:"run_id"`
+	CaseKey    string    `json:"case_key" db:"case_key"`
+	Status     string    `json:"status" db:"status"` // empty until a result is recorded
+	Message    string    `json:"message" db:"message"`
+	DurationMs int64     `json:"duration_ms" db:"duration_ms"`
+	RecordedBy string    `json:"recorded_by" db:"recorded_by"`
+	Version    int       `json:"version" db:"version"` // number of recorded results
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (*CaseExecution) GetTableName() string {
+	return "case_executions"
+}
+
+type CaseExecutionStep struct {
+	ID          int       `json:"id" db:"id"`
+	ExecutionID int       `json:"execution_id" db:"execution_id"`
+	Step        int       `json:"step" db:"step"` // from 1
+	Status      string    `json:"status" db:"status"`
+	Note        string    `json:"note" db:"note"`
+	UpdatedBy   string    `json:"updated_by" db:"updated_by"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (*CaseExecutionStep) GetTableName() string {
+	return "case_execution_steps"
+}
+
+type RunComment struct {
+	ID        int       `json:"id" db:"id"`
+	RunID     int       `json:"run_id" db:"run_id"`
+	CaseKey   string    `json:"case_key" db:"case_key"` // empty for comments about the run
+	Author    string    `json:"author" db:"author"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func (*RunComment) GetTableName() string {
+	return "run_comments"
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// The outcomes of a configuration reload
+const (
+	ConfigReloadApplied    = "applied"
+	ConfigReloadRolledBack = "rolled_back"
+	ConfigReloadRejected   = "rejected"
+)
+
+// ConfigReload is the audit record of a configuration reload
+type ConfigReload struct {
+	ID          int       `json:"id" db:"id"`
+	Instance    string    `json:"instance" db:"instance"`
+	Trigger     string    `json:"trigger" db:"trigger_source"`
+	Outcome     string    `json:"outcome" db:"outcome"`
+	Changes     []string  `json:"changes" db:"-"` // key: old -> new, secrets redacted
+	RestartKeys []string  `json:"restart_keys" db:"-"`
+	Error       string    `json:"error" db:"error"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+func (*ConfigReload) GetTableName() string {
+	return "config_reloads"
+}
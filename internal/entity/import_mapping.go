@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// The kinds of imported items mapped to their ID in the tool they come from
+const (
+	ImportKindSuite = "suite"
+	ImportKindCase  = "case"
+	ImportKindRun   = "run"
+)
+
+// ImportMapping records what an item of another tool was imported as, re-imports update it
+type ImportMapping struct {
+	ID        int       `json:"id" db:"id"`
+	ProjectID int       `json:"project_id" db:"project_id"`
+	Source    string    `json:"source" db:"source"` // the tool
+	Kind      string    `json:"kind" db:"kind"`
+	SourceID  string    `json:"source_id" db:"source_id"` // unique in the project, source and kind
+	TargetID  int       `json:"target_id" db:"target_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func (*ImportMapping) GetTableName() string {
+	return "import_mappings"
+}
@@ -4,8 +4,10 @@ import "time"
 
 type Project struct {
 	ID          int        `json:"id" db:"id"`
-	Name        string     `json:"name" db:"name"` // unique
+	Name        string     `json:"name" db:"name"`       // unique
+	Key         *string    `json:"key" db:"project_key"` // unique when set
 	Description string     `json:"description" db:"description"`
+	Version     int        `json:"version" db:"version"` // incremented by every update
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at" db:"deleted_at"`
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type TestCase struct {
+	ID            int       `json:"id" db:"id"`
+	ProjectID     int       `json:"project_id" db:"project_id"`
+	SuiteID       *int      `json:"suite_id" db:"suite_id"`
+	ExternalID    string    `json:"external_id" db:"external_id"` // unique in the project
+	Title         string    `json:"title" db:"title"`
+	Section       string    `json:"section" db:"section"`
+	Priority      string    `json:"priority" db:"priority"`
+	Preconditions string    `json:"preconditions" db:"preconditions"`
+	Description   string    `json:"description" db:"description"`
+	Tags          string    `json:"tags" db:"tags"`                   // comma-separated
+	CustomFields  *string   `json:"custom_fields" db:"custom_fields"` // JSON object of text values by name
+	Checksum      string    `json:"checksum" db:"checksum"`           // of the imported content
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (*TestCase) GetTableName() string {
+	return "test_cases"
+}
+
+type TestSuite struct {
+	ID          int       `json:"id" db:"id"`
+	ProjectID   int       `json:"project_id" db:"project_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (*TestSuite) GetTableName() string {
+	return "test_suites"
+}
+
+type TestCaseStep struct {
+	ID       int    `json:"id" db:"id"`
+	CaseID   int    `json:"case_id" db:"case_id"`
+	Position int    `json:"position" db:"position"` // from 1
+	Action   string `json:"action" db:"action"`
+	Expected string `json:"expected" db:"expected"`
+}
+
+func (*TestCaseStep) GetTableName() string {
+	return "test_case_steps"
+}
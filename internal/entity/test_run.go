@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+const (
+	TestRunStatusOpen   = "open"
+	TestRunStatusClosed = "closed"
+)
+
+type TestRun struct {
+	ID           int        `json:"id" db:"id"`
+	ProjectID    int        `json:"project_id" db:"project_id"`
+	Name         string     `json:"name" db:"name"`
+	Status       string     `json:"status" db:"status"`               // open while results are accepted
+	LastSequence int64      `json:"last_sequence" db:"last_sequence"` // highest committed result sequence
+	ResultCount  int        `json:"result_count" db:"result_count"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	ClosedAt     *time.Time `json:"closed_at" db:"closed_at"`
+}
+
+func (*TestRun) GetTableName() string {
+	return "test_runs"
+}
+
+const (
+	TestResultStatusPassed  = "passed"
+	TestResultStatusFailed  = "failed"
+	TestResultStatusSkipped = "skipped"
+	TestResultStatusBlocked = "blocked"
+)
+
+type TestResult struct {
+	ID         int       `json:"id" db:"id"`
+	RunID      int       `json:"run_id" db:"run_id"`
+	Sequence   int64     `json:"sequence" db:"sequence"` // unique in the run
+	CaseKey    string    `json:"case_key" db:"case_key"`
+	Status     string    `json:"status" db:"status"`
+	DurationMs int64     `json:"duration_ms" db:"duration_ms"`
+	Message    string    `json:"message" db:"message"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+func (*TestResult) GetTableName() string {
+	return "test_results"
+}
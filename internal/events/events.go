@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+)
+
+const logTag = "events"
+
+// Event is a change of a test run, delivered to the subscribers of the run on every instance
+type Event struct {
+	// ID orders the events of all runs, it is assigned by the broker and travels outside the JSON
+	ID    int64           `json:"-"`
+	RunID int             `json:"runId"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Broker numbers events and delivers them to the hubs of all instances
+type Broker interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Bus publishes the events of test runs through the broker and subscribes to them on the hub
+type Bus struct {
+	hub    *Hub
+	broker Broker
+	logger *slog.Logger
+}
+
+// NewBus creates a bus publishing through broker, which must deliver to hub
+func NewBus(hub *Hub, broker Broker, logger *slog.Logger) *Bus {
+	return &Bus{hub: hub, broker: broker, logger: logger}
+}
+
+// Publish sends data as an event of eventType about run. The change it reports is already
+// stored, so a failure only costs subscribers a live update and is logged rather than returned
+func (b *Bus) Publish(ctx context.Context, runID int, eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err == nil {
+		err = b.broker.Publish(ctx, &Event{RunID: runID, Type: eventType, Data: encoded})
+	}
+	if err != nil {
+		b.logger.WarnContext(ctx, "Failed to publish run event", "tag", logTag, "error", err,
+			"runId", runID, "type", eventType)
+	}
+}
+
+// Subscribe follows the events of run after lastEventID, zero starts without a backlog
+func (b *Bus) Subscribe(runID int, lastEventID int64) *Subscription {
+	return b.hub.Subscribe(runID, lastEventID)
+}
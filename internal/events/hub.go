@@ -0,0 +1,195 @@
+package events
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Hub keeps the recent events of each run and fans new ones out to the subscribers of this instance
+type Hub struct {
+	mu          sync.Mutex
+	runs        map[int]*runEvents
+	historySize int
+	bufferSize  int
+	// floor is the ID up to which the hub may have missed events, no run can resume before it
+	floor  int64
+	closed bool
+	now    func() time.Time
+}
+
+// runEvents are the recent events and the subscribers of a run
+type runEvents struct {
+	// history holds at most historySize events, oldest first
+	history []Event
+	// since is the ID of the last event of the run that is not in history
+	since       int64
+	subscribers map[*Subscription]struct{}
+	at          time.Time
+}
+
+// Subscription follows the events of a run
+type Subscription struct {
+	// Backlog holds the events after the ID the subscriber resumed from, oldest first
+	Backlog []Event
+	// Resumed is set when Backlog holds every event after that ID. Otherwise events were
+	// missed and the subscriber has to start over from the current state of the run
+	Resumed bool
+	// Events delivers the new events. It is closed when the subscriber falls behind by more
+	// than the buffer or the hub closes, the subscriber then reconnects and resumes
+	Events <-chan Event
+
+	events chan Event
+	hub    *Hub
+	runID  int
+}
+
+// NewHub creates a hub keeping historySize events per run and buffering bufferSize events per
+// subscriber. Nothing can be resumed until the broker calls Reset
+func NewHub(historySize, bufferSize int) *Hub {
+	return &Hub{
+		runs:        make(map[int]*runEvents),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		floor:       math.MaxInt64,
+		now:         time.Now,
+	}
+}
+
+// Reset forgets the history and disconnects every subscriber, for when the broker may have lost
+// events. Events up to floor are treated as missed
+func (h *Hub) Reset(floor int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, run := range h.runs {
+		for sub := range run.subscribers {
+			close(sub.events)
+		}
+	}
+	clear(h.runs)
+	h.floor = floor
+}
+
+// Deliver adds event to the history of its run and sends it to the subscribers. A subscriber
+// with a full buffer is disconnected rather than holding up the others
+func (h *Hub) Deliver(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	run := h.run(event.RunID)
+	if n := len(run.history); n > 0 && event.ID <= run.history[n-1].ID {
+		return
+	}
+
+	run.history = append(run.history, event)
+	if len(run.history) > h.historySize {
+		run.since = run.history[0].ID
+		run.history = run.history[1:]
+	}
+	run.at = h.now()
+
+	for sub := range run.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(run.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe follows the events of run after lastEventID, zero starts without a backlog
+func (h *Hub) Subscribe(runID int, lastEventID int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make(chan Event, h.bufferSize)
+	sub := &Subscription{Events: events, events: events, hub: h, runID: runID}
+	if h.closed {
+		close(events)
+		return sub
+	}
+
+	run := h.run(runID)
+	run.subscribers[sub] = struct{}{}
+	run.at = h.now()
+	if lastEventID > 0 && lastEventID >= run.since {
+		sub.Resumed = true
+		for _, event := range run.history {
+			if event.ID > lastEventID {
+				sub.Backlog = append(sub.Backlog, event)
+			}
+		}
+	}
+	return sub
+}
+
+// Close stops following the run, closing Events if the hub has not
+func (s *Subscription) Close() {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if run, ok := h.runs[s.runID]; ok {
+		if _, ok = run.subscribers[s]; ok {
+			delete(run.subscribers, s)
+			close(s.events)
+		}
+	}
+}
+
+// Close disconnects every subscriber and refuses new ones, ending the streams so that the
+// server can drain
+func (h *Hub) Close() {
+	h.Reset(math.MaxInt64)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+}
+
+// Run drops the runs without subscribers and events for longer than idle until ctx is done
+func (h *Hub) Run(ctx context.Context, idle time.Duration) {
+	ticker := time.NewTicker(idle)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.sweep(idle)
+		}
+	}
+}
+
+func (h *Hub) sweep(idle time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := h.now().Add(-idle)
+	for runID, run := range h.runs {
+		if len(run.subscribers) > 0 || !run.at.Before(cutoff) {
+			continue
+		}
+		// A run seen again starts after the dropped events, and the floor covers them
+		if n := len(run.history); n > 0 {
+			h.floor = max(h.floor, run.history[n-1].ID)
+		}
+		delete(h.runs, runID)
+	}
+}
+
+// run returns the events of runID, tracking it from the floor on when it is new
+func (h *Hub) run(runID int) *runEvents {
+	run, ok := h.runs[runID]
+	if !ok {
+		run = &runEvents{since: h.floor, subscribers: make(map[*Subscription]struct{})}
+		h.runs[runID] = run
+	}
+	return run
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// publish sends n events about run through broker and returns their IDs
+func publish(t *testing.T, broker *MemoryBroker, runID, n int) []int64 {
+	t.Helper()
+	ids := make([]int64, 0, n)
+	for range n {
+		event := &Event{RunID: runID, Type: "result-recorded", Data: []byte(`{}`)}
+		if err := broker.Publish(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func eventIDs(events []Event) []int64 {
+	var ids []int64
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+// received reads the events of sub until Events is closed or empty, closed tells which
+func received(sub *Subscription) (ids []int64, closed bool) {
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return ids, true
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids, false
+		}
+	}
+}
+
+func TestHubResume(t *testing.T) {
+	tests := []struct {
+		name        string
+		historySize int
+		lastEventID func(ids []int64) int64
+		wantResumed bool
+		wantBacklog func(ids []int64) []int64
+	}{
+		{
+			name:        "a subscriber gets the events after its last one",
+			historySize: 10,
+			lastEventID: func(ids []int64) int64 { return ids[0] },
+			wantResumed: true,
+			wantBacklog: func(ids []int64) []int64 { return ids[1:] },
+		},
+		{
+			name:        "a subscriber that saw the last event has no backlog",
+			historySize: 10,
+			lastEventID: func(ids []int64) int64 { return ids[3] },
+			wantResumed: true,
+		},
+		{
+			name:        "the history still covers the event before the oldest kept",
+			historySize: 2,
+			lastEventID: func(ids []int64) int64 { return ids[1] },
+			wantResumed: true,
+			wantBacklog: func(ids []int64) []int64 { return ids[2:] },
+		},
+		{
+			// The stream then starts over with a run-status event
+			name:        "events trimmed from the history cannot be resumed",
+			historySize: 2,
+			lastEventID: func(ids []int64) int64 { return ids[0] },
+		},
+		{
+			name:        "events from before the broker started cannot be resumed",
+			historySize: 10,
+			lastEventID: func([]int64) int64 { return 1 },
+		},
+		{
+			name:        "a new subscriber starts without a backlog",
+			historySize: 10,
+			lastEventID: func([]int64) int64 { return 0 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(tt.historySize, 10)
+			broker := NewMemoryBroker(hub)
+			ids := publish(t, broker, 1, 4)
+			publish(t, broker, 2, 1)
+
+			sub := hub.Subscribe(1, tt.lastEventID(ids))
+			defer sub.Close()
+			var wantBacklog []int64
+			if tt.wantBacklog != nil {
+				wantBacklog = tt.wantBacklog(ids)
+			}
+			if sub.Resumed != tt.wantResumed {
+				t.Errorf("resumed = %t, want %t", sub.Resumed, tt.wantResumed)
+			}
+			if got := eventIDs(sub.Backlog); !reflect.DeepEqual(got, wantBacklog) {
+				t.Errorf("backlog = %v, want %v", got, wantBacklog)
+			}
+		})
+	}
+}
+
+// TestHubSlowSubscriber fills the buffer of one subscriber, it is disconnected while the other
+// keeps its stream and the slow one resumes where it fell behind
+func TestHubSlowSubscriber(t *testing.T) {
+	hub := NewHub(10, 2)
+	broker := NewMemoryBroker(hub)
+	slow, fast := hub.Subscribe(1, 0), hub.Subscribe(1, 0)
+	defer fast.Close()
+
+	var ids, fastIDs []int64
+	for range 3 {
+		ids = append(ids, publish(t, broker, 1, 1)...)
+		got, _ := received(fast)
+		fastIDs = append(fastIDs, got...)
+	}
+
+	slowIDs, closed := received(slow)
+	if !closed || !reflect.DeepEqual(slowIDs, ids[:2]) {
+		t.Errorf("slow subscriber got %v, closed = %t, want %v and closed", slowIDs, closed, ids[:2])
+	}
+	if _, closed = received(fast); closed || !reflect.DeepEqual(fastIDs, ids) {
+		t.Errorf("fast subscriber got %v, closed = %t, want %v and open", fastIDs, closed, ids)
+	}
+
+	again := hub.Subscribe(1, slowIDs[len(slowIDs)-1])
+	defer again.Close()
+	if !again.Resumed || !reflect.DeepEqual(eventIDs(again.Backlog), ids[2:]) {
+		t.Errorf("resumed = %t with %v, want the events after %d", again.Resumed, eventIDs(again.Backlog), slowIDs[1])
+	}
+}
+
+// TestHubReset loses events as a broker reconnecting does, subscribers start over
+func TestHubReset(t *testing.T) {
+	hub := NewHub(10, 10)
+	broker := NewMemoryBroker(hub)
+	before := publish(t, broker, 1, 2)
+	sub := hub.Subscribe(1, 0)
+
+	hub.Reset(broker.lastID)
+	if _, closed := received(sub); !closed {
+		t.Error("the subscriber was kept")
+	}
+	// Events up to the floor may be lost, only a subscriber that saw the last of them resumes
+	if again := hub.Subscribe(1, before[0]); again.Resumed || len(again.Backlog) > 0 {
+		t.Errorf("resumed = %t with %v, want to start over", again.Resumed, eventIDs(again.Backlog))
+	}
+	if again := hub.Subscribe(1, before[1]); !again.Resumed || len(again.Backlog) > 0 {
+		t.Errorf("resumed = %t with %v, want to resume without a backlog", again.Resumed, eventIDs(again.Backlog))
+	}
+
+	after := publish(t, broker, 1, 2)
+	if again := hub.Subscribe(1, after[0]); !again.Resumed || !reflect.DeepEqual(eventIDs(again.Backlog), after[1:]) {
+		t.Errorf("resumed = %t with %v, want the events after the reset", again.Resumed, eventIDs(again.Backlog))
+	}
+}
+
+// TestHubClose drains the hub, as the server does when it shuts down
+func TestHubClose(t *testing.T) {
+	hub := NewHub(10, 10)
+	broker := NewMemoryBroker(hub)
+	ids := publish(t, broker, 1, 1)
+	sub := hub.Subscribe(1, 0)
+
+	hub.Close()
+	if _, closed := received(sub); !closed {
+		t.Error("the subscriber was kept")
+	}
+	late := hub.Subscribe(1, ids[0])
+	if got, closed := received(late); !closed || late.Resumed || len(got) > 0 {
+		t.Errorf("a subscriber after the drain got %v, closed = %t, resumed = %t", got, closed, late.Resumed)
+	}
+	sub.Close()
+	late.Close()
+
+	publish(t, broker, 1, 1)
+	if len(hub.runs) > 0 {
+		t.Errorf("runs = %d, want none after the drain", len(hub.runs))
+	}
+}
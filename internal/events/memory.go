@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBroker delivers events to the hub of this instance only
+type MemoryBroker struct {
+	mu     sync.Mutex
+	hub    *Hub
+	lastID int64
+}
+
+// NewMemoryBroker creates a broker delivering to hub. IDs start at the clock in microseconds so
+// that they keep growing across restarts: a subscriber resuming from before a restart starts
+// over instead of waiting for IDs it has already seen
+func NewMemoryBroker(hub *Hub) *MemoryBroker {
+	lastID := time.Now().UnixMicro()
+	hub.Reset(lastID)
+	return &MemoryBroker{hub: hub, lastID: lastID}
+}
+
+// Publish numbers event and delivers it, events are delivered in the order of their IDs
+func (b *MemoryBroker) Publish(_ context.Context, event *Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	b.hub.Deliver(*event)
+	return nil
+}
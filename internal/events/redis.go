@@ -0,0 +1,128 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// publishScript numbers an event and publishes it in one step, so that every instance receives
+// the events in the order of their IDs. The first ID is the Redis clock in microseconds, for
+// the same reason as in NewMemoryBroker. The message is the ID, a space and the event JSON
+var publishScript = redis.NewScript(`
+local id = redis.call('INCR', KEYS[1])
+if id == 1 then
+  local t = redis.call('TIME')
+  id = tonumber(t[1]) * 1000000 + tonumber(t[2])
+  redis.call('SET', KEYS[1], string.format('%d', id))
+end
+redis.call('PUBLISH', ARGV[2], string.format('%d', id) .. ' ' .. ARGV[1])
+return id
+`)
+
+// resubscribeDelay is the pause before following the channel again after the connection failed
+const resubscribeDelay = time.Second
+
+// RedisBroker fans events out to the hubs of all instances through a Redis channel
+type RedisBroker struct {
+	client  redis.UniversalClient
+	idKey   string
+	channel string
+	hub     *Hub
+	logger  *slog.Logger
+}
+
+// NewRedisBroker creates a broker numbering events and publishing them under prefix.
+// Run delivers the events of every instance to hub
+func NewRedisBroker(client redis.UniversalClient, prefix string, hub *Hub, logger *slog.Logger) *RedisBroker {
+	return &RedisBroker{
+		client:  client,
+		idKey:   prefix + "id",
+		channel: prefix + "runs",
+		hub:     hub,
+		logger:  logger,
+	}
+}
+
+// Publish numbers event and publishes it, the hub of this instance gets it back from Run
+func (b *RedisBroker) Publish(ctx context.Context, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	id, err := publishScript.Run(ctx, b.client, []string{b.idKey}, payload, b.channel).Int64()
+	if err != nil {
+		return err
+	}
+	event.ID = id
+	return nil
+}
+
+// Run follows the channel until ctx is done, following it again when the connection fails
+func (b *RedisBroker) Run(ctx context.Context) {
+	for {
+		err := b.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.Warn("Lost the run events channel, following it again", "tag", logTag, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// follow delivers the messages of the channel to the hub until receiving fails. Messages
+// published while no connection was subscribed are lost, so the hub is reset once subscribed
+func (b *RedisBroker) follow(ctx context.Context) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	lastID, err := b.client.Get(ctx, b.idKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	b.hub.Reset(lastID)
+
+	for {
+		message, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		event, err := decodeMessage(message.Payload)
+		if err != nil {
+			b.logger.Warn("Dropped a malformed run event", "tag", logTag, "error", err)
+			continue
+		}
+		b.hub.Deliver(event)
+	}
+}
+
+// decodeMessage parses a message written by publishScript
+func decodeMessage(payload string) (Event, error) {
+	var event Event
+	id, encoded, ok := strings.Cut(payload, " ")
+	if !ok {
+		return event, errors.New("the message has no event ID")
+	}
+	var err error
+	if event.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return event, fmt.Errorf("invalid event ID: %w", err)
+	}
+	if err = json.Unmarshal([]byte(encoded), &event); err != nil {
+		return event, err
+	}
+	return event, nil
+}
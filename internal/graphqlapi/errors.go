@@ -0,0 +1,80 @@
+package graphqlapi
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+const (
+	queryTooComplex       = "QUERY_TOO_COMPLEX"
+	introspectionDisabled = "INTROSPECTION_DISABLED"
+	invalidQuery          = "INVALID_QUERY"
+)
+
+// fieldError is an error of a field or of the whole query, its code and details become
+// the extensions of the GraphQL error
+type fieldError struct {
+	code    string
+	message string
+	details []common.ErrorDetail
+}
+
+func newFieldError(code, message string) *fieldError {
+	return &fieldError{code: code, message: message}
+}
+
+// asFieldError converts a service error, other errors become INTERNAL_SERVER_ERROR without their text
+func asFieldError(err error) *fieldError {
+	var fieldErr *fieldError
+	if errors.As(err, &fieldErr) {
+		return fieldErr
+	}
+	serviceErr := common.AsServiceError(err)
+	return &fieldError{code: serviceErr.Code, message: serviceErr.Message, details: serviceErr.Errors}
+}
+
+func (e *fieldError) Error() string {
+	return e.message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *fieldError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	if len(e.details) > 0 {
+		extensions["errors"] = e.details
+	}
+	return extensions
+}
+
+// withExtensions sets the extensions of errors raised by thunks, which the executor wraps without them
+func withExtensions(formatted []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, err := range formatted {
+		if err.Extensions != nil {
+			continue
+		}
+		if fieldErr := originalFieldError(err); fieldErr != nil {
+			formatted[i].Extensions = fieldErr.Extensions()
+		}
+	}
+	return formatted
+}
+
+// originalFieldError follows the original errors of err down to a field error
+func originalFieldError(err error) *fieldError {
+	for err != nil {
+		switch wrapped := err.(type) {
+		case *fieldError:
+			return wrapped
+		case gqlerrors.FormattedError:
+			err = wrapped.OriginalError()
+		case *gqlerrors.Error:
+			err = wrapped.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
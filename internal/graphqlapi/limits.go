@@ -0,0 +1,194 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the cost of a query before it runs
+type Limits struct {
+	// MaxDepth is how deeply fields may nest, top level fields are at depth 1
+	MaxDepth int
+	// MaxComplexity bounds the number of fields the query can resolve, list fields
+	// counting their children once per item they can return
+	MaxComplexity int
+	// Introspection allows the __schema and __type queries
+	Introspection bool
+}
+
+// cost is the depth and complexity of a selection set
+type cost struct {
+	depth      int
+	complexity int
+}
+
+// checkLimits measures the operation of doc that runs and fails when it exceeds limits.
+// An unknown operation is left to the executor to report
+func checkLimits(doc *ast.Document, operationName string, variables map[string]any, limits Limits) error {
+	analysis := &analysis{fragments: map[string]*ast.FragmentDefinition{}, variables: variables, limits: limits}
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			analysis.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	// The validation of the executor overflows its stack on fragment cycles, they never reach it
+	if err := analysis.checkFragmentCycles(); err != nil {
+		return err
+	}
+	if operation == nil {
+		return nil
+	}
+
+	measured, err := analysis.selectionSet(operation.SelectionSet, 1)
+	if err != nil {
+		return err
+	}
+	if measured.depth > limits.MaxDepth {
+		return newFieldError(queryTooComplex, fmt.Sprintf("the query is %d levels deep, at most %d are allowed",
+			measured.depth, limits.MaxDepth))
+	}
+	if measured.complexity > limits.MaxComplexity {
+		return newFieldError(queryTooComplex, fmt.Sprintf("the query has a complexity of %d, at most %d is allowed",
+			measured.complexity, limits.MaxComplexity))
+	}
+	return nil
+}
+
+type analysis struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	limits    Limits
+}
+
+// selectionSet measures the fields of set at depth, fragments are free of cycles
+func (a *analysis) selectionSet(set *ast.SelectionSet, depth int) (cost, error) {
+	var total cost
+	if set == nil {
+		return total, nil
+	}
+	for _, selection := range set.Selections {
+		var measured cost
+		var err error
+		switch selection := selection.(type) {
+		case *ast.Field:
+			measured, err = a.field(selection, depth)
+		case *ast.InlineFragment:
+			measured, err = a.selectionSet(selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if fragment, ok := a.fragments[selection.Name.Value]; ok {
+				measured, err = a.selectionSet(fragment.SelectionSet, depth)
+			}
+		}
+		if err != nil {
+			return total, err
+		}
+		total.depth = max(total.depth, measured.depth)
+		total.complexity += measured.complexity
+	}
+	return total, nil
+}
+
+func (a *analysis) field(field *ast.Field, depth int) (cost, error) {
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		// Introspection answers from the schema without touching the database
+		if !a.limits.Introspection && name != "__typename" {
+			return cost{}, newFieldError(introspectionDisabled, "introspection is disabled")
+		}
+		return cost{}, nil
+	}
+
+	children, err := a.selectionSet(field.SelectionSet, depth+1)
+	if err != nil {
+		return cost{}, err
+	}
+	return cost{
+		depth:      max(depth, children.depth),
+		complexity: 1 + a.listSize(field)*children.complexity,
+	}, nil
+}
+
+// listSize is how many items a list field can return, from its first argument or default
+func (a *analysis) listSize(field *ast.Field) int {
+	size, ok := listDefaults[field.Name.Value]
+	if !ok {
+		return 1
+	}
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if parsed, err := strconv.Atoi(value.Value); err == nil {
+				size = parsed
+			}
+		case *ast.Variable:
+			if given, ok := a.variables[value.Name.Value].(float64); ok {
+				size = int(given)
+			}
+		}
+	}
+	return max(size, 1)
+}
+
+// checkFragmentCycles fails when a fragment spreads itself, directly or through others
+func (a *analysis) checkFragmentCycles() error {
+	// done holds the fragments known to be free of cycles, visiting those on the current path
+	done := map[string]bool{}
+	visiting := map[string]bool{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		fragment, ok := a.fragments[name]
+		if !ok || done[name] {
+			return nil
+		}
+		if visiting[name] {
+			return newFieldError(invalidQuery, fmt.Sprintf("fragment %s spreads itself", name))
+		}
+		visiting[name] = true
+		for _, spread := range spreads(fragment.SelectionSet) {
+			if err := visit(spread); err != nil {
+				return err
+			}
+		}
+		delete(visiting, name)
+		done[name] = true
+		return nil
+	}
+
+	for name := range a.fragments {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spreads returns the names of the fragments spread anywhere in set
+func spreads(set *ast.SelectionSet) []string {
+	if set == nil {
+		return nil
+	}
+	var names []string
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			names = append(names, spreads(selection.SelectionSet)...)
+		case *ast.InlineFragment:
+			names = append(names, spreads(selection.SelectionSet)...)
+		case *ast.FragmentSpread:
+			names = append(names, selection.Name.Value)
+		}
+	}
+	return names
+}
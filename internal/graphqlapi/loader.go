@@ -0,0 +1,116 @@
+package graphqlapi
+
+import (
+	"context"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+// loader batches the loads of one request. The executor resolves a query level by level and
+// runs the returned thunks only once the level is complete, so the keys asked for on a level
+// are fetched together when the first of their thunks runs
+type loader[K comparable, V any] struct {
+	fetch   func(keys []K) (map[K]V, error)
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: map[K]bool{},
+		values: map[K]V{},
+		errs:   map[K]error{},
+	}
+}
+
+// load queues key and returns the thunk resolving to its value, the zero value when it is missing
+func (l *loader[K, V]) load(key K) func() (any, error) {
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	return func() (any, error) {
+		l.flush()
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		return l.values[key], nil
+	}
+}
+
+func (l *loader[K, V]) flush() {
+	if len(l.pending) == 0 {
+		return
+	}
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = asFieldError(err)
+			continue
+		}
+		l.values[key] = values[key]
+	}
+}
+
+// childKey names the children of a parent matching a filter, e.g. the failed results of a run
+type childKey struct {
+	parentID int
+	filter   model.ListFilter
+}
+
+// loaders holds the loaders of one request
+type loaders struct {
+	projects    *loader[int, *model.ProjectResponse]
+	runs        *loader[int, *model.TestRunResponse]
+	projectRuns *loader[childKey, []*model.TestRunResponse]
+	runResults  *loader[childKey, []*model.TestResultResponse]
+}
+
+func newLoaders(ctx context.Context, dashboard service.IDashboardService) *loaders {
+	return &loaders{
+		projects: newLoader(func(ids []int) (map[int]*model.ProjectResponse, error) {
+			return dashboard.ProjectsByIDs(ctx, ids)
+		}),
+		runs: newLoader(func(ids []int) (map[int]*model.TestRunResponse, error) {
+			return dashboard.TestRunsByIDs(ctx, ids)
+		}),
+		projectRuns: newLoader(func(keys []childKey) (map[childKey][]*model.TestRunResponse, error) {
+			return byFilter(keys, func(ids []int, filter model.ListFilter) (map[int][]*model.TestRunResponse, error) {
+				return dashboard.TestRunsByProjectIDs(ctx, ids, filter)
+			})
+		}),
+		runResults: newLoader(func(keys []childKey) (map[childKey][]*model.TestResultResponse, error) {
+			return byFilter(keys, func(ids []int, filter model.ListFilter) (map[int][]*model.TestResultResponse, error) {
+				return dashboard.TestResultsByRunIDs(ctx, ids, filter)
+			})
+		}),
+	}
+}
+
+// byFilter fetches the children of keys with one call per distinct filter
+func byFilter[V any](keys []childKey,
+	fetch func(ids []int, filter model.ListFilter) (map[int][]V, error)) (map[childKey][]V, error) {
+	parents := map[model.ListFilter][]int{}
+	for _, key := range keys {
+		parents[key.filter] = append(parents[key.filter], key.parentID)
+	}
+
+	children := make(map[childKey][]V, len(keys))
+	for filter, ids := range parents {
+		byParent, err := fetch(ids, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			children[childKey{parentID: id, filter: filter}] = byParent[id]
+		}
+	}
+	return children, nil
+}
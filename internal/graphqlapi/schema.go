@@ -0,0 +1,198 @@
+package graphqlapi
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// maxFirst bounds the first argument of list fields
+const maxFirst = 500
+
+// listDefaults maps the list fields to the number of items they return without a first argument
+var listDefaults = map[string]int{
+	"runs":    20,
+	"results": 100,
+}
+
+// schemaBuilder builds the schema. Every field reading an entity asks authorize about the REST
+// path serving the same data, so a caller gets through GraphQL what it gets through REST.
+// Those fields are nullable, a refused field is null with an error and its siblings are kept
+type schemaBuilder struct {
+	authorize Authorizer
+}
+
+func newSchema(authorize Authorizer) (graphql.Schema, error) {
+	b := &schemaBuilder{authorize: authorize}
+	project := b.projectType()
+	run := b.runType(project, b.resultType())
+	b.addProjectRuns(project, run)
+	return graphql.NewSchema(graphql.SchemaConfig{Query: b.queryType(project, run)})
+}
+
+// guard resolves with resolve when the caller may read the REST path returned by path
+func (b *schemaBuilder) guard(path func(p graphql.ResolveParams) string,
+	resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		if err := b.authorize(p.Context, path(p)); err != nil {
+			return nil, asFieldError(err)
+		}
+		return resolve(p)
+	}
+}
+
+func (b *schemaBuilder) resultType() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "TestResult",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"sequence":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"caseKey":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"status":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"durationMs": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"message":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt":  &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+}
+
+func (b *schemaBuilder) projectType() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Project",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"key":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"version":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+}
+
+func (b *schemaBuilder) runType(project, result *graphql.Object) *graphql.Object {
+	sourceRun := func(p graphql.ResolveParams) *model.TestRunResponse {
+		return p.Source.(*model.TestRunResponse)
+	}
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "TestRun",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"projectId":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"status":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"lastSequence": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"resultCount":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"createdAt":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"closedAt":     &graphql.Field{Type: graphql.DateTime},
+			"project": &graphql.Field{
+				Type: project,
+				Resolve: b.guard(func(p graphql.ResolveParams) string {
+					return projectPath(sourceRun(p).ProjectID)
+				}, func(p graphql.ResolveParams) (any, error) {
+					return loadersFrom(p.Context).projects.load(sourceRun(p).ProjectID), nil
+				}),
+			},
+			"results": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(result)),
+				Description: "The first results of the run by sequence",
+				Args:        listArgs(listDefaults["results"]),
+				Resolve: b.guard(func(p graphql.ResolveParams) string {
+					return runPath(sourceRun(p).ID) + "/results"
+				}, func(p graphql.ResolveParams) (any, error) {
+					key, err := listKey(p, sourceRun(p).ID)
+					if err != nil {
+						return nil, err
+					}
+					return loadersFrom(p.Context).runResults.load(key), nil
+				}),
+			},
+		},
+	})
+}
+
+// addProjectRuns links projects to their runs once both types exist
+func (b *schemaBuilder) addProjectRuns(project, run *graphql.Object) {
+	project.AddFieldConfig("runs", &graphql.Field{
+		Type:        graphql.NewList(graphql.NewNonNull(run)),
+		Description: "The newest runs of the project",
+		Args:        listArgs(listDefaults["runs"]),
+		Resolve: b.guard(func(p graphql.ResolveParams) string {
+			return projectPath(p.Source.(*model.ProjectResponse).ID) + "/run"
+		}, func(p graphql.ResolveParams) (any, error) {
+			key, err := listKey(p, p.Source.(*model.ProjectResponse).ID)
+			if err != nil {
+				return nil, err
+			}
+			return loadersFrom(p.Context).projectRuns.load(key), nil
+		}),
+	})
+}
+
+func (b *schemaBuilder) queryType(project, run *graphql.Object) *graphql.Object {
+	idArgs := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}}
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"project": &graphql.Field{
+				Type: project,
+				Args: idArgs,
+				Resolve: b.guard(func(p graphql.ResolveParams) string {
+					return projectPath(p.Args["id"].(int))
+				}, func(p graphql.ResolveParams) (any, error) {
+					return loadersFrom(p.Context).projects.load(p.Args["id"].(int)), nil
+				}),
+			},
+			"testRun": &graphql.Field{
+				Type: run,
+				Args: idArgs,
+				Resolve: b.guard(func(p graphql.ResolveParams) string {
+					return runPath(p.Args["id"].(int))
+				}, func(p graphql.ResolveParams) (any, error) {
+					return loadersFrom(p.Context).runs.load(p.Args["id"].(int)), nil
+				}),
+			},
+		},
+	})
+}
+
+func listArgs(defaultFirst int) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"first": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: defaultFirst,
+			Description:  fmt.Sprintf("How many items to return, at most %d", maxFirst),
+		},
+		"status": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Only return items with this status",
+		},
+	}
+}
+
+// listKey reads the list arguments of the children of parentID
+func listKey(p graphql.ResolveParams, parentID int) (childKey, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > maxFirst {
+		return childKey{}, asFieldError(common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "VALIDATION_ERROR",
+			Message:   fmt.Sprintf("first must be between 1 and %d", maxFirst),
+			Path:      "first",
+		}}))
+	}
+	status, _ := p.Args["status"].(string)
+	return childKey{parentID: parentID, filter: model.ListFilter{Status: status, First: first}}, nil
+}
+
+func projectPath(id int) string {
+	return fmt.Sprintf("/api/v1/project/%d", id)
+}
+
+func runPath(id int) string {
+	return fmt.Sprintf("/api/v1/run/%d", id)
+}
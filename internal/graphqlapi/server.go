@@ -0,0 +1,96 @@
+package graphqlapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+// Authorizer decides whether the caller of ctx may read the REST path serving the same data
+// as a field, e.g. /api/v1/project/1. It returns the service error refusing the caller
+type Authorizer func(ctx context.Context, path string) error
+
+// Server answers GraphQL queries over the entities of the REST API. Queries are read only,
+// nested lists are fetched with one batched query per level
+type Server struct {
+	logger    *slog.Logger
+	schema    graphql.Schema
+	dashboard service.IDashboardService
+	limits    Limits
+}
+
+func NewServer(logger *slog.Logger, dashboardService service.IDashboardService, authorize Authorizer,
+	limits Limits) (*Server, error) {
+	schema, err := newSchema(authorize)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{logger: logger, schema: schema, dashboard: dashboardService, limits: limits}, nil
+}
+
+// request is a GraphQL request in the JSON encoding of GraphQL over HTTP
+type request struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type requestKey struct{}
+
+type loadersKey struct{}
+
+// HTTPRequest returns the HTTP request of a query context, for authorizers
+func HTTPRequest(ctx context.Context) *http.Request {
+	req, _ := ctx.Value(requestKey{}).(*http.Request)
+	return req
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// Handler answers a query. Errors of the query are reported in the GraphQL response with
+// status 200, only bodies that are not GraphQL requests get an error response
+func (s *Server) Handler(c *gin.Context) {
+	req := new(request)
+	if err := c.ShouldBindJSON(req); err != nil {
+		s.logger.WarnContext(c, "Failed to parse GraphQL request", "error", err)
+		_ = c.Error(common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_GRAPHQL_REQUEST",
+			Message:   "the body must be a JSON object with a query",
+		}}))
+		c.Abort()
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query)})})
+	if err != nil {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	if err = checkLimits(document, req.OperationName, req.Variables, s.limits); err != nil {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: withExtensions(gqlerrors.FormatErrors(err))})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), requestKey{}, c.Request)
+	ctx = context.WithValue(ctx, loadersKey{}, newLoaders(ctx, s.dashboard))
+	result := graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	result.Errors = withExtensions(result.Errors)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,293 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+var testLimits = Limits{MaxDepth: 4, MaxComplexity: 200}
+
+// fakeDashboard serves two projects with two runs each, calls counts the batched reads by method
+type fakeDashboard struct {
+	service.IDashboardService
+	calls map[string]int
+}
+
+func (f *fakeDashboard) ProjectsByIDs(_ context.Context, ids []int) (map[int]*model.ProjectResponse, error) {
+	f.calls["ProjectsByIDs"]++
+	projects := map[int]*model.ProjectResponse{}
+	for _, id := range ids {
+		if id <= 2 {
+			projects[id] = &model.ProjectResponse{ID: id, Name: "project"}
+		}
+	}
+	return projects, nil
+}
+
+func (f *fakeDashboard) TestRunsByIDs(_ context.Context, ids []int) (map[int]*model.TestRunResponse, error) {
+	f.calls["TestRunsByIDs"]++
+	runs := map[int]*model.TestRunResponse{}
+	for _, id := range ids {
+		runs[id] = &model.TestRunResponse{ID: id, ProjectID: (id + 1) / 2, Status: "open"}
+	}
+	return runs, nil
+}
+
+func (f *fakeDashboard) TestRunsByProjectIDs(_ context.Context, projectIDs []int,
+	filter model.ListFilter) (map[int][]*model.TestRunResponse, error) {
+	f.calls["TestRunsByProjectIDs"]++
+	runs := map[int][]*model.TestRunResponse{}
+	for _, id := range projectIDs {
+		for _, runID := range []int{2 * id, 2*id - 1}[:min(filter.First, 2)] {
+			runs[id] = append(runs[id], &model.TestRunResponse{ID: runID, ProjectID: id, Status: "open"})
+		}
+	}
+	return runs, nil
+}
+
+func (f *fakeDashboard) TestResultsByRunIDs(_ context.Context, runIDs []int,
+	filter model.ListFilter) (map[int][]*model.TestResultResponse, error) {
+	f.calls["TestResultsByRunIDs"]++
+	results := map[int][]*model.TestResultResponse{}
+	for _, id := range runIDs {
+		results[id] = []*model.TestResultResponse{{ID: id, Sequence: 1, Status: filter.Status}}
+	}
+	return results, nil
+}
+
+// refuseProject2 lets the caller read everything but the second project
+func refuseProject2(_ context.Context, path string) error {
+	if strings.HasPrefix(path, "/api/v1/project/2") {
+		return common.NewServiceError(common.ErrCode_Forbidden, nil)
+	}
+	return nil
+}
+
+type response struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func serve(t *testing.T, dashboard *fakeDashboard, limits Limits, body string) (int, *response) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	server, err := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), dashboard, refuseProject2, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := gin.New()
+	// Stands in for the error middleware of the server, which renders the errors of handlers
+	engine.Use(func(c *gin.Context) {
+		c.Next()
+		if err := c.Errors.Last(); err != nil {
+			c.Status(common.AsServiceError(err.Err).HTTPStatus)
+		}
+	})
+	engine.POST("/graphql", server.Handler)
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+	resp := new(response)
+	if recorder.Code == http.StatusOK {
+		if err = json.Unmarshal(recorder.Body.Bytes(), resp); err != nil {
+			t.Fatalf("decode %q: %v", recorder.Body.String(), err)
+		}
+	}
+	return recorder.Code, resp
+}
+
+func queryBody(t *testing.T, query string, variables map[string]any) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestCheckLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		limits    Limits
+		wantCode  string
+	}{
+		{
+			name:   "a shallow query passes",
+			query:  `{ project(id: 1) { id name } }`,
+			limits: testLimits,
+		},
+		{
+			name:     "nesting deeper than the maximum is refused",
+			query:    `{ project(id: 1) { runs { project { runs { id } } } } }`,
+			limits:   testLimits,
+			wantCode: queryTooComplex,
+		},
+		{
+			name:     "list fields multiply the complexity of their children",
+			query:    `{ project(id: 1) { runs(first: 50) { results(first: 50) { id } } } }`,
+			limits:   testLimits,
+			wantCode: queryTooComplex,
+		},
+		{
+			name:      "the first argument is read from variables",
+			query:     `query($n: Int) { project(id: 1) { runs(first: $n) { results(first: $n) { id } } } }`,
+			variables: map[string]any{"n": float64(5)},
+			limits:    testLimits,
+		},
+		{
+			name:     "fragments count where they are spread",
+			query:    `{ project(id: 1) { ...deep } } fragment deep on Project { runs { project { runs { id } } } }`,
+			limits:   testLimits,
+			wantCode: queryTooComplex,
+		},
+		{
+			name:     "fragment cycles are refused before validation",
+			query:    `{ project(id: 1) { ...a } } fragment a on Project { ...b } fragment b on Project { ...a }`,
+			limits:   testLimits,
+			wantCode: invalidQuery,
+		},
+		{
+			name:     "introspection is refused unless enabled",
+			query:    `{ __schema { types { name } } }`,
+			limits:   testLimits,
+			wantCode: introspectionDisabled,
+		},
+		{
+			name:   "introspection passes when enabled",
+			query:  `{ __schema { types { name } } }`,
+			limits: Limits{MaxDepth: 4, MaxComplexity: 200, Introspection: true},
+		},
+		{
+			name:   "__typename is always allowed",
+			query:  `{ project(id: 1) { __typename } }`,
+			limits: testLimits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(tt.query)})})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkLimits(doc, "", tt.variables, tt.limits)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want none", err)
+				}
+				return
+			}
+			fieldErr, ok := err.(*fieldError)
+			if !ok || fieldErr.code != tt.wantCode {
+				t.Fatalf("err = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		wantData  string
+		wantCodes []string
+		wantCalls map[string]int
+	}{
+		{
+			name:      "nested lists are fetched with one call per level",
+			query:     `{ a: project(id: 1) { runs(first: 2) { id results(first: 1, status: "failed") { status } } } b: testRun(id: 3) { id } }`,
+			wantData:  `{"a":{"runs":[{"id":2,"results":[{"status":"failed"}]},{"id":1,"results":[{"status":"failed"}]}]},"b":{"id":3}}`,
+			wantCalls: map[string]int{"ProjectsByIDs": 1, "TestRunsByIDs": 1, "TestRunsByProjectIDs": 1, "TestResultsByRunIDs": 1},
+		},
+		{
+			name:      "parents on one level share their batch",
+			query:     `{ testRun(id: 1) { project { id } } other: testRun(id: 2) { project { id } } }`,
+			wantData:  `{"testRun":{"project":{"id":1}},"other":{"project":{"id":1}}}`,
+			wantCalls: map[string]int{"TestRunsByIDs": 1, "ProjectsByIDs": 1},
+		},
+		{
+			name:      "a refused field is null and its siblings are kept",
+			query:     `{ one: project(id: 1) { id } two: project(id: 2) { id } }`,
+			wantData:  `{"one":{"id":1},"two":null}`,
+			wantCodes: []string{string(common.ErrCode_Forbidden)},
+			wantCalls: map[string]int{"ProjectsByIDs": 1},
+		},
+		{
+			name:      "first out of range is a bad request of the field",
+			query:     `query($n: Int) { project(id: 1) { runs(first: $n) { id } } }`,
+			variables: map[string]any{"n": 0},
+			wantData:  `{"project":{"runs":null}}`,
+			wantCodes: []string{string(common.ErrCode_BadRequest)},
+			wantCalls: map[string]int{"ProjectsByIDs": 1},
+		},
+		{
+			name:      "queries over the limits do not reach the service",
+			query:     `{ project(id: 1) { runs { project { runs { id } } } } }`,
+			wantData:  `null`,
+			wantCodes: []string{queryTooComplex},
+			wantCalls: map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dashboard := &fakeDashboard{calls: map[string]int{}}
+			code, resp := serve(t, dashboard, testLimits, queryBody(t, tt.query, tt.variables))
+			if code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", code)
+			}
+
+			data, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want any
+			_ = json.Unmarshal(data, &got)
+			if err = json.Unmarshal([]byte(tt.wantData), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("data = %s, want %s", data, tt.wantData)
+			}
+
+			var codes []string
+			for _, gqlErr := range resp.Errors {
+				codes = append(codes, gqlErr.Extensions["code"].(string))
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if !reflect.DeepEqual(dashboard.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", dashboard.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHandlerRefusesBodiesThatAreNotQueries(t *testing.T) {
+	for _, body := range []string{``, `[]`, `{"variables":{}}`} {
+		if code, _ := serve(t, &fakeDashboard{calls: map[string]int{}}, testLimits, body); code == http.StatusOK {
+			t.Errorf("body %q: status = 200, want an error", body)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// errorDomain is the domain of the ErrorInfo details
+const errorDomain = "qms-engine"
+
+// statusCodes maps the service error codes to gRPC codes, unknown codes become Internal
+var statusCodes = map[common.ErrorCode]codes.Code{
+	common.ErrCode_BadRequest:           codes.InvalidArgument,
+	common.ErrCode_Unauthorized:         codes.Unauthenticated,
+	common.ErrCode_Forbidden:            codes.PermissionDenied,
+	common.ErrCode_ResourceNotFound:     codes.NotFound,
+	common.ErrCode_Conflict:             codes.AlreadyExists,
+	common.ErrCode_PreconditionFailed:   codes.Aborted,
+	common.ErrCode_PreconditionRequired: codes.FailedPrecondition,
+	common.ErrCode_PayloadTooLarge:      codes.ResourceExhausted,
+	common.ErrCode_Unprocessable:        codes.FailedPrecondition,
+	common.ErrCode_TooManyRequests:      codes.ResourceExhausted,
+	common.ErrCode_InternalServerError:  codes.Internal,
+	common.ErrCode_ServiceUnavailable:   codes.Unavailable,
+}
+
+// toStatus converts a service error to a gRPC status error. The code travels in an ErrorInfo,
+// details with a path in a BadRequest. Other errors become Internal without leaking their text
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	serviceErr := common.AsServiceError(err)
+	code, ok := statusCodes[common.ErrorCode(serviceErr.Code)]
+	if !ok {
+		code = codes.Internal
+	}
+
+	info := &errdetails.ErrorInfo{Reason: serviceErr.Code, Domain: errorDomain, Metadata: map[string]string{}}
+	badRequest := &errdetails.BadRequest{}
+	for _, detail := range serviceErr.Errors {
+		for key, value := range detail.Meta {
+			info.Metadata[key] = fmt.Sprint(value)
+		}
+		if detail.Path != "" {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       detail.Path,
+				Description: detail.Message,
+			})
+		}
+	}
+
+	st := status.New(code, serviceErr.Message)
+	if len(badRequest.FieldViolations) > 0 {
+		if withDetails, detailsErr := st.WithDetails(info, badRequest); detailsErr == nil {
+			return withDetails.Err()
+		}
+	} else if withDetails, detailsErr := st.WithDetails(info); detailsErr == nil {
+		return withDetails.Err()
+	}
+	return st.Err()
+}
@@ -0,0 +1,147 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"expvar"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// APIKeyMetadata carries the API key of the caller, like the X-API-Key header of the REST API
+const APIKeyMetadata = "x-api-key"
+
+var (
+	// rpcCount counts the finished calls by "method code", rpcLatency sums their durations in ms
+	rpcCount   = expvar.NewMap("grpc.calls")
+	rpcLatency = expvar.NewMap("grpc.latency_ms")
+)
+
+// Interceptors returns the unary and stream interceptors of the server, in order: metrics,
+// logging, recovery and API key authentication. Recovered panics are counted and logged as Internal
+func Interceptors(logger *slog.Logger, apiKeys []string) (grpc.ServerOption, grpc.ServerOption) {
+	return grpc.ChainUnaryInterceptor(
+			metricsUnary,
+			loggingUnary(logger),
+			recoveryUnary(logger),
+			authUnary(apiKeys),
+		), grpc.ChainStreamInterceptor(
+			metricsStream,
+			loggingStream(logger),
+			recoveryStream(logger),
+			authStream(apiKeys),
+		)
+}
+
+func recoveryUnary(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recoverPanic(ctx, logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func recoveryStream(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverPanic(stream.Context(), logger, info.FullMethod, &err)
+		return handler(srv, stream)
+	}
+}
+
+// recoverPanic turns a panic of a handler into an Internal error, like the gin recovery middleware
+func recoverPanic(ctx context.Context, logger *slog.Logger, method string, err *error) {
+	if recovered := recover(); recovered != nil {
+		logger.ErrorContext(ctx, "Panic recovered", "method", method, "panic", recovered, "stack", string(debug.Stack()))
+		*err = toStatus(common.NewServiceError(common.ErrCode_InternalServerError, nil))
+	}
+}
+
+func metricsUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+func metricsStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observe(info.FullMethod, err, time.Since(start))
+	return err
+}
+
+func observe(method string, err error, elapsed time.Duration) {
+	key := method + " " + status.Code(err).String()
+	rpcCount.Add(key, 1)
+	rpcLatency.Add(key, elapsed.Milliseconds())
+}
+
+func loggingUnary(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+func loggingStream(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		logCall(stream.Context(), logger, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+// logCall logs a finished call, server faults at error level and client faults at warn level
+func logCall(ctx context.Context, logger *slog.Logger, method string, err error, elapsed time.Duration) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	logger.Log(ctx, level, "gRPC call", "method", method, "code", code.String(),
+		"latencyMs", elapsed.Milliseconds())
+}
+
+func authUnary(apiKeys []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authenticate(ctx, apiKeys); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStream(apiKeys []string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticate(stream.Context(), apiKeys); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authenticate requires one of apiKeys in the x-api-key metadata, no caller is accepted without keys
+func authenticate(ctx context.Context, apiKeys []string) error {
+	for _, given := range metadata.ValueFromIncomingContext(ctx, APIKeyMetadata) {
+		for _, key := range apiKeys {
+			if subtle.ConstantTimeCompare([]byte(given), []byte(key)) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "a valid x-api-key is required")
+}
@@ -0,0 +1,154 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+	"github.com/project-weekend/qms-engine/internal/service"
+	qmsenginev1 "github.com/project-weekend/qms-engine/pkg/pb/qmsengine/v1"
+)
+
+const logTag = "grpcapi"
+
+// ProjectServer serves qmsengine.v1.ProjectService with the service layer of the REST handlers
+type ProjectServer struct {
+	qmsenginev1.UnimplementedProjectServiceServer
+	Logger         *slog.Logger
+	Validator      *validator.Validate
+	ProjectService service.IProjectService
+}
+
+func NewProjectServer(logger *slog.Logger, validator *validator.Validate,
+	projectService service.IProjectService) *ProjectServer {
+	return &ProjectServer{
+		Logger:         logger,
+		Validator:      validator,
+		ProjectService: projectService,
+	}
+}
+
+// CreateProject creates a project
+func (s *ProjectServer) CreateProject(ctx context.Context,
+	req *qmsenginev1.CreateProjectRequest) (*qmsenginev1.CreateProjectResponse, error) {
+	request := &model.CreateProjectRequest{Name: req.GetName(), Key: req.GetKey(), Description: req.GetDescription()}
+	if err := validate(ctx, s.Validator, request); err != nil {
+		return nil, toStatus(err)
+	}
+
+	created, err := s.ProjectService.CreateProject(ctx, request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "CreateProject error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.CreateProjectResponse{Project: &qmsenginev1.Project{
+		Id:          int64(created.ID),
+		Name:        request.Name,
+		Key:         request.Key,
+		Description: request.Description,
+		Version:     int64(created.Version),
+		CreatedAt:   timestamp(created.CreatedAt),
+		UpdatedAt:   timestamp(created.UpdatedAt),
+	}}, nil
+}
+
+// GetProject returns a project
+func (s *ProjectServer) GetProject(ctx context.Context,
+	req *qmsenginev1.GetProjectRequest) (*qmsenginev1.GetProjectResponse, error) {
+	if err := positive("id", req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	found, err := s.ProjectService.GetProject(ctx, int(req.GetId()))
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "GetProject error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.GetProjectResponse{Project: toProject(found)}, nil
+}
+
+// UpdateProject changes the fields that are set when the project is at the expected version
+func (s *ProjectServer) UpdateProject(ctx context.Context,
+	req *qmsenginev1.UpdateProjectRequest) (*qmsenginev1.UpdateProjectResponse, error) {
+	if err := positive("id", req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := positive("expected_version", req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+	request := &model.UpdateProjectRequest{Name: req.Name, Key: req.Key, Description: req.Description}
+	if err := validate(ctx, s.Validator, request); err != nil {
+		return nil, toStatus(err)
+	}
+
+	updated, err := s.ProjectService.UpdateProject(ctx, int(req.GetId()), int(req.GetExpectedVersion()), request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "UpdateProject error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.UpdateProjectResponse{Project: toProject(updated)}, nil
+}
+
+// DeleteProject deletes a project when it is at the expected version
+func (s *ProjectServer) DeleteProject(ctx context.Context,
+	req *qmsenginev1.DeleteProjectRequest) (*qmsenginev1.DeleteProjectResponse, error) {
+	if err := positive("id", req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := positive("expected_version", req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.ProjectService.DeleteProject(ctx, int(req.GetId()), int(req.GetExpectedVersion())); err != nil {
+		s.Logger.ErrorContext(ctx, "DeleteProject error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.DeleteProjectResponse{}, nil
+}
+
+// validate normalizes and validates request like the REST handlers do
+func validate(ctx context.Context, requestValidator *validator.Validate, request any) error {
+	if err := normalize.Struct(request); err != nil {
+		return common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+	if err := requestValidator.StructCtx(ctx, request); err != nil {
+		return common.NewServiceError(common.ErrCode_BadRequest, common.ParseValidationErrors(err, nil))
+	}
+	return nil
+}
+
+func positive(field string, value int64) error {
+	if value > 0 {
+		return nil
+	}
+	return common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+		ErrorCode: "VALIDATION_ERROR",
+		Message:   field + " must be a positive integer",
+		Path:      field,
+	}})
+}
+
+func toProject(project *model.ProjectResponse) *qmsenginev1.Project {
+	return &qmsenginev1.Project{
+		Id:          int64(project.ID),
+		Name:        project.Name,
+		Key:         project.Key,
+		Description: project.Description,
+		Version:     int64(project.Version),
+		CreatedAt:   timestamp(project.CreatedAt),
+		UpdatedAt:   timestamp(project.UpdatedAt),
+	}
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,133 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+	qmsenginev1 "github.com/project-weekend/qms-engine/pkg/pb/qmsengine/v1"
+)
+
+// TestCaseServer serves qmsengine.v1.TestCaseService with the service layer of the imports
+type TestCaseServer struct {
+	qmsenginev1.UnimplementedTestCaseServiceServer
+	Logger          *slog.Logger
+	Validator       *validator.Validate
+	TestCaseService service.ITestCaseService
+}
+
+func NewTestCaseServer(logger *slog.Logger, validator *validator.Validate,
+	testCaseService service.ITestCaseService) *TestCaseServer {
+	return &TestCaseServer{
+		Logger:          logger,
+		Validator:       validator,
+		TestCaseService: testCaseService,
+	}
+}
+
+// ListTestSuites returns the suites of a project
+func (s *TestCaseServer) ListTestSuites(ctx context.Context,
+	req *qmsenginev1.ListTestSuitesRequest) (*qmsenginev1.ListTestSuitesResponse, error) {
+	if err := positive("project_id", req.GetProjectId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	suites, err := s.TestCaseService.ListTestSuites(ctx, int(req.GetProjectId()))
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "ListTestSuites error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	response := &qmsenginev1.ListTestSuitesResponse{TestSuites: make([]*qmsenginev1.TestSuite, 0, len(suites))}
+	for _, suite := range suites {
+		response.TestSuites = append(response.TestSuites, &qmsenginev1.TestSuite{
+			Id:          int64(suite.ID),
+			ProjectId:   int64(suite.ProjectID),
+			Name:        suite.Name,
+			Description: suite.Description,
+			CreatedAt:   timestamp(suite.CreatedAt),
+			UpdatedAt:   timestamp(suite.UpdatedAt),
+		})
+	}
+	return response, nil
+}
+
+// ListTestCases returns a page of the cases of a project
+func (s *TestCaseServer) ListTestCases(ctx context.Context,
+	req *qmsenginev1.ListTestCasesRequest) (*qmsenginev1.ListTestCasesResponse, error) {
+	if err := positive("project_id", req.GetProjectId()); err != nil {
+		return nil, toStatus(err)
+	}
+	request := &model.ListTestCasesRequest{
+		SuiteID: int(req.GetSuiteId()),
+		AfterID: int(req.GetAfterId()),
+		Limit:   int(req.GetPageSize()),
+	}
+	if err := validate(ctx, s.Validator, request); err != nil {
+		return nil, toStatus(err)
+	}
+
+	page, err := s.TestCaseService.ListTestCases(ctx, int(req.GetProjectId()), request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "ListTestCases error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	response := &qmsenginev1.ListTestCasesResponse{
+		TestCases:   make([]*qmsenginev1.TestCase, 0, len(page.Cases)),
+		NextAfterId: int64(page.NextAfterID),
+	}
+	for _, testCase := range page.Cases {
+		response.TestCases = append(response.TestCases, toTestCase(testCase))
+	}
+	return response, nil
+}
+
+// GetTestCase returns a case with its steps
+func (s *TestCaseServer) GetTestCase(ctx context.Context,
+	req *qmsenginev1.GetTestCaseRequest) (*qmsenginev1.GetTestCaseResponse, error) {
+	if err := positive("project_id", req.GetProjectId()); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := positive("id", req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	found, err := s.TestCaseService.GetTestCase(ctx, int(req.GetProjectId()), int(req.GetId()))
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "GetTestCase error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.GetTestCaseResponse{TestCase: toTestCase(found)}, nil
+}
+
+func toTestCase(testCase *model.TestCaseResponse) *qmsenginev1.TestCase {
+	converted := &qmsenginev1.TestCase{
+		Id:            int64(testCase.ID),
+		ProjectId:     int64(testCase.ProjectID),
+		ExternalId:    testCase.ExternalID,
+		Title:         testCase.Title,
+		Section:       testCase.Section,
+		Priority:      testCase.Priority,
+		Preconditions: testCase.Preconditions,
+		Description:   testCase.Description,
+		Tags:          testCase.Tags,
+		CustomFields:  testCase.CustomFields,
+		Steps:         make([]*qmsenginev1.TestStep, 0, len(testCase.Steps)),
+		CreatedAt:     timestamp(testCase.CreatedAt),
+		UpdatedAt:     timestamp(testCase.UpdatedAt),
+	}
+	if testCase.SuiteID != nil {
+		suiteID := int64(*testCase.SuiteID)
+		converted.SuiteId = &suiteID
+	}
+	for _, step := range testCase.Steps {
+		converted.Steps = append(converted.Steps, &qmsenginev1.TestStep{
+			Position: int32(step.Position),
+			Action:   step.Action,
+			Expected: step.Expected,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,186 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+	qmsenginev1 "github.com/project-weekend/qms-engine/pkg/pb/qmsengine/v1"
+)
+
+// TestRunServer serves qmsengine.v1.TestRunService with the service layer of the REST handlers
+type TestRunServer struct {
+	qmsenginev1.UnimplementedTestRunServiceServer
+	Logger         *slog.Logger
+	Validator      *validator.Validate
+	TestRunService service.ITestRunService
+}
+
+func NewTestRunServer(logger *slog.Logger, validator *validator.Validate,
+	testRunService service.ITestRunService) *TestRunServer {
+	return &TestRunServer{
+		Logger:         logger,
+		Validator:      validator,
+		TestRunService: testRunService,
+	}
+}
+
+// CreateTestRun opens a test run of a project
+func (s *TestRunServer) CreateTestRun(ctx context.Context,
+	req *qmsenginev1.CreateTestRunRequest) (*qmsenginev1.CreateTestRunResponse, error) {
+	if err := positive("project_id", req.GetProjectId()); err != nil {
+		return nil, toStatus(err)
+	}
+	request := &model.CreateTestRunRequest{Name: req.GetName()}
+	if err := validate(ctx, s.Validator, request); err != nil {
+		return nil, toStatus(err)
+	}
+
+	created, err := s.TestRunService.CreateTestRun(ctx, int(req.GetProjectId()), request)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "CreateTestRun error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.CreateTestRunResponse{TestRun: toTestRun(created)}, nil
+}
+
+// GetTestRun returns a test run
+func (s *TestRunServer) GetTestRun(ctx context.Context,
+	req *qmsenginev1.GetTestRunRequest) (*qmsenginev1.GetTestRunResponse, error) {
+	if err := positive("id", req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	found, err := s.TestRunService.GetTestRun(ctx, int(req.GetId()))
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "GetTestRun error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.GetTestRunResponse{TestRun: toTestRun(found)}, nil
+}
+
+// CloseTestRun closes a test run to further results
+func (s *TestRunServer) CloseTestRun(ctx context.Context,
+	req *qmsenginev1.CloseTestRunRequest) (*qmsenginev1.CloseTestRunResponse, error) {
+	if err := positive("id", req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	closed, err := s.TestRunService.CloseTestRun(ctx, int(req.GetId()))
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "CloseTestRun error", "tag", logTag, "error", err)
+		return nil, toStatus(err)
+	}
+	return &qmsenginev1.CloseTestRunResponse{TestRun: toTestRun(closed)}, nil
+}
+
+// UploadResults stores the streamed results, the run is named by the first message
+func (s *TestRunServer) UploadResults(stream qmsenginev1.TestRunService_UploadResultsServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return toStatus(common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "EMPTY_UPLOAD",
+			Message:   "the stream ended before naming the run",
+			Path:      "run_id",
+		}}))
+	}
+	if err != nil {
+		return err
+	}
+	if err = positive("run_id", first.GetRunId()); err != nil {
+		return toStatus(err)
+	}
+
+	results := &streamResults{server: s, stream: stream, runID: first.GetRunId(), pending: first.GetResults()}
+	progress, err := s.TestRunService.UploadResults(ctx, int(first.GetRunId()), results)
+	if err != nil {
+		s.Logger.ErrorContext(ctx, "UploadResults error", "tag", logTag, "error", err,
+			"runId", first.GetRunId(), "ackedSequence", progress.AckedSequence)
+		// The progress goes out ahead of the error, so the client knows where to resume. It is
+		// lost only when the stream itself broke
+		if ackErr := results.Ack(progress); ackErr != nil {
+			s.Logger.WarnContext(ctx, "UploadResults: failed to send the last progress", "tag", logTag, "error", ackErr)
+		}
+		return toStatus(err)
+	}
+	return results.Ack(progress)
+}
+
+// streamResults reads the results of an UploadResults stream and sends the progress
+type streamResults struct {
+	server  *TestRunServer
+	stream  qmsenginev1.TestRunService_UploadResultsServer
+	runID   int64
+	pending []*qmsenginev1.TestResult
+}
+
+// Next returns the next result, receiving messages until one carries results
+func (r *streamResults) Next() (*model.TestResultRequest, error) {
+	for len(r.pending) == 0 {
+		message, err := r.stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if message.GetRunId() != r.runID {
+			return nil, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+				ErrorCode: "RUN_CHANGED",
+				Message:   "every message of the stream must name the same run",
+				Path:      "run_id",
+			}})
+		}
+		r.pending = message.GetResults()
+	}
+
+	next := r.pending[0]
+	r.pending = r.pending[1:]
+	result := &model.TestResultRequest{
+		Sequence:   next.GetSequence(),
+		CaseKey:    next.GetCaseKey(),
+		Status:     next.GetStatus(),
+		DurationMs: next.GetDurationMs(),
+		Message:    next.GetMessage(),
+	}
+	if err := validate(r.stream.Context(), r.server.Validator, result); err != nil {
+		serviceErr := common.AsServiceError(err)
+		for i := range serviceErr.Errors {
+			serviceErr.Errors[i].Meta = map[string]any{"sequence": next.GetSequence()}
+		}
+		return nil, serviceErr
+	}
+	return result, nil
+}
+
+// Ack sends the progress to the client
+func (r *streamResults) Ack(progress *model.UploadResultsResponse) error {
+	return r.stream.Send(&qmsenginev1.UploadResultsResponse{
+		AckedSequence: progress.AckedSequence,
+		Received:      int64(progress.Received),
+		Stored:        int64(progress.Stored),
+		Duplicates:    int64(progress.Duplicates),
+		Done:          progress.Done,
+	})
+}
+
+func toTestRun(run *model.TestRunResponse) *qmsenginev1.TestRun {
+	testRun := &qmsenginev1.TestRun{
+		Id:           int64(run.ID),
+		ProjectId:    int64(run.ProjectID),
+		Name:         run.Name,
+		Status:       run.Status,
+		LastSequence: run.LastSequence,
+		ResultCount:  int64(run.ResultCount),
+		CreatedAt:    timestamp(run.CreatedAt),
+		UpdatedAt:    timestamp(run.UpdatedAt),
+	}
+	if run.ClosedAt != nil {
+		testRun.ClosedAt = timestamp(*run.ClosedAt)
+	}
+	return testRun
+}
@@ -0,0 +1,96 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DatabaseChecker pings the database
+func DatabaseChecker(db *sqlx.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+}
+
+// MigrationChecker verifies that the schema_migrations table has reached the expected version
+func MigrationChecker(db *sqlx.DB, expectedVersion int) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var version int
+		query := `SELECT COALESCE(MAX(version), -1) FROM schema_migrations`
+		if err := db.GetContext(ctx, &version, query); err != nil {
+			return fmt.Errorf("failed to read schema version: %w", err)
+		}
+		if version < expectedVersion {
+			return fmt.Errorf("schema version %d is behind expected version %d", version, expectedVersion)
+		}
+		return nil
+	})
+}
+
+// RedisChecker sends a PING to the Redis server at addr
+func RedisChecker(addr string, useTLS bool) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		conn, err := dial(ctx, addr, useTLS)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if _, err = conn.Write([]byte("PING\r\n")); err != nil {
+			return fmt.Errorf("failed to send PING: %w", err)
+		}
+		reply, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read PING reply: %w", err)
+		}
+
+		// A server asking for credentials is up, authentication is the client's concern
+		reply = strings.TrimSpace(reply)
+		if reply != "+PONG" && !strings.HasPrefix(reply, "-NOAUTH") {
+			return fmt.Errorf("unexpected PING reply %q", reply)
+		}
+		return nil
+	})
+}
+
+// KafkaChecker verifies that at least one of the comma separated bootstrap servers accepts connections
+func KafkaChecker(bootstrapServers string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var errs []error
+		for _, broker := range strings.Split(bootstrapServers, ",") {
+			conn, err := dial(ctx, strings.TrimSpace(broker), false)
+			if err == nil {
+				return conn.Close()
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
+
+func dial(ctx context.Context, addr string, useTLS bool) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if useTLS {
+		dialer := &tls.Dialer{Config: &tls.Config{MinVersion: tls.VersionTLS12}}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	return conn, nil
+}
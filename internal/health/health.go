@@ -0,0 +1,145 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Checker verifies that a dependency is usable
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Component is the result of a single check
+type Component struct {
+	Status    Status `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report aggregates the results of all registered checks. It is down when a critical
+// component is down and degraded when only non-critical components are
+type Report struct {
+	Status     Status               `json:"status"`
+	CheckedAt  time.Time            `json:"checkedAt"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+type registeredCheck struct {
+	name     string
+	critical bool
+	checker  Checker
+}
+
+// Registry runs the registered checks concurrently and caches the report briefly,
+// so that frequent probes do not hammer the dependencies
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	checks   []registeredCheck
+	cached   *Report
+	cachedAt time.Time
+}
+
+// NewRegistry creates a registry giving every check at most timeout to complete
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Register adds a check. A failing critical check makes the whole service unready
+func (r *Registry) Register(name string, critical bool, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, registeredCheck{name: name, critical: critical, checker: checker})
+	r.cached = nil
+}
+
+// Check returns the cached report when it is fresh, or runs all checks.
+// Concurrent callers wait for the same run instead of starting their own
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil && time.Since(r.cachedAt) < r.cacheTTL {
+		return *r.cached
+	}
+
+	// The report is shared with other callers, so it must not fail because this caller went away
+	report := r.run(context.WithoutCancel(ctx))
+	r.cached = &report
+	r.cachedAt = time.Now()
+	return report
+}
+
+func (r *Registry) run(ctx context.Context) Report {
+	results := make([]Component, len(r.checks))
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.runOne(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:     StatusUp,
+		CheckedAt:  time.Now(),
+		Components: make(map[string]Component, len(r.checks)),
+	}
+	for i, check := range r.checks {
+		result := results[i]
+		report.Components[check.name] = result
+		if result.Status == StatusUp {
+			continue
+		}
+		if check.critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (r *Registry) runOne(ctx context.Context, check registeredCheck) Component {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.checker.Check(ctx)
+	component := Component{
+		Status:    StatusUp,
+		Critical:  check.critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
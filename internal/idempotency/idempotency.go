@@ -0,0 +1,46 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrInProgress is returned by Store.Begin when another request holds the key
+	ErrInProgress = errors.New("idempotency key is in progress")
+	// ErrLockLost is returned by Store.Complete when the lock expired and the key was taken again
+	ErrLockLost = errors.New("idempotency key lock was lost")
+)
+
+// Response is a stored response, replayed to the retries of a request
+type Response struct {
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType"`
+	// Header holds the response headers kept for the retries, e.g. ETag and Location
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body"`
+}
+
+// Record is the state of an idempotency key
+type Record struct {
+	// Fingerprint identifies the request that first used the key
+	Fingerprint string `json:"fingerprint"`
+	// Token identifies the lock of the request holding the key
+	Token string `json:"token,omitempty"`
+	// Response is nil while the first request is in progress
+	Response *Response `json:"response,omitempty"`
+}
+
+// Store keeps idempotency records. Keys are opaque and already scoped to the principal
+type Store interface {
+	// Begin locks key with token for lockTTL when it is unused and returns nil.
+	// Otherwise it returns the existing record, or ErrInProgress while it is locked
+	Begin(ctx context.Context, key, token, fingerprint string, lockTTL time.Duration) (*Record, error)
+	// Complete stores the response of the request holding key with token for ttl. It returns
+	// ErrLockLost when the lock expired, the record of the request holding the key now is kept
+	Complete(ctx context.Context, key, token string, response Response, ttl time.Duration) error
+	// Release unlocks key when token still holds it, so the request can be retried
+	Release(ctx context.Context, key, token string) error
+}
@@ -0,0 +1,129 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MySQLStore keeps the records in the idempotency_keys table
+type MySQLStore struct {
+	db *sqlx.DB
+}
+
+// NewMySQLStore creates a store on db
+func NewMySQLStore(db *sqlx.DB) *MySQLStore {
+	return &MySQLStore{db: db}
+}
+
+type mysqlRecord struct {
+	Fingerprint string        `db:"fingerprint"`
+	StatusCode  sql.NullInt32 `db:"status_code"`
+	ContentType string        `db:"content_type"`
+	Header      []byte        `db:"header"`
+	Body        []byte        `db:"body"`
+	Expired     bool          `db:"expired"`
+}
+
+// Begin locks key with an insert, the primary key lets a single request win
+func (s *MySQLStore) Begin(ctx context.Context, key, token, fingerprint string, lockTTL time.Duration) (*Record, error) {
+	// An expired record is deleted and the insert retried once
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := s.db.ExecContext(ctx, `
+			INSERT IGNORE INTO idempotency_keys (id, fingerprint, lock_token, expires_at)
+			VALUES (?, ?, ?, NOW() + INTERVAL ? MICROSECOND)
+		`, key, fingerprint, token, lockTTL.Microseconds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert idempotency key: %w", err)
+		}
+		if inserted, _ := result.RowsAffected(); inserted == 1 {
+			return nil, nil
+		}
+
+		var row mysqlRecord
+		err = s.db.GetContext(ctx, &row, `
+			SELECT fingerprint, status_code, content_type, header, body, expires_at < NOW() AS expired
+			FROM idempotency_keys
+			WHERE id = ?
+		`, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+
+		if row.Expired {
+			if _, err = s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE id = ? AND expires_at < NOW()`, key); err != nil {
+				return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+			}
+			continue
+		}
+		record := &Record{Fingerprint: row.Fingerprint}
+		if !row.StatusCode.Valid {
+			return record, ErrInProgress
+		}
+		record.Response = &Response{StatusCode: int(row.StatusCode.Int32), ContentType: row.ContentType, Body: row.Body}
+		if row.Header != nil {
+			if err = json.Unmarshal(row.Header, &record.Response.Header); err != nil {
+				return nil, fmt.Errorf("failed to decode idempotent response header: %w", err)
+			}
+		}
+		return record, nil
+	}
+	return nil, ErrInProgress
+}
+
+// Complete stores the response and extends the record to ttl, while token still holds the lock
+func (s *MySQLStore) Complete(ctx context.Context, key, token string, response Response, ttl time.Duration) error {
+	var header []byte
+	if len(response.Header) > 0 {
+		var err error
+		if header, err = json.Marshal(response.Header); err != nil {
+			return err
+		}
+	}
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = ?, content_type = ?, header = ?, body = ?, expires_at = NOW() + INTERVAL ? MICROSECOND
+		WHERE id = ? AND lock_token = ? AND status_code IS NULL
+	`, response.StatusCode, response.ContentType, header, response.Body, ttl.Microseconds(), key, token)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release deletes the lock of an unfinished request, while token still holds it
+func (s *MySQLStore) Release(ctx context.Context, key, token string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE id = ? AND lock_token = ? AND status_code IS NULL
+	`, key, token)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Run purges the expired records every interval until ctx is done
+func (s *MySQLStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Purging is best effort, expired records are also replaced on use
+			_, _ = s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW() LIMIT 1000`)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// completeScript adds the response ARGV[2] to a record locked with the token ARGV[1] and keeps
+	// it for ARGV[3] milliseconds. It returns 0 when the lock was lost
+	completeScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if not value then
+  return 0
+end
+local record = cjson.decode(value)
+if record.token ~= ARGV[1] or record.response then
+  return 0
+end
+record.response = cjson.decode(ARGV[2])
+redis.call('SET', KEYS[1], cjson.encode(record), 'PX', ARGV[3])
+return 1
+`)
+	// releaseScript deletes a record only while it is still in progress under the token ARGV[1]
+	releaseScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if not value then
+  return 0
+end
+local record = cjson.decode(value)
+if record.token == ARGV[1] and not record.response then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+// RedisStore keeps the records as JSON values expiring with their TTL
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a store keeping the records under prefix
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Begin locks key with SET NX, so a single request wins
+func (s *RedisStore) Begin(ctx context.Context, key, token, fingerprint string, lockTTL time.Duration) (*Record, error) {
+	lock, err := json.Marshal(Record{Fingerprint: fingerprint, Token: token})
+	if err != nil {
+		return nil, err
+	}
+
+	// The record can expire between the two commands, the lock is then retried once
+	for attempt := 0; attempt < 2; attempt++ {
+		locked, err := s.client.SetNX(ctx, s.prefix+key, lock, lockTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock idempotency key: %w", err)
+		}
+		if locked {
+			return nil, nil
+		}
+
+		value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+
+		var record Record
+		if err = json.Unmarshal(value, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		if record.Response == nil {
+			return &record, ErrInProgress
+		}
+		return &record, nil
+	}
+	return nil, ErrInProgress
+}
+
+// Complete stores the response in one step with checking the lock, keeping the fingerprint
+func (s *RedisStore) Complete(ctx context.Context, key, token string, response Response, ttl time.Duration) error {
+	value, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	stored, err := completeScript.Run(ctx, s.client, []string{s.prefix + key}, token, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	if stored == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release deletes the lock of an unfinished request, while token still holds it
+func (s *RedisStore) Release(ctx context.Context, key, token string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{s.prefix + key}, token).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The tools exports are read from
+const (
+	SourceTestRail = "testrail"
+	SourceZephyr   = "zephyr"
+	SourceXray     = "xray"
+)
+
+// The formats of exports
+const (
+	FormatXML  = "xml"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+const (
+	// maxSamples bounds the values kept as samples of an unmapped field
+	maxSamples = 3
+	// maxSampleLength bounds a sample, in runes
+	maxSampleLength = 100
+)
+
+// stepNumber is the numbering tools often put before steps, e.g. "1." or "2)"
+var stepNumber = regexp.MustCompile(`^\d+[.)]\s*`)
+
+// ErrUnsupportedFormat is returned when a tool has no export in the format
+var ErrUnsupportedFormat = errors.New("importer: unsupported format")
+
+// Export is what an export of another tool holds, in the terms of this service
+type Export struct {
+	Suites []*Suite
+	Cases  []*Case
+	Runs   []*Run
+	// Unmapped are the fields of the export that have no place here, sorted by field
+	Unmapped []*UnmappedField
+}
+
+// Suite groups cases in tools that have suites, only TestRail among them
+type Suite struct {
+	// SourceID is the ID of the suite in the tool
+	SourceID    string
+	Name        string
+	Description string
+}
+
+// Case is a test case, its steps and the custom fields of the tool holding a value
+type Case struct {
+	// SourceID is the ID of the case in the tool, it stays the same when the case is moved
+	SourceID string
+	// Key is what the case is known by, the results of runs name it
+	Key string
+	// SuiteID is the SourceID of the suite of the case, empty when the tool has no suites
+	SuiteID       string
+	Title         string
+	Section       string
+	Priority      string
+	Preconditions string
+	Description   string
+	Tags          []string
+	Steps         []*Step
+	CustomFields  map[string]string
+}
+
+type Step struct {
+	Action   string
+	Expected string
+}
+
+// Run is a past run of cases
+type Run struct {
+	SourceID string
+	Name     string
+	// ClosedAt is when the last result was recorded, nil when the export does not tell
+	ClosedAt *time.Time
+	Results  []*Result
+}
+
+// Result is the outcome of a case in a run. Statuses are those of test results, the ones the
+// tool has and this service has not are skipped
+type Result struct {
+	CaseKey    string
+	Status     string
+	DurationMs int64
+	Message    string
+}
+
+// UnmappedField is a field of the export that was left out, with some of its values
+type UnmappedField struct {
+	Field   string
+	Count   int
+	Samples []string
+}
+
+// Parse reads the export of source in format. It fails with ErrUnsupportedFormat when the tool has
+// no such export, and with the reason otherwise when the export cannot be read
+func Parse(source, format string, r io.Reader) (*Export, error) {
+	switch {
+	case source == SourceTestRail && format == FormatXML:
+		return parseTestRailXML(r)
+	case source == SourceTestRail && format == FormatCSV:
+		return parseTestRailCSV(r)
+	case source == SourceZephyr && format == FormatJSON:
+		return parseZephyr(r)
+	case source == SourceXray && format == FormatJSON:
+		return parseXray(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// DefaultFormat is the format of the usual export of source
+func DefaultFormat(source string) string {
+	if source == SourceTestRail {
+		return FormatXML
+	}
+	return FormatJSON
+}
+
+// unmapped collects the fields left out of an export by name
+type unmapped map[string]*UnmappedField
+
+// add counts a value of field that was left out, empty values do not count
+func (u unmapped) add(field, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	f, ok := u[field]
+	if !ok {
+		f = &UnmappedField{Field: field}
+		u[field] = f
+	}
+	f.Count++
+	if runes := []rune(value); len(runes) > maxSampleLength {
+		value = string(runes[:maxSampleLength])
+	}
+	if len(f.Samples) < maxSamples && !slices.Contains(f.Samples, value) {
+		f.Samples = append(f.Samples, value)
+	}
+}
+
+func (u unmapped) sorted() []*UnmappedField {
+	fields := make([]*UnmappedField, 0, len(u))
+	for _, f := range u {
+		fields = append(fields, f)
+	}
+	slices.SortFunc(fields, func(a, b *UnmappedField) int { return strings.Compare(a.Field, b.Field) })
+	return fields
+}
+
+// status maps the result status of a tool to the status of a test result. Statuses this service
+// has no equivalent of are skipped, and reported under field
+func status(value, field string, u unmapped) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "pass", "passed":
+		return "passed"
+	case "fail", "failed":
+		return "failed"
+	case "blocked":
+		return "blocked"
+	case "skip", "skipped", "untested", "not executed", "todo":
+		return "skipped"
+	}
+	u.add(field, value)
+	return "skipped"
+}
+
+// splitSteps pairs the lines of a text of actions with the lines of a text of expected results,
+// dropping the numbering tools put before steps
+func splitSteps(actions, expected string) []*Step {
+	actionLines, expectedLines := lines(actions), lines(expected)
+	steps := make([]*Step, 0, max(len(actionLines), len(expectedLines)))
+	for i := range max(len(actionLines), len(expectedLines)) {
+		step := &Step{}
+		if i < len(actionLines) {
+			step.Action = actionLines[i]
+		}
+		if i < len(expectedLines) {
+			step.Expected = expectedLines[i]
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func lines(text string) []string {
+	var kept []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = stepNumber.ReplaceAllString(strings.TrimSpace(line), ""); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// withData adds the test data of a step to its action
+func withData(action, data string) string {
+	if data = strings.TrimSpace(data); data == "" {
+		return action
+	}
+	return fmt.Sprintf("%s\nTest data: %s", action, data)
+}
+
+// section turns a folder path into a section, levels separated by /
+func section(path string) string {
+	return strings.Trim(strings.TrimSpace(path), "/")
+}
@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func at(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		format  string
+		fixture string
+		want    *Export
+	}{
+		{
+			name:    "TestRail XML",
+			source:  SourceTestRail,
+			format:  FormatXML,
+			fixture: "testrail.xml",
+			want: &Export{
+				Suites: []*Suite{{SourceID: "S12", Name: "Checkout", Description: "Orders placed from the web shop"}},
+				Cases: []*Case{
+					{
+						SourceID: "C101", Key: "C101", SuiteID: "S12", Title: "Add an item to the cart",
+						Section: "Cart", Priority: "High", Preconditions: "A signed in customer",
+						Steps: []*Step{
+							{Action: "Open a product page", Expected: "The product is shown"},
+							{Action: "Click Add to cart\nTest data: Quantity 2", Expected: "The cart holds 2 items"},
+						},
+						CustomFields: map[string]string{"automation_type": "Selenium"},
+					},
+					{
+						SourceID: "C102", Key: "C102", SuiteID: "S12", Title: "Apply a coupon",
+						Section: "Cart/Coupons", Priority: "Medium",
+						Steps: []*Step{
+							{Action: "Enter SAVE10", Expected: "The total drops by 10%"},
+							{Action: "Click Apply"},
+						},
+						CustomFields: map[string]string{"browsers": "Chrome, Firefox"},
+					},
+				},
+				Unmapped: []*UnmappedField{
+					{Field: "case.estimate", Count: 1, Samples: []string{"2m"}},
+					{Field: "case.template", Count: 1, Samples: []string{"Test Case (Steps)"}},
+					{Field: "case.type", Count: 2, Samples: []string{"Functional", "Regression"}},
+					{Field: "section.description", Count: 1, Samples: []string{"Adding and removing items"}},
+				},
+			},
+		},
+		{
+			name:    "Zephyr Scale JSON",
+			source:  SourceZephyr,
+			format:  FormatJSON,
+			fixture: "zephyr.json",
+			want: &Export{
+				Cases: []*Case{
+					{
+						SourceID: "SHOP-T1", Key: "SHOP-T1", Title: "Pay by card", Section: "Payments/Cards",
+						Priority: "High", Preconditions: "A cart with one item", Description: "Card payments are accepted",
+						Tags: []string{"payments", " smoke "},
+						Steps: []*Step{
+							{Action: "Enter the card number\nTest data: 4111 1111 1111 1111", Expected: "The card is accepted"},
+							{Action: "Confirm the order", Expected: "The order is placed"},
+						},
+						CustomFields: map[string]string{"Component": "Billing", "Automated": "true"},
+					},
+					{
+						SourceID: "SHOP-T2", Key: "SHOP-T2", Title: "Pay by invoice", Section: "Payments", Priority: "Low",
+						Steps:        []*Step{{Action: "Choose invoice"}, {Action: "Confirm the order"}},
+						CustomFields: map[string]string{},
+					},
+				},
+				Runs: []*Run{{
+					SourceID: "SHOP-C7", Name: "Release 2.4", ClosedAt: at("2026-03-02T11:30:00Z"),
+					Results: []*Result{
+						{CaseKey: "SHOP-T1", Status: "passed", DurationMs: 42000},
+						{CaseKey: "SHOP-T2", Status: "skipped", Message: "Waiting for the invoice service"},
+					},
+				}},
+				Unmapped: []*UnmappedField{
+					{Field: "testCases.owner", Count: 1, Samples: []string{"5b10a2844c20165700ede21g"}},
+					{Field: "testCases.status", Count: 1, Samples: []string{"Approved"}},
+					{Field: "testRuns.items.status", Count: 1, Samples: []string{"In Progress"}},
+					{Field: "testRuns.plannedStartDate", Count: 1, Samples: []string{"2026-03-01T09:00:00Z"}},
+				},
+			},
+		},
+		{
+			name:    "Xray JSON",
+			source:  SourceXray,
+			format:  FormatJSON,
+			fixture: "xray.json",
+			want: &Export{
+				Cases: []*Case{
+					{
+						SourceID: "10100", Key: "ACC-7", Title: "Sign in with a password", Section: "Accounts/Sign in",
+						Priority: "Highest", Description: "Customers sign in with their email", Tags: []string{"auth"},
+						Preconditions: "A registered customer\n\nThe customer is signed out",
+						Steps: []*Step{{
+							Action:   "Enter the email and password\nTest data: ada@example.com",
+							Expected: "The account page is shown",
+						}},
+						CustomFields: map[string]string{"customfield_10200": "Web"},
+					},
+					{
+						SourceID: "10101", Key: "ACC-8", Title: "Reset a password", Section: "Accounts",
+						Steps: []*Step{{
+							Action: "Given a registered customer\nWhen they reset their password\nThen they get an email",
+						}},
+						CustomFields: map[string]string{},
+					},
+				},
+				Runs: []*Run{{
+					SourceID: "10300", Name: "Sprint 14 regression", ClosedAt: at("2026-04-01T08:06:00Z"),
+					Results: []*Result{
+						{CaseKey: "ACC-7", Status: "passed", DurationMs: 90000},
+						{CaseKey: "ACC-8", Status: "failed", DurationMs: 60000, Message: "No email sent"},
+					},
+				}},
+				Unmapped: []*UnmappedField{
+					{Field: "testExecutions.testRuns.defects", Count: 1, Samples: []string{"ACC-31"}},
+					{Field: "tests.jira.assignee", Count: 1, Samples: []string{"Ada Lovelace"}},
+					{Field: "tests.jira.description", Count: 1,
+						Samples: []string{`{"content":[],"type":"doc","version":1}`}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := Parse(tt.source, tt.format, bytes.NewReader(readFixture(t, tt.fixture)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(export, tt.want) {
+				got, _ := json.MarshalIndent(export, "", "  ")
+				want, _ := json.MarshalIndent(tt.want, "", "  ")
+				t.Errorf("export = %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+// TestParseReimport parses an export again after it changed in the tool, the items keep their IDs
+// in the tool so that the import finds what they were imported as
+func TestParseReimport(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		fixture string
+		edit    func(string) string
+		changed string
+	}{
+		{
+			name:    "a TestRail case moved to another section",
+			source:  SourceTestRail,
+			fixture: "testrail.xml",
+			edit:    func(s string) string { return strings.Replace(s, "<name>Coupons</name>", "<name>Discounts</name>", 1) },
+			changed: "C102",
+		},
+		{
+			name:    "a Zephyr Scale case renamed",
+			source:  SourceZephyr,
+			fixture: "zephyr.json",
+			edit:    func(s string) string { return strings.Replace(s, `"Pay by card"`, `"Pay by credit card"`, 1) },
+			changed: "SHOP-T1",
+		},
+		{
+			name:    "an Xray test with another step",
+			source:  SourceXray,
+			fixture: "xray.json",
+			edit: func(s string) string {
+				return strings.Replace(s, `"steps": [`,
+					`"steps": [{"action": "Open the sign in page", "data": "", "result": ""}, `, 1)
+			},
+			changed: "10100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := string(readFixture(t, tt.fixture))
+			first, err := Parse(tt.source, DefaultFormat(tt.source), strings.NewReader(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := Parse(tt.source, DefaultFormat(tt.source), strings.NewReader(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(first, again) {
+				t.Fatal("parsing the same export twice gave different items")
+			}
+
+			edited, err := Parse(tt.source, DefaultFormat(tt.source), strings.NewReader(tt.edit(content)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(edited.Cases) != len(first.Cases) {
+				t.Fatalf("cases = %d, want %d", len(edited.Cases), len(first.Cases))
+			}
+			for i, c := range edited.Cases {
+				if c.SourceID != first.Cases[i].SourceID || c.Key != first.Cases[i].Key {
+					t.Errorf("case %d is %s (%s), want %s (%s)", i, c.SourceID, c.Key,
+						first.Cases[i].SourceID, first.Cases[i].Key)
+				}
+				if changed := !reflect.DeepEqual(c, first.Cases[i]); changed != (c.SourceID == tt.changed) {
+					t.Errorf("case %s changed = %v", c.SourceID, changed)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRefuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		format  string
+		content string
+		wantErr error
+	}{
+		{name: "formats the tool has no export in", source: SourceZephyr, format: FormatXML, wantErr: ErrUnsupportedFormat},
+		{name: "unknown tools", source: "qtest", format: FormatJSON, wantErr: ErrUnsupportedFormat},
+		{name: "malformed XML", source: SourceTestRail, format: FormatXML, content: "<suite><sections>"},
+		{name: "malformed JSON", source: SourceXray, format: FormatJSON, content: `{"tests": [`},
+		{name: "items of the wrong type", source: SourceZephyr, format: FormatJSON, content: `{"testCases": [{"key": 7}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source, tt.format, strings.NewReader(tt.content))
+			if err == nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
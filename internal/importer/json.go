@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// decodeObject reads the JSON object raw into v, reporting the keys not in known as unmapped
+// fields of path. Keys are checked apart since v keeps only the fields it has
+func decodeObject(raw json.RawMessage, v any, path string, known []string, u unmapped) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("importer: %s: %w", path, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("importer: %s: %w", path, err)
+	}
+	for key, value := range fields {
+		if !slices.Contains(known, key) {
+			u.add(path+"."+key, text(value))
+		}
+	}
+	return nil
+}
+
+// text returns a JSON value as text: strings as they are, the names or values of objects the
+// tools use for options, lists joined by commas and other values as written
+func text(raw json.RawMessage) string {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+	return valueText(value)
+}
+
+func valueText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := valueText(item); s != "" {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, ", ")
+	case map[string]any:
+		for _, key := range []string{"name", "value", "displayName"} {
+			if s, ok := v[key]; ok {
+				return valueText(s)
+			}
+		}
+	}
+	written, _ := json.Marshal(value)
+	return string(written)
+}
+
+// timestamp reads an RFC 3339 time, nil when value is not one
+func timestamp(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// latest returns the later of two times, either may be nil
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || b != nil && b.After(*a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// trSuite is the root of a TestRail XML export, sections nest as deep as in TestRail
+type trSuite struct {
+	ID          string       `xml:"id"`
+	Name        string       `xml:"name"`
+	Description string       `xml:"description"`
+	Sections    []*trSection `xml:"sections>section"`
+}
+
+type trSection struct {
+	Name     string       `xml:"name"`
+	Cases    []*trCase    `xml:"cases>case"`
+	Sections []*trSection `xml:"sections>section"`
+	Other    []*trNode    `xml:",any"`
+}
+
+type trCase struct {
+	ID       string `xml:"id"`
+	Title    string `xml:"title"`
+	Priority string `xml:"priority"`
+	Custom   struct {
+		Fields []*trNode `xml:",any"`
+	} `xml:"custom"`
+	// Other holds the fields TestRail has and cases here have not, e.g. type or estimate
+	Other []*trNode `xml:",any"`
+}
+
+// trNode is an element of a TestRail export read as is
+type trNode struct {
+	XMLName xml.Name
+	Text    string    `xml:",chardata"`
+	Nodes   []*trNode `xml:",any"`
+}
+
+// value is the text of a field. Dropdowns hold their value apart from their ID, multi-selects
+// hold one item per value
+func (n *trNode) value() string {
+	if len(n.Nodes) == 0 {
+		return strings.TrimSpace(n.Text)
+	}
+	var values []string
+	for _, child := range n.Nodes {
+		if name := child.XMLName.Local; name == "value" || name == "item" {
+			if value := child.value(); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func (n *trNode) child(name string) string {
+	for _, child := range n.Nodes {
+		if child.XMLName.Local == name {
+			return child.value()
+		}
+	}
+	return ""
+}
+
+// parseTestRailXML reads the XML export of a TestRail suite
+func parseTestRailXML(r io.Reader) (*Export, error) {
+	var suite trSuite
+	if err := xml.NewDecoder(r).Decode(&suite); err != nil {
+		return nil, fmt.Errorf("importer: %w", err)
+	}
+
+	export := &Export{}
+	u := make(unmapped)
+	suiteID := strings.TrimSpace(suite.ID)
+	if suiteID == "" {
+		suiteID = strings.TrimSpace(suite.Name)
+	}
+	if suiteID != "" {
+		export.Suites = append(export.Suites, &Suite{
+			SourceID: suiteID, Name: strings.TrimSpace(suite.Name), Description: strings.TrimSpace(suite.Description),
+		})
+	}
+
+	var walk func(sections []*trSection, parent string)
+	walk = func(sections []*trSection, parent string) {
+		for _, s := range sections {
+			path := strings.TrimSpace(s.Name)
+			if parent != "" {
+				path = parent + "/" + path
+			}
+			for _, other := range s.Other {
+				u.add("section."+other.XMLName.Local, other.value())
+			}
+			for _, c := range s.Cases {
+				export.Cases = append(export.Cases, testRailCase(c, suiteID, path, u))
+			}
+			walk(s.Sections, path)
+		}
+	}
+	walk(suite.Sections, "")
+
+	export.Unmapped = u.sorted()
+	return export, nil
+}
+
+// testRailCase reads a case of a TestRail XML export filed under section
+func testRailCase(c *trCase, suiteID, section string, u unmapped) *Case {
+	id := strings.TrimSpace(c.ID)
+	testCase := &Case{
+		SourceID:     id,
+		Key:          id,
+		SuiteID:      suiteID,
+		Title:        c.Title,
+		Section:      section,
+		Priority:     c.Priority,
+		CustomFields: make(map[string]string),
+	}
+	for _, other := range c.Other {
+		u.add("case."+other.XMLName.Local, other.value())
+	}
+
+	var actions, expected string
+	for _, field := range c.Custom.Fields {
+		switch name := field.XMLName.Local; name {
+		case "preconds":
+			testCase.Preconditions = field.value()
+		case "steps":
+			actions = field.value()
+		case "expected":
+			expected = field.value()
+		case "steps_separated":
+			for _, step := range field.Nodes {
+				testCase.Steps = append(testCase.Steps, &Step{
+					Action: withData(step.child("content"), step.child("additional_info")), Expected: step.child("expected"),
+				})
+			}
+		case "mission", "goals":
+			testCase.Description = strings.TrimSpace(testCase.Description + "\n\n" + field.value())
+		default:
+			if value := field.value(); value != "" {
+				testCase.CustomFields[name] = value
+			}
+		}
+	}
+	if len(testCase.Steps) == 0 {
+		testCase.Steps = splitSteps(actions, expected)
+	}
+	return testCase
+}
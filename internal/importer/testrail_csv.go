@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/project-weekend/qms-engine/internal/spreadsheet"
+)
+
+// testRailUnmapped are the columns of TestRail CSV exports cases here have no field for, the
+// columns that are neither these nor mapped are custom fields
+var testRailUnmapped = map[string]bool{
+	"type": true, "template": true, "estimate": true, "forecast": true, "milestone": true, "references": true,
+	"created by": true, "created on": true, "updated by": true, "updated on": true, "section depth": true,
+	"section description": true, "assigned to": true, "tested by": true, "tested on": true, "defects": true,
+	"version": true, "steps (additional info)": true,
+}
+
+// testRailMapped are the columns of TestRail CSV exports read into cases and results
+var testRailMapped = map[string]bool{
+	"id": true, "case id": true, "title": true, "section": true, "section hierarchy": true, "suite": true,
+	"suite id": true, "priority": true, "preconditions": true, "steps": true, "expected result": true,
+	"steps (step)": true, "steps (expected result)": true, "mission": true,
+	"goals": true, "run": true, "run id": true, "status": true, "elapsed": true, "comment": true,
+}
+
+// trRow reads the cells of a row of a TestRail CSV export by column name
+type trRow struct {
+	header []string
+	cells  []string
+}
+
+func (r *trRow) get(column string) string {
+	for i, name := range r.header {
+		if name == column && i < len(r.cells) {
+			return strings.TrimSpace(r.cells[i])
+		}
+	}
+	return ""
+}
+
+// parseTestRailCSV reads a CSV export of TestRail cases. An export of the tests of runs, with
+// a Run and a Status column, holds the results of the runs besides their cases
+func parseTestRailCSV(r io.Reader) (*Export, error) {
+	rows, err := spreadsheet.Read(r, spreadsheet.FormatCSV, math.MaxInt)
+	if err != nil {
+		return nil, fmt.Errorf("importer: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("importer: the export has no header row")
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	export := &Export{}
+	u := make(unmapped)
+	suites := make(map[string]bool)
+	cases := make(map[string]bool)
+	runs := make(map[string]*Run)
+	for _, cells := range rows[1:] {
+		if spreadsheet.IsBlank(cells) {
+			continue
+		}
+		row := &trRow{header: header, cells: cells}
+		testCase := testRailCSVCase(row, rows[0], u)
+		if testCase.SuiteID != "" && !suites[testCase.SuiteID] {
+			suites[testCase.SuiteID] = true
+			export.Suites = append(export.Suites, &Suite{SourceID: testCase.SuiteID, Name: row.get("suite")})
+		}
+
+		if row.get("run") == "" || row.get("status") == "" {
+			export.Cases = append(export.Cases, testCase)
+			continue
+		}
+		// The tests of runs repeat their case in every run
+		if !cases[testCase.Key] {
+			cases[testCase.Key] = true
+			export.Cases = append(export.Cases, testCase)
+		}
+		addResult(export, runs, row, testCase.Key, u)
+	}
+
+	export.Unmapped = u.sorted()
+	return export, nil
+}
+
+// addResult adds the result of a row to its run, runs maps the runs read so far by ID
+func addResult(export *Export, runs map[string]*Run, row *trRow, caseKey string, u unmapped) {
+	runID := cmp.Or(row.get("run id"), row.get("run"))
+	run, ok := runs[runID]
+	if !ok {
+		run = &Run{SourceID: runID, Name: row.get("run")}
+		runs[runID] = run
+		export.Runs = append(export.Runs, run)
+	}
+	run.Results = append(run.Results, &Result{
+		CaseKey:    caseKey,
+		Status:     status(row.get("status"), "Status", u),
+		DurationMs: elapsed(row.get("elapsed")),
+		Message:    row.get("comment"),
+	})
+}
+
+// testRailCSVCase reads the case of a row, names are the headers as written in the export
+func testRailCSVCase(row *trRow, names []string, u unmapped) *Case {
+	id := row.get("case id")
+	if id == "" {
+		id = row.get("id")
+	}
+	// Exports write IDs with or without their prefix depending on the options
+	if id != "" && !strings.HasPrefix(strings.ToUpper(id), "C") {
+		id = "C" + id
+	}
+
+	testCase := &Case{
+		SourceID:      id,
+		Key:           id,
+		SuiteID:       cmp.Or(row.get("suite id"), row.get("suite")),
+		Title:         row.get("title"),
+		Section:       cmp.Or(strings.ReplaceAll(row.get("section hierarchy"), " > ", "/"), row.get("section")),
+		Priority:      row.get("priority"),
+		Preconditions: row.get("preconditions"),
+		Description:   strings.TrimSpace(row.get("mission") + "\n\n" + row.get("goals")),
+		Steps: splitSteps(cmp.Or(row.get("steps (step)"), row.get("steps")),
+			cmp.Or(row.get("steps (expected result)"), row.get("expected result"))),
+		CustomFields: make(map[string]string),
+	}
+	for i, column := range row.header {
+		value := ""
+		if i < len(row.cells) {
+			value = strings.TrimSpace(row.cells[i])
+		}
+		switch {
+		case testRailMapped[column]:
+		case testRailUnmapped[column]:
+			u.add(strings.TrimSpace(names[i]), value)
+		case value != "":
+			testCase.CustomFields[strings.TrimSpace(names[i])] = value
+		}
+	}
+	return testCase
+}
+
+// elapsed reads the elapsed time of a TestRail test, e.g. "1m 30s". Days and weeks, which
+// TestRail counts in working hours, are left out
+func elapsed(value string) int64 {
+	duration, err := time.ParseDuration(strings.ReplaceAll(value, " ", ""))
+	if err != nil {
+		return 0
+	}
+	return duration.Milliseconds()
+}
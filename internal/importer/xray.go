@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xrayExport holds the tests and test executions of Xray, as returned by its GraphQL API
+// (getTests and getTestExecutions), in one document
+type xrayExport struct {
+	Tests          []json.RawMessage `json:"tests"`
+	TestExecutions []json.RawMessage `json:"testExecutions"`
+}
+
+type xrayTest struct {
+	IssueID  string                     `json:"issueId"`
+	Jira     map[string]json.RawMessage `json:"jira"`
+	TestType struct {
+		Name string `json:"name"`
+	} `json:"testType"`
+	Folder struct {
+		Path string `json:"path"`
+	} `json:"folder"`
+	Steps []struct {
+		Action string `json:"action"`
+		Data   string `json:"data"`
+		Result string `json:"result"`
+	} `json:"steps"`
+	Gherkin       string `json:"gherkin"`
+	Unstructured  string `json:"unstructured"`
+	Preconditions struct {
+		Results []struct {
+			Definition string `json:"definition"`
+		} `json:"results"`
+	} `json:"preconditions"`
+}
+
+var xrayTestFields = []string{"issueId", "projectId", "jira", "testType", "folder", "steps", "gherkin",
+	"unstructured", "preconditions"}
+
+type xrayExecution struct {
+	IssueID string `json:"issueId"`
+	Jira    struct {
+		Key     string `json:"key"`
+		Summary string `json:"summary"`
+	} `json:"jira"`
+	TestRuns struct {
+		Results []json.RawMessage `json:"results"`
+	} `json:"testRuns"`
+}
+
+var xrayExecutionFields = []string{"issueId", "projectId", "jira", "testRuns"}
+
+type xrayRun struct {
+	Test struct {
+		IssueID string `json:"issueId"`
+		Jira    struct {
+			Key string `json:"key"`
+		} `json:"jira"`
+	} `json:"test"`
+	Status struct {
+		Name string `json:"name"`
+	} `json:"status"`
+	Comment    string `json:"comment"`
+	StartedOn  string `json:"startedOn"`
+	FinishedOn string `json:"finishedOn"`
+}
+
+var xrayRunFields = []string{"id", "test", "status", "comment", "startedOn", "finishedOn"}
+
+// parseXray reads an Xray export. Tests are Jira issues: their summary is the title, their labels
+// the tags and their custom fields the custom fields. The folder of a test in the test repository
+// is its section, Xray has no suites
+func parseXray(r io.Reader) (*Export, error) {
+	var document xrayExport
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("importer: %w", err)
+	}
+
+	export := &Export{}
+	u := make(unmapped)
+	// keys maps the issue ID of a test to its key, results may name a test by either
+	keys := make(map[string]string, len(document.Tests))
+	for _, raw := range document.Tests {
+		var test xrayTest
+		if err := decodeObject(raw, &test, "tests", xrayTestFields, u); err != nil {
+			return nil, err
+		}
+		testCase := xrayTestCase(&test, u)
+		keys[test.IssueID] = testCase.Key
+		export.Cases = append(export.Cases, testCase)
+	}
+	for _, raw := range document.TestExecutions {
+		run, err := xrayTestRun(raw, keys, u)
+		if err != nil {
+			return nil, err
+		}
+		export.Runs = append(export.Runs, run)
+	}
+
+	export.Unmapped = u.sorted()
+	return export, nil
+}
+
+func xrayTestCase(test *xrayTest, u unmapped) *Case {
+	testCase := &Case{
+		SourceID:     test.IssueID,
+		Section:      section(test.Folder.Path),
+		CustomFields: make(map[string]string),
+	}
+	for name, value := range test.Jira {
+		switch {
+		case name == "key":
+			testCase.Key = text(value)
+		case name == "summary":
+			testCase.Title = text(value)
+		case name == "description":
+			// Jira Cloud writes descriptions as documents, only plain text is kept
+			if err := json.Unmarshal(value, &testCase.Description); err != nil {
+				u.add("tests.jira.description", text(value))
+			}
+		case name == "priority":
+			testCase.Priority = text(value)
+		case name == "labels":
+			_ = json.Unmarshal(value, &testCase.Tags)
+		case strings.HasPrefix(name, "customfield_"):
+			if value := text(value); value != "" {
+				testCase.CustomFields[name] = value
+			}
+		default:
+			u.add("tests.jira."+name, text(value))
+		}
+	}
+	if testCase.SourceID == "" {
+		testCase.SourceID = testCase.Key
+	}
+
+	var preconditions []string
+	for _, precondition := range test.Preconditions.Results {
+		preconditions = append(preconditions, strings.TrimSpace(precondition.Definition))
+	}
+	testCase.Preconditions = strings.Join(preconditions, "\n\n")
+	for _, step := range test.Steps {
+		testCase.Steps = append(testCase.Steps, &Step{Action: withData(step.Action, step.Data), Expected: step.Result})
+	}
+	// Cucumber and generic tests have a definition instead of steps
+	definition := strings.TrimSpace(test.Gherkin + test.Unstructured)
+	if len(testCase.Steps) == 0 && definition != "" {
+		testCase.Steps = []*Step{{Action: definition}}
+	}
+	return testCase
+}
+
+func xrayTestRun(raw json.RawMessage, keys map[string]string, u unmapped) (*Run, error) {
+	var execution xrayExecution
+	if err := decodeObject(raw, &execution, "testExecutions", xrayExecutionFields, u); err != nil {
+		return nil, err
+	}
+	run := &Run{
+		SourceID: execution.IssueID,
+		Name:     execution.Jira.Summary,
+	}
+	if run.SourceID == "" {
+		run.SourceID = execution.Jira.Key
+	}
+	if run.Name == "" {
+		run.Name = execution.Jira.Key
+	}
+
+	for _, rawRun := range execution.TestRuns.Results {
+		var testRun xrayRun
+		if err := decodeObject(rawRun, &testRun, "testExecutions.testRuns", xrayRunFields, u); err != nil {
+			return nil, err
+		}
+		caseKey := testRun.Test.Jira.Key
+		if caseKey == "" {
+			caseKey = keys[testRun.Test.IssueID]
+		}
+		result := &Result{
+			CaseKey: caseKey,
+			Status:  status(testRun.Status.Name, "testExecutions.testRuns.status", u),
+			Message: testRun.Comment,
+		}
+		started, finished := timestamp(testRun.StartedOn), timestamp(testRun.FinishedOn)
+		if started != nil && finished != nil && finished.After(*started) {
+			result.DurationMs = finished.Sub(*started).Milliseconds()
+		}
+		run.ClosedAt = latest(run.ClosedAt, finished)
+		run.Results = append(run.Results, result)
+	}
+	return run, nil
+}
@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// zephyrExport holds the test cases and test runs (cycles) of Zephyr Scale, as returned by its
+// REST API searches, in one document
+type zephyrExport struct {
+	TestCases []json.RawMessage `json:"testCases"`
+	TestRuns  []json.RawMessage `json:"testRuns"`
+}
+
+type zephyrCase struct {
+	Key          string                     `json:"key"`
+	Name         string                     `json:"name"`
+	Objective    string                     `json:"objective"`
+	Precondition string                     `json:"precondition"`
+	Priority     string                     `json:"priority"`
+	Folder       string                     `json:"folder"`
+	Labels       []string                   `json:"labels"`
+	CustomFields map[string]json.RawMessage `json:"customFields"`
+	TestScript   struct {
+		Type  string `json:"type"`
+		Text  string `json:"text"`
+		Steps []struct {
+			Description    string `json:"description"`
+			TestData       string `json:"testData"`
+			ExpectedResult string `json:"expectedResult"`
+		} `json:"steps"`
+	} `json:"testScript"`
+}
+
+// zephyrCaseFields are the keys of a case that are read or carry nothing to import
+var zephyrCaseFields = []string{"id", "projectKey", "key", "name", "objective", "precondition", "priority",
+	"folder", "labels", "customFields", "testScript"}
+
+type zephyrRun struct {
+	Key   string            `json:"key"`
+	Name  string            `json:"name"`
+	Items []json.RawMessage `json:"items"`
+}
+
+var zephyrRunFields = []string{"id", "projectKey", "key", "name", "items"}
+
+type zephyrItem struct {
+	TestCaseKey   string `json:"testCaseKey"`
+	Status        string `json:"status"`
+	ExecutionTime int64  `json:"executionTime"`
+	Comment       string `json:"comment"`
+	ActualEndDate string `json:"actualEndDate"`
+}
+
+var zephyrItemFields = []string{"id", "testCaseKey", "status", "executionTime", "comment", "actualEndDate"}
+
+// parseZephyr reads a Zephyr Scale export. Zephyr Scale has folders but no suites, the folder
+// of a case is its section
+func parseZephyr(r io.Reader) (*Export, error) {
+	var document zephyrExport
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("importer: %w", err)
+	}
+
+	export := &Export{}
+	u := make(unmapped)
+	for _, raw := range document.TestCases {
+		var c zephyrCase
+		if err := decodeObject(raw, &c, "testCases", zephyrCaseFields, u); err != nil {
+			return nil, err
+		}
+		export.Cases = append(export.Cases, zephyrTestCase(&c))
+	}
+	for _, raw := range document.TestRuns {
+		run, err := zephyrTestRun(raw, u)
+		if err != nil {
+			return nil, err
+		}
+		export.Runs = append(export.Runs, run)
+	}
+
+	export.Unmapped = u.sorted()
+	return export, nil
+}
+
+func zephyrTestCase(c *zephyrCase) *Case {
+	testCase := &Case{
+		SourceID:      c.Key,
+		Key:           c.Key,
+		Title:         c.Name,
+		Section:       section(c.Folder),
+		Priority:      c.Priority,
+		Preconditions: c.Precondition,
+		Description:   c.Objective,
+		Tags:          c.Labels,
+		CustomFields:  make(map[string]string, len(c.CustomFields)),
+	}
+	for name, value := range c.CustomFields {
+		if value := text(value); value != "" {
+			testCase.CustomFields[name] = value
+		}
+	}
+	if strings.EqualFold(c.TestScript.Type, "PLAIN_TEXT") {
+		testCase.Steps = splitSteps(c.TestScript.Text, "")
+	}
+	for _, step := range c.TestScript.Steps {
+		testCase.Steps = append(testCase.Steps, &Step{
+			Action: withData(step.Description, step.TestData), Expected: step.ExpectedResult,
+		})
+	}
+	return testCase
+}
+
+func zephyrTestRun(raw json.RawMessage, u unmapped) (*Run, error) {
+	var r zephyrRun
+	if err := decodeObject(raw, &r, "testRuns", zephyrRunFields, u); err != nil {
+		return nil, err
+	}
+	run := &Run{SourceID: r.Key, Name: r.Name}
+	for _, rawItem := range r.Items {
+		var item zephyrItem
+		if err := decodeObject(rawItem, &item, "testRuns.items", zephyrItemFields, u); err != nil {
+			return nil, err
+		}
+		run.ClosedAt = latest(run.ClosedAt, timestamp(item.ActualEndDate))
+		run.Results = append(run.Results, &Result{
+			CaseKey:    item.TestCaseKey,
+			Status:     status(item.Status, "testRuns.items.status", u),
+			DurationMs: item.ExecutionTime,
+			Message:    item.Comment,
+		})
+	}
+	return run, nil
+}
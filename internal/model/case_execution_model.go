@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// StartCaseRequest takes the soft lock on a case, Force takes it over from another tester
+type StartCaseRequest struct {
+	CaseKey string `json:"caseKey" normalize:"trim,nfc" validate:"required,max=255,no_control_chars"`
+	Force   bool   `json:"force"`
+}
+
+// RecordStepRequest records the result of a step of a case, replacing the one recorded before
+type RecordStepRequest struct {
+	CaseKey string `json:"caseKey" normalize:"trim,nfc" validate:"required,max=255,no_control_chars"`
+	Step    int    `json:"step" validate:"gt=0,lte=1000"`
+	Status  string `json:"status" normalize:"trim,lower" validate:"required,oneof=passed failed skipped blocked"`
+	Note    string `json:"note" validate:"max=65535"`
+}
+
+// RecordCaseResultRequest records the result of a case. Version is the version of the execution
+// the tester saw, zero when no result was shown, a result recorded since then is a conflict
+type RecordCaseResultRequest struct {
+	CaseKey    string `json:"caseKey" normalize:"trim,nfc" validate:"required,max=255,no_control_chars"`
+	Status     string `json:"status" normalize:"trim,lower" validate:"required,oneof=passed failed skipped blocked"`
+	Message    string `json:"message" validate:"max=65535"`
+	DurationMs int64  `json:"durationMs" validate:"gte=0"`
+	Version    int    `json:"version" validate:"gte=0"`
+}
+
+// AddCommentRequest comments on a case, or on the run without CaseKey
+type AddCommentRequest struct {
+	CaseKey string `json:"caseKey" normalize:"trim,nfc" validate:"max=255,no_control_chars"`
+	Body    string `json:"body" normalize:"trim" validate:"required,max=65535"`
+}
+
+// CaseExecutionResponse describes the manual execution of a case, Version counts its recorded results
+type CaseExecutionResponse struct {
+	RunID      int                 `json:"runId"`
+	CaseKey    string              `json:"caseKey"`
+	Status     string              `json:"status"`
+	Message    string              `json:"message"`
+	DurationMs int64               `json:"durationMs"`
+	RecordedBy string              `json:"recordedBy"`
+	Version    int                 `json:"version"`
+	Steps      []*CaseStepResponse `json:"steps,omitempty"`
+	UpdatedAt  time.Time           `json:"updatedAt"`
+}
+
+type CaseStepResponse struct {
+	CaseKey   string    `json:"caseKey"`
+	Step      int       `json:"step"`
+	Status    string    `json:"status"`
+	Note      string    `json:"note"`
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RunCommentResponse struct {
+	ID        int       `json:"id"`
+	RunID     int       `json:"runId"`
+	CaseKey   string    `json:"caseKey,omitempty"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RunSessionSnapshot is the state of a run a tester joining its session starts from
+type RunSessionSnapshot struct {
+	Run        *TestRunResponse         `json:"run"`
+	Executions []*CaseExecutionResponse `json:"executions"`
+	// Comments are the latest comments, oldest first
+	Comments []*RunCommentResponse `json:"comments"`
+}
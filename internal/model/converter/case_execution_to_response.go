@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+func CaseExecutionToResponse(entity *entity.CaseExecution) *model.CaseExecutionResponse {
+	return &model.CaseExecutionResponse{
+		RunID:      entity.RunID,
+		CaseKey:    entity.CaseKey,
+		Status:     entity.Status,
+		Message:    entity.Message,
+		DurationMs: entity.DurationMs,
+		RecordedBy: entity.RecordedBy,
+		Version:    entity.Version,
+		UpdatedAt:  entity.UpdatedAt,
+	}
+}
+
+func CaseStepToResponse(caseKey string, entity *entity.CaseExecutionStep) *model.CaseStepResponse {
+	return &model.CaseStepResponse{
+		CaseKey:   caseKey,
+		Step:      entity.Step,
+		Status:    entity.Status,
+		Note:      entity.Note,
+		UpdatedBy: entity.UpdatedBy,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func RunCommentToResponse(entity *entity.RunComment) *model.RunCommentResponse {
+	return &model.RunCommentResponse{
+		ID:        entity.ID,
+		RunID:     entity.RunID,
+		CaseKey:   entity.CaseKey,
+		Author:    entity.Author,
+		Body:      entity.Body,
+		CreatedAt: entity.CreatedAt,
+	}
+}
@@ -8,7 +8,23 @@ import (
 func ProjectToResponse(entity *entity.Project) *model.CreateProjectResponse {
 	return &model.CreateProjectResponse{
 		ID:        entity.ID,
+		Version:   entity.Version,
 		CreatedAt: entity.CreatedAt,
 		UpdatedAt: entity.UpdatedAt,
 	}
 }
+
+func ProjectToDetailResponse(entity *entity.Project) *model.ProjectResponse {
+	response := &model.ProjectResponse{
+		ID:          entity.ID,
+		Name:        entity.Name,
+		Description: entity.Description,
+		Version:     entity.Version,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+	if entity.Key != nil {
+		response.Key = *entity.Key
+	}
+	return response
+}
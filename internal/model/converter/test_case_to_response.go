@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+func TestSuiteToResponse(entity *entity.TestSuite) *model.TestSuiteResponse {
+	return &model.TestSuiteResponse{
+		ID:          entity.ID,
+		ProjectID:   entity.ProjectID,
+		Name:        entity.Name,
+		Description: entity.Description,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+// TestCaseToResponse splits the stored tags and decodes the custom fields, steps are left to the caller
+func TestCaseToResponse(entity *entity.TestCase) *model.TestCaseResponse {
+	response := &model.TestCaseResponse{
+		ID:            entity.ID,
+		ProjectID:     entity.ProjectID,
+		SuiteID:       entity.SuiteID,
+		ExternalID:    entity.ExternalID,
+		Title:         entity.Title,
+		Section:       entity.Section,
+		Priority:      entity.Priority,
+		Preconditions: entity.Preconditions,
+		Description:   entity.Description,
+		Tags:          []string{},
+		CustomFields:  map[string]string{},
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+	if entity.Tags != "" {
+		response.Tags = strings.Split(entity.Tags, ",")
+	}
+	if entity.CustomFields != nil {
+		// Written by the imports from a map of strings, it always decodes
+		_ = json.Unmarshal([]byte(*entity.CustomFields), &response.CustomFields)
+	}
+	return response
+}
+
+func TestStepToResponse(entity *entity.TestCaseStep) *model.TestStepResponse {
+	return &model.TestStepResponse{
+		Position: entity.Position,
+		Action:   entity.Action,
+		Expected: entity.Expected,
+	}
+}
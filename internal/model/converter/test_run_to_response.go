@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+func TestRunToResponse(entity *entity.TestRun) *model.TestRunResponse {
+	return &model.TestRunResponse{
+		ID:           entity.ID,
+		ProjectID:    entity.ProjectID,
+		Name:         entity.Name,
+		Status:       entity.Status,
+		LastSequence: entity.LastSequence,
+		ResultCount:  entity.ResultCount,
+		CreatedAt:    entity.CreatedAt,
+		UpdatedAt:    entity.UpdatedAt,
+		ClosedAt:     entity.ClosedAt,
+	}
+}
+
+func TestResultToResponse(entity *entity.TestResult) *model.TestResultResponse {
+	return &model.TestResultResponse{
+		ID:         entity.ID,
+		RunID:      entity.RunID,
+		Sequence:   entity.Sequence,
+		CaseKey:    entity.CaseKey,
+		Status:     entity.Status,
+		DurationMs: entity.DurationMs,
+		Message:    entity.Message,
+		CreatedAt:  entity.CreatedAt,
+	}
+}
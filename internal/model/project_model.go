@@ -2,13 +2,73 @@ package model
 
 import "time"
 
+// CreateProjectRequest is normalized before validation, names are unique once case folded.
+// Key is the optional prefix of the IDs of the project's test cases, unique when set
 type CreateProjectRequest struct {
-	Name        string `json:"name" validate:"required,min=5,max=50"`
-	Description string `json:"description"`
+	Name        string `json:"name" normalize:"trim,nfc,fold" validate:"required,min=5,max=50,no_control_chars"`
+	Key         string `json:"key" normalize:"trim,upper" validate:"omitempty,project_key"`
+	Description string `json:"description" normalize:"trim,nfc" validate:"max=250"`
 }
 
 type CreateProjectResponse struct {
 	ID        int       `json:"id"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+// UpdateProjectRequest changes the fields that are set, omitted fields are kept
+type UpdateProjectRequest struct {
+	Name        *string `json:"name" normalize:"trim,nfc,fold" validate:"omitempty,min=5,max=50,no_control_chars"`
+	Key         *string `json:"key" normalize:"trim,upper" validate:"omitnil,project_key"`
+	Description *string `json:"description" normalize:"trim,nfc" validate:"omitempty,max=250"`
+}
+
+type ProjectResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Key         string    `json:"key,omitempty"`
+	Description string    `json:"description"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ImportProjectRequest imports the export of another tool into the project ProjectID, or into a
+// project the import creates under Name and Description, which follow the rules of
+// CreateProjectRequest. Format defaults to the usual export of Source. With DryRun the import is
+// rolled back once reported. Transports read it from query parameters
+type ImportProjectRequest struct {
+	Source      string `form:"source" json:"source" normalize:"trim,lower" validate:"required,oneof=testrail zephyr xray"`
+	Format      string `form:"format" json:"format" normalize:"trim,lower" validate:"omitempty,oneof=xml csv json"`
+	ProjectID   int    `form:"projectId" json:"projectId" validate:"required_without=Name,excluded_with=Name,omitempty,gt=0"`
+	Name        string `form:"name" json:"name" normalize:"trim,nfc,fold" validate:"required_without=ProjectID,omitempty,min=5,max=50,no_control_chars"`
+	Description string `form:"description" json:"description" normalize:"trim,nfc" validate:"max=250"`
+	DryRun      bool   `form:"dryRun" json:"dryRun"`
+}
+
+// ImportProjectResponse reports an import. Items imported before are found by their ID in the
+// tool, they are updated when they changed and runs are kept as they are. Results counts the
+// results stored. Unmapped lists the fields of the export that were left out, with some values
+type ImportProjectResponse struct {
+	ProjectID      int              `json:"projectId"`
+	ProjectCreated bool             `json:"projectCreated"`
+	DryRun         bool             `json:"dryRun"`
+	Suites         ImportCounts     `json:"suites"`
+	Cases          ImportCounts     `json:"cases"`
+	Runs           ImportCounts     `json:"runs"`
+	Results        int              `json:"results"`
+	Unmapped       []*UnmappedField `json:"unmapped"`
+}
+
+type ImportCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+type UnmappedField struct {
+	Field   string   `json:"field"`
+	Count   int      `json:"count"`
+	Samples []string `json:"samples"`
+}
@@ -0,0 +1,19 @@
+package model
+
+const (
+	// RunEventResultRecorded is sent after a batch of uploaded results was stored
+	RunEventResultRecorded = "result-recorded"
+	// RunEventStatus carries the run after it was opened or closed. It also starts every stream
+	// that does not resume, as the state to apply later events to
+	RunEventStatus = "run-status"
+)
+
+// ResultRecordedEvent is the data of a result-recorded event
+type ResultRecordedEvent struct {
+	RunID int `json:"runId"`
+	// LastSequence and ResultCount are those of the run after the batch
+	LastSequence int64 `json:"lastSequence"`
+	ResultCount  int   `json:"resultCount"`
+	// Recorded counts the results the batch stored by status, duplicates left out
+	Recorded map[string]int `json:"recorded"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// ImportCasesRequest imports the test cases of a spreadsheet into a project. Columns maps a case
+// field to the header of the column holding it, fields left out are found by a header matching
+// their name, e.g. "External ID" for externalId. With DryRun nothing is written. Transports read
+// Columns from query parameters like columns[title]=Summary
+type ImportCasesRequest struct {
+	Format  string            `form:"format" json:"format" normalize:"trim,lower" validate:"required,oneof=csv xlsx"`
+	DryRun  bool              `form:"dryRun" json:"dryRun"`
+	Columns map[string]string `form:"-" json:"columns" validate:"dive,keys,oneof=externalId title section priority preconditions description tags steps expected,endkeys,required,max=255"`
+}
+
+// TestCaseRow is a case read from a row of a spreadsheet, its steps come from the steps and
+// expected columns, one step per line. Tags are the comma-separated values of the tags column,
+// short enough for 20 of them to be stored together
+type TestCaseRow struct {
+	ExternalID    string         `json:"externalId" normalize:"trim,nfc" validate:"required,max=255,no_control_chars"`
+	Title         string         `json:"title" normalize:"trim,nfc" validate:"required,max=255,no_control_chars"`
+	Section       string         `json:"section" normalize:"trim,nfc" validate:"max=255,no_control_chars"`
+	Priority      string         `json:"priority" normalize:"trim,nfc" validate:"max=20,no_control_chars"`
+	Preconditions string         `json:"preconditions" normalize:"trim,nfc" validate:"max=65535"`
+	Description   string         `json:"description" normalize:"trim,nfc" validate:"max=65535"`
+	Tags          []string       `json:"tags" normalize:"trim,nfc,lower" validate:"tag_list,dive,max=48"`
+	Steps         []*TestStepRow `json:"steps" validate:"max=200,dive"`
+}
+
+// TestStepRow is a step of a TestCaseRow, the lines it comes from are trimmed
+type TestStepRow struct {
+	Action   string `json:"action" validate:"required,max=65535"`
+	Expected string `json:"expected" validate:"max=65535"`
+}
+
+// ImportCasesResponse reports an import. Errors holds the invalid rows, their paths start with
+// the row number in the sheet, e.g. rows[12].title. Nothing is written while a row is invalid
+type ImportCasesResponse struct {
+	DryRun    bool                 `json:"dryRun"`
+	Rows      int                  `json:"rows"`
+	Invalid   int                  `json:"invalid"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Errors    []common.ErrorDetail `json:"errors,omitempty"`
+}
+
+// ListTestCasesRequest pages through the cases of a project by ID, those of the suite SuiteID when
+// it is set. The page starts after the case AfterID, Limit defaults to 100
+type ListTestCasesRequest struct {
+	SuiteID int `json:"suiteId" validate:"gte=0"`
+	AfterID int `json:"afterId" validate:"gte=0"`
+	Limit   int `json:"limit" validate:"gte=0,lte=500"`
+}
+
+// ListTestCasesResponse holds a page of cases, NextAfterID starts the next one and is 0 on the last
+type ListTestCasesResponse struct {
+	Cases       []*TestCaseResponse `json:"cases"`
+	NextAfterID int                 `json:"nextAfterId"`
+}
+
+type TestSuiteResponse struct {
+	ID          int       `json:"id"`
+	ProjectID   int       `json:"projectId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TestCaseResponse describes a case, Steps are only set when a single case is read
+type TestCaseResponse struct {
+	ID            int                 `json:"id"`
+	ProjectID     int                 `json:"projectId"`
+	SuiteID       *int                `json:"suiteId"`
+	ExternalID    string              `json:"externalId"`
+	Title         string              `json:"title"`
+	Section       string              `json:"section"`
+	Priority      string              `json:"priority"`
+	Preconditions string              `json:"preconditions"`
+	Description   string              `json:"description"`
+	Tags          []string            `json:"tags"`
+	CustomFields  map[string]string   `json:"customFields"`
+	Steps         []*TestStepResponse `json:"steps,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+}
+
+type TestStepResponse struct {
+	Position int    `json:"position"`
+	Action   string `json:"action"`
+	Expected string `json:"expected"`
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+type CreateTestRunRequest struct {
+	Name string `json:"name" normalize:"trim,nfc" validate:"required,max=100,no_control_chars"`
+}
+
+// TestRunResponse describes a run, uploads that were cut off resume after LastSequence
+type TestRunResponse struct {
+	ID           int        `json:"id"`
+	ProjectID    int        `json:"projectId"`
+	Name         string     `json:"name"`
+	Status       string     `json:"status"`
+	LastSequence int64      `json:"lastSequence"`
+	ResultCount  int        `json:"resultCount"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ClosedAt     *time.Time `json:"closedAt"`
+}
+
+// ListTestRunsRequest narrows the runs of a project, listed newest first. Transports read it from
+// query parameters, Limit defaults to 20
+type ListTestRunsRequest struct {
+	Status string `form:"status" json:"status" normalize:"trim,lower" validate:"omitempty,oneof=open closed"`
+	Limit  int    `form:"limit" json:"limit" validate:"gte=0,lte=100"`
+}
+
+type ListTestRunsResponse struct {
+	Runs []*TestRunResponse `json:"runs"`
+}
+
+// EvaluateGateRequest holds the thresholds a run must meet to pass its quality gate. Transports
+// read it from query parameters, thresholds left out are taken from the configured quality gate
+type EvaluateGateRequest struct {
+	// MinPassRate is the lowest percentage of passed results, skipped results left out
+	MinPassRate *float64 `form:"minPassRate" json:"minPassRate" validate:"omitempty,gte=0,lte=100"`
+	// MaxFailed bounds the failed and blocked results, it is unbounded when unset
+	MaxFailed *int `form:"maxFailed" json:"maxFailed" validate:"omitempty,gte=0"`
+}
+
+// GateResponse is the verdict of the quality gate of a run. Counts holds the results by status,
+// Reasons the thresholds that were missed
+type GateResponse struct {
+	RunID    int            `json:"runId"`
+	Passed   bool           `json:"passed"`
+	PassRate float64        `json:"passRate"`
+	Counts   map[string]int `json:"counts"`
+	Reasons  []string       `json:"reasons"`
+}
+
+// TestResultRequest is one result of an upload. Sequences increase through the upload,
+// results whose sequence is already stored in the run are dropped as duplicates
+type TestResultRequest struct {
+	Sequence   int64  `json:"sequence" validate:"gt=0"`
+	CaseKey    string `json:"caseKey" normalize:"trim,nfc" validate:"required,max=255,no_control_chars"`
+	Status     string `json:"status" normalize:"trim,lower" validate:"required,oneof=passed failed skipped blocked"`
+	DurationMs int64  `json:"durationMs" validate:"gte=0"`
+	Message    string `json:"message" validate:"max=65535"`
+}
+
+// UploadResultsResponse reports the progress of an upload. It is sent after every committed
+// batch and once more with Done set when the upload ends, with Error when it failed
+type UploadResultsResponse struct {
+	// AckedSequence is the sequence up to which results of the run are committed
+	AckedSequence int64 `json:"ackedSequence"`
+	// Received counts the results read from the upload, Stored those inserted and
+	// Duplicates those dropped because their sequence was already stored
+	Received   int                  `json:"received"`
+	Stored     int                  `json:"stored"`
+	Duplicates int                  `json:"duplicates"`
+	Done       bool                 `json:"done"`
+	Error      *common.ServiceError `json:"error,omitempty"`
+}
+
+type TestResultResponse struct {
+	ID         int       `json:"id"`
+	RunID      int       `json:"runId"`
+	Sequence   int64     `json:"sequence"`
+	CaseKey    string    `json:"caseKey"`
+	Status     string    `json:"status"`
+	DurationMs int64     `json:"durationMs"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ListFilter narrows the children listed per parent, e.g. the runs of each project
+type ListFilter struct {
+	// Status keeps the children with this status, all of them when empty
+	Status string
+	// First is how many children are listed per parent
+	First int
+}
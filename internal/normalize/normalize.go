@@ -0,0 +1,95 @@
+package normalize
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// tagName is the struct tag listing the steps applied to a field, e.g. normalize:"trim,nfc,lower"
+const tagName = "normalize"
+
+// steps are the available normalization steps, applied in the order of the tag
+var steps = map[string]func(string) string{
+	"trim":  strings.TrimSpace,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// fold applies Unicode case folding, which also matches e.g. "ß" and "ss"
+	"fold": func(s string) string { return cases.Fold().String(s) },
+	"nfc":  norm.NFC.String,
+	// squash replaces every run of whitespace with a single space
+	"squash": func(s string) string { return strings.Join(strings.Fields(s), " ") },
+}
+
+// Struct normalizes the string, *string and []string fields of the struct v points to,
+// following their normalize tags. Nested structs are normalized too. It runs before
+// validation so that rules and uniqueness checks see the stored form
+func Struct(v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("normalize: expected a pointer to a struct, got %T", v)
+	}
+	return normalizeStruct(value.Elem())
+}
+
+func normalizeStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := normalizeStruct(fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag := field.Tag.Get(tagName)
+		if tag == "" {
+			continue
+		}
+		apply, err := compile(tag)
+		if err != nil {
+			return fmt.Errorf("normalize: field %s: %w", field.Name, err)
+		}
+		normalizeValue(fieldValue, apply)
+	}
+	return nil
+}
+
+func normalizeValue(v reflect.Value, apply func(string) string) {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(apply(v.String()))
+	case v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.String:
+		v.Elem().SetString(apply(v.Elem().String()))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).SetString(apply(v.Index(i).String()))
+		}
+	}
+}
+
+// compile chains the steps of a tag
+func compile(tag string) (func(string) string, error) {
+	var chain []func(string) string
+	for _, name := range strings.Split(tag, ",") {
+		step, ok := steps[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown step %q", name)
+		}
+		chain = append(chain, step)
+	}
+	return func(s string) string {
+		for _, step := range chain {
+			s = step(s)
+		}
+		return s
+	}, nil
+}
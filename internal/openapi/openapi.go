@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+const (
+	contentTypeJSON    = "application/json"
+	contentTypeProblem = "application/problem+json"
+)
+
+// Route describes an API route registered on the gin engine
+type Route struct {
+	Method string
+	// Path uses the gin syntax, e.g. /api/v1/project/:id
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Tag         string
+	// Params are the query and header parameters, path parameters are taken from Path
+	Params []*Parameter
+	// Request is the JSON body model, nil when the route reads no body
+	Request any
+	// ContentType is the media type of the request and success bodies, JSON when empty.
+	// For streamed types such as NDJSON the models describe one line
+	ContentType string
+	// RequestContentTypes are the media types of a request body uploaded as a file, which is
+	// described as binary. ContentType then only applies to the success body
+	RequestContentTypes []string
+	Status              int
+	// Response is the JSON body model, nil when the route answers without content
+	Response        any
+	ResponseHeaders map[string]string
+	// Errors are the error codes the route answers with besides the common ones
+	Errors []common.ErrorCode
+}
+
+// Key identifies the route as registered on the gin engine
+func (r Route) Key() string {
+	return r.Method + " " + r.Path
+}
+
+// commonErrors may be answered by every route
+var commonErrors = []common.ErrorCode{
+	// An unknown API key is refused on every route
+	common.ErrCode_Unauthorized,
+	common.ErrCode_TooManyRequests,
+	common.ErrCode_InternalServerError,
+}
+
+// Generator builds OpenAPI documents from route descriptions and the request and response models
+type Generator struct {
+	info Info
+	// patterns maps custom validate tags to the regular expression they enforce
+	patterns map[string]string
+	schemas  map[string]*Schema
+}
+
+// NewGenerator creates a generator, patterns maps custom validate tags such as slug to the
+// regular expression they enforce so that they become schema patterns
+func NewGenerator(info Info, patterns map[string]string) *Generator {
+	return &Generator{info: info, patterns: patterns}
+}
+
+// Generate returns the document describing routes
+func (g *Generator) Generate(routes []Route) *Document {
+	g.schemas = map[string]*Schema{}
+	g.schemaOf(reflect.TypeOf(common.ServiceError{}))
+	g.schemaOf(reflect.TypeOf(common.Problem{}))
+
+	paths := map[string]map[string]*Operation{}
+	for _, route := range routes {
+		path := specPath(route.Path)
+		if paths[path] == nil {
+			paths[path] = map[string]*Operation{}
+		}
+		paths[path][strings.ToLower(route.Method)] = g.operation(route)
+	}
+	return &Document{
+		OpenAPI:    Version,
+		Info:       g.info,
+		Paths:      paths,
+		Components: Components{Schemas: g.schemas},
+	}
+}
+
+func (g *Generator) operation(route Route) *Operation {
+	op := &Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Parameters:  append(pathParams(route.Path), route.Params...),
+		Responses:   map[string]*Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	contentType := route.ContentType
+	if contentType == "" {
+		contentType = contentTypeJSON
+	}
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{contentType: {Schema: g.schemaOf(reflect.TypeOf(route.Request))}},
+		}
+	}
+	if len(route.RequestContentTypes) > 0 {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{}}
+		for _, requestType := range route.RequestContentTypes {
+			op.RequestBody.Content[requestType] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	}
+
+	success := &Response{Description: http.StatusText(route.Status)}
+	if route.Response != nil {
+		success.Content = map[string]*MediaType{contentType: {Schema: g.schemaOf(reflect.TypeOf(route.Response))}}
+	}
+	for name, description := range route.ResponseHeaders {
+		if success.Headers == nil {
+			success.Headers = map[string]*Header{}
+		}
+		success.Headers[name] = &Header{Description: description, Schema: &Schema{Type: "string"}}
+	}
+	op.Responses[strconv.Itoa(route.Status)] = success
+
+	for _, code := range slices.Concat(route.Errors, commonErrors) {
+		status := common.ErrorMappings[code].HTTPCode
+		if _, ok := op.Responses[strconv.Itoa(status)]; !ok {
+			op.Responses[strconv.Itoa(status)] = errorResponse(status)
+		}
+	}
+	return op
+}
+
+// errorResponse describes a ServiceError, or its RFC 7807 form when the client prefers it
+func errorResponse(status int) *Response {
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{
+			contentTypeJSON:    {Schema: &Schema{Ref: componentRef + "ServiceError"}},
+			contentTypeProblem: {Schema: &Schema{Ref: componentRef + "Problem"}},
+		},
+	}
+}
+
+// specPath converts the gin parameters of path to OpenAPI ones, e.g. :id becomes {id}
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := paramName(segment); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// pathParams describes the parameters of a gin path. IDs are positive integers, others strings
+func pathParams(path string) []*Parameter {
+	var params []*Parameter
+	for _, segment := range strings.Split(path, "/") {
+		name, ok := paramName(segment)
+		if !ok {
+			continue
+		}
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "Id") {
+			minimum := 1.0
+			schema = &Schema{Type: "integer", Format: "int32", Minimum: &minimum}
+		}
+		params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+func paramName(segment string) (string, bool) {
+	if name, ok := strings.CutPrefix(segment, ":"); ok {
+		return name, true
+	}
+	return strings.CutPrefix(segment, "*")
+}
+
+// Drift compares the documented routes with the registered ones, given by Route.Key, and
+// returns an error listing the routes missing on either side
+func Drift(routes []Route, registered []string) error {
+	documented := make([]string, 0, len(routes))
+	for _, route := range routes {
+		documented = append(documented, route.Key())
+	}
+
+	var problems []string
+	for _, key := range registered {
+		if !slices.Contains(documented, key) {
+			problems = append(problems, "undocumented route "+key)
+		}
+	}
+	for _, key := range documented {
+		if !slices.Contains(registered, key) {
+			problems = append(problems, "documented route "+key+" is not registered")
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems)
+	return fmt.Errorf("openapi: the spec and the routes drifted: %s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,3 @@
+// Placeholder for the Redoc v2.1.5 standalone bundle, replaced by running
+// go generate ./internal/openapi
+document.body.textContent = "The Redoc bundle is not vendored, run go generate ./internal/openapi and rebuild.";
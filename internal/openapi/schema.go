@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// componentRef is the prefix of references to the component schemas
+const componentRef = "#/components/schemas/"
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	// unsafeNameChars are dropped from component names, e.g. the brackets of generic types
+	unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// schemaOf returns the schema of t, registering structs as component schemas
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return nullable(g.schemaOf(t.Elem()))
+	case t.Kind() == reflect.Struct:
+		return g.component(t)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &Schema{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	}
+	return scalarSchema(t.Kind())
+}
+
+func scalarSchema(kind reflect.Kind) *Schema {
+	switch kind {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	}
+	// Interfaces hold any value
+	return &Schema{}
+}
+
+// nullable allows null besides the values of s. References are kept as they are,
+// a null struct is rendered as an omitted field by the handlers
+func nullable(s *Schema) *Schema {
+	if name, ok := s.Type.(string); ok {
+		s.Type = []string{name, "null"}
+	}
+	return s
+}
+
+// component registers the schema of the struct t once and returns a reference to it
+func (g *Generator) component(t reflect.Type) *Schema {
+	name := unsafeNameChars.ReplaceAllString(t.Name(), "")
+	ref := &Schema{Ref: componentRef + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	// Registered before the fields so that recursive types end on the reference
+	g.schemas[name] = schema
+	g.addFields(schema, t)
+	return ref
+}
+
+// addFields adds the exported fields of t to schema, flattening embedded structs like encoding/json
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schemaOf(field.Type)
+		if g.applyValidateTag(fieldSchema, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// applyValidateTag turns the rules of a validate tag into constraints of s and reports whether
+// the field is required. Rules after dive apply to the items, rules without an equivalent are skipped
+func (g *Generator) applyValidateTag(s *Schema, tag string) bool {
+	required := false
+	target := s
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch {
+		case name == "required" && target == s:
+			required = true
+		case name == "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case target.Ref == "":
+			g.applyRule(target, name, param)
+		}
+	}
+	return required
+}
+
+func (g *Generator) applyRule(s *Schema, name, param string) {
+	switch name {
+	case "min", "max", "len":
+		applyBound(s, name, param)
+	case "gte":
+		s.Minimum = parseFloat(param)
+	case "lte":
+		s.Maximum = parseFloat(param)
+	case "gt":
+		s.ExclusiveMinimum = parseFloat(param)
+	case "lt":
+		s.ExclusiveMaximum = parseFloat(param)
+	case "oneof":
+		for _, value := range strings.Fields(param) {
+			s.Enum = append(s.Enum, value)
+		}
+	case "unique":
+		s.UniqueItems = true
+	case "email", "uuid", "ipv4", "ipv6", "hostname":
+		s.Format = name
+	case "url", "uri":
+		s.Format = "uri"
+	default:
+		if pattern, ok := g.patterns[name]; ok {
+			applyPattern(s, pattern)
+		}
+	}
+}
+
+// applyBound maps min, max and len to the length, value or item count bounds of the type of s
+func applyBound(s *Schema, name, param string) {
+	switch typeName(s) {
+	case "string":
+		s.MinLength, s.MaxLength = bounds(s.MinLength, s.MaxLength, name, param)
+	case "array":
+		s.MinItems, s.MaxItems = bounds(s.MinItems, s.MaxItems, name, param)
+	case "integer", "number":
+		value := parseFloat(param)
+		if name != "max" {
+			s.Minimum = value
+		}
+		if name != "min" {
+			s.Maximum = value
+		}
+	}
+}
+
+func bounds(minimum, maximum *int, name, param string) (*int, *int) {
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return minimum, maximum
+	}
+	if name != "max" {
+		minimum = &value
+	}
+	if name != "min" {
+		maximum = &value
+	}
+	return minimum, maximum
+}
+
+// applyPattern sets the pattern of a string, or of the items of an array of strings
+func applyPattern(s *Schema, pattern string) {
+	if typeName(s) == "array" && s.Items != nil {
+		s = s.Items
+	}
+	s.Pattern = pattern
+}
+
+// typeName returns the type of s without the null of nullable schemas
+func typeName(s *Schema) string {
+	switch typ := s.Type.(type) {
+	case string:
+		return typ
+	case []string:
+		return typ[0]
+	}
+	return ""
+}
+
+func parseFloat(param string) *float64 {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
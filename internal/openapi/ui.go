@@ -0,0 +1,16 @@
+package openapi
+
+import _ "embed"
+
+//go:generate curl -sSfL -o redoc.standalone.js https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js
+
+// DocsPage is the HTML page rendering the document served at /openapi.json with Redoc
+//
+//go:embed redoc.html
+var DocsPage []byte
+
+// RedocBundle is the Redoc script DocsPage loads, vendored so that the docs do not depend on a CDN.
+// go generate ./internal/openapi fetches the pinned release
+//
+//go:embed redoc.standalone.js
+var RedocBundle []byte
@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Violation is a part of a request or response breaking the document
+type Violation struct {
+	// In is where the value is: body, path, query or header
+	In string
+	// Path is the RFC 6901 JSON pointer of a body value, or the name of a parameter
+	Path    string
+	Message string
+}
+
+// Request holds the parts of an HTTP request checked against an operation
+type Request struct {
+	PathParams  map[string]string
+	Query       url.Values
+	Header      http.Header
+	ContentType string
+	Body        []byte
+}
+
+// Validator checks requests and responses against the operations of a document
+type Validator struct {
+	doc      *Document
+	patterns sync.Map
+}
+
+// NewValidator creates a validator of doc
+func NewValidator(doc *Document) *Validator {
+	return &Validator{doc: doc}
+}
+
+// Operation returns the operation of a route given with the gin path syntax, or nil when it is not documented
+func (v *Validator) Operation(method, path string) *Operation {
+	return v.doc.Paths[specPath(path)][strings.ToLower(method)]
+}
+
+// ValidateRequest returns the parameters and body values of req breaking op
+func (v *Validator) ValidateRequest(op *Operation, req Request) []Violation {
+	var violations []Violation
+	for _, param := range op.Parameters {
+		value, ok := paramValue(param, req)
+		if !ok {
+			if param.Required {
+				violations = append(violations, Violation{In: param.In, Path: param.Name, Message: "is required"})
+			}
+			continue
+		}
+		violations = append(violations, v.validateParam(param, value)...)
+	}
+
+	if op.RequestBody != nil {
+		violations = append(violations, v.validateContent(op.RequestBody.Content, req.ContentType, req.Body,
+			op.RequestBody.Required)...)
+	}
+	return violations
+}
+
+// ValidateResponse returns the parts of a response breaking op, including an undocumented status
+func (v *Validator) ValidateResponse(op *Operation, status int, contentType string, body []byte) []Violation {
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = op.Responses["default"]
+	}
+	if !ok {
+		return []Violation{{In: "status", Path: strconv.Itoa(status), Message: "is not a documented status"}}
+	}
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return []Violation{{In: "body", Message: "must be empty"}}
+		}
+		return nil
+	}
+	return v.validateContent(response.Content, contentType, body, true)
+}
+
+func paramValue(param *Parameter, req Request) (string, bool) {
+	switch param.In {
+	case "path":
+		value, ok := req.PathParams[param.Name]
+		return value, ok
+	case "query":
+		if !req.Query.Has(param.Name) {
+			return "", false
+		}
+		return req.Query.Get(param.Name), true
+	case "header":
+		values := req.Header.Values(param.Name)
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	}
+	return "", false
+}
+
+// validateParam converts the text of a parameter to the type of its schema before checking it
+func (v *Validator) validateParam(param *Parameter, text string) []Violation {
+	var value any = text
+	switch typeName(param.Schema) {
+	case "integer", "number":
+		value = json.Number(text)
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return []Violation{{In: param.In, Path: param.Name, Message: "must be a " + typeName(param.Schema)}}
+		}
+	case "boolean":
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return []Violation{{In: param.In, Path: param.Name, Message: "must be a boolean"}}
+		}
+		value = parsed
+	}
+
+	violations := v.validate(param.Schema, value, "")
+	for i := range violations {
+		violations[i].In, violations[i].Path = param.In, param.Name
+	}
+	return violations
+}
+
+// validateContent checks a JSON body against the schema of its media type
+func (v *Validator) validateContent(content map[string]*MediaType, contentType string, body []byte,
+	required bool) []Violation {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			return []Violation{{In: "body", Message: "is required"}}
+		}
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := content[mediaType]
+	if !ok {
+		return []Violation{{In: "body", Message: fmt.Sprintf("content type %q is not accepted", mediaType)}}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []Violation{{In: "body", Message: "must be valid JSON"}}
+	}
+	return v.validate(media.Schema, value, "")
+}
@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// pointerEscaper escapes a property name as an RFC 6901 reference token
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// validate checks a decoded JSON value against s, pointer locates the value in the body
+func (v *Validator) validate(s *Schema, value any, pointer string) []Violation {
+	if s.Ref != "" {
+		s = v.doc.Components.Schemas[strings.TrimPrefix(s.Ref, componentRef)]
+		if s == nil {
+			return nil
+		}
+	}
+	if !matchesType(s, value) {
+		return []Violation{{In: "body", Path: pointer, Message: "must be of type " + typeList(s)}}
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		return []Violation{{In: "body", Path: pointer, Message: fmt.Sprintf("must be one of %v", s.Enum)}}
+	}
+
+	var messages []string
+	switch typed := value.(type) {
+	case string:
+		messages = v.checkString(s, typed)
+	case json.Number:
+		messages = checkNumber(s, typed)
+	case []any:
+		return v.validateArray(s, typed, pointer)
+	case map[string]any:
+		return v.validateObject(s, typed, pointer)
+	}
+
+	violations := make([]Violation, 0, len(messages))
+	for _, message := range messages {
+		violations = append(violations, Violation{In: "body", Path: pointer, Message: message})
+	}
+	return violations
+}
+
+func (v *Validator) validateObject(s *Schema, object map[string]any, pointer string) []Violation {
+	var violations []Violation
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, Violation{In: "body", Path: pointer + "/" + pointerEscaper.Replace(name),
+				Message: "is required"})
+		}
+	}
+
+	// Sorted so that the violations come in a stable order
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			property = s.AdditionalProperties
+		}
+		if property != nil {
+			violations = append(violations, v.validate(property, object[name], pointer+"/"+pointerEscaper.Replace(name))...)
+		}
+	}
+	return violations
+}
+
+func (v *Validator) validateArray(s *Schema, items []any, pointer string) []Violation {
+	var violations []Violation
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		violations = append(violations, Violation{In: "body", Path: pointer,
+			Message: fmt.Sprintf("must have at least %d items", *s.MinItems)})
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		violations = append(violations, Violation{In: "body", Path: pointer,
+			Message: fmt.Sprintf("must have at most %d items", *s.MaxItems)})
+	}
+
+	seen := make(map[string]struct{}, len(items))
+	for i, item := range items {
+		if s.UniqueItems {
+			key := fmt.Sprint(item)
+			if _, dup := seen[key]; dup {
+				violations = append(violations, Violation{In: "body", Path: pointer + "/" + strconv.Itoa(i),
+					Message: "must be unique"})
+			}
+			seen[key] = struct{}{}
+		}
+		if s.Items != nil {
+			violations = append(violations, v.validate(s.Items, item, pointer+"/"+strconv.Itoa(i))...)
+		}
+	}
+	return violations
+}
+
+func (v *Validator) checkString(s *Schema, value string) []string {
+	var messages []string
+	length := utf8.RuneCountInString(value)
+	if s.MinLength != nil && length < *s.MinLength {
+		messages = append(messages, fmt.Sprintf("must be at least %d characters", *s.MinLength))
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		messages = append(messages, fmt.Sprintf("must be at most %d characters", *s.MaxLength))
+	}
+	if s.Pattern != "" {
+		if pattern := v.pattern(s.Pattern); pattern != nil && !pattern.MatchString(value) {
+			messages = append(messages, "must match the pattern "+s.Pattern)
+		}
+	}
+	if s.Format != "" && !matchesFormat(s.Format, value) {
+		messages = append(messages, "must be a valid "+s.Format)
+	}
+	return messages
+}
+
+// pattern compiles a schema pattern once. Patterns Go cannot compile are not checked
+func (v *Validator) pattern(expr string) *regexp.Regexp {
+	if cached, ok := v.patterns.Load(expr); ok {
+		return cached.(*regexp.Regexp)
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil
+	}
+	v.patterns.Store(expr, pattern)
+	return pattern
+}
+
+func checkNumber(s *Schema, value json.Number) []string {
+	number, err := value.Float64()
+	if err != nil {
+		return []string{"must be a number"}
+	}
+	var messages []string
+	if s.Minimum != nil && number < *s.Minimum {
+		messages = append(messages, fmt.Sprintf("must be at least %v", *s.Minimum))
+	}
+	if s.Maximum != nil && number > *s.Maximum {
+		messages = append(messages, fmt.Sprintf("must be at most %v", *s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && number <= *s.ExclusiveMinimum {
+		messages = append(messages, fmt.Sprintf("must be greater than %v", *s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && number >= *s.ExclusiveMaximum {
+		messages = append(messages, fmt.Sprintf("must be less than %v", *s.ExclusiveMaximum))
+	}
+	return messages
+}
+
+func matchesFormat(format, value string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(value)
+		return err == nil
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.IsAbs()
+	}
+	// Formats without a check are annotations only
+	return true
+}
+
+// matchesType reports whether value is of one of the types of s, a schema without type accepts any value
+func matchesType(s *Schema, value any) bool {
+	var types []string
+	switch typ := s.Type.(type) {
+	case string:
+		types = []string{typ}
+	case []string:
+		types = typ
+	default:
+		return true
+	}
+	return slices.Contains(types, jsonType(value)) ||
+		(jsonType(value) == "integer" && slices.Contains(types, "number"))
+}
+
+// jsonType returns the JSON Schema type of a value decoded with json.Decoder.UseNumber
+func jsonType(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+func typeList(s *Schema) string {
+	if types, ok := s.Type.([]string); ok {
+		return strings.Join(types, " or ")
+	}
+	return fmt.Sprint(s.Type)
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is the time of a MemoryStore under test, moved on by the steps
+type clock struct{ at time.Time }
+
+func (c *clock) now() time.Time { return c.at }
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{at: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = c.now
+	return store, c
+}
+
+func TestPerMinute(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		burst int
+		want  Limit
+	}{
+		{name: "the rate is per second", n: 120, burst: 10, want: Limit{Rate: 2, Burst: 10}},
+		{name: "without a burst the minute can be taken at once", n: 30, want: Limit{Rate: 0.5, Burst: 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PerMinute(tt.n, tt.burst); got != tt.want {
+				t.Errorf("PerMinute(%d, %d) = %+v, want %+v", tt.n, tt.burst, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	// A token a second, three at once
+	limit := PerMinute(60, 3)
+	type take struct {
+		after time.Duration
+		key   string
+		want  Result
+	}
+	full := []take{
+		{key: "a", want: Result{Allowed: true, Remaining: 2, Reset: time.Second}},
+		{key: "a", want: Result{Allowed: true, Remaining: 1, Reset: 2 * time.Second}},
+		{key: "a", want: Result{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+	}
+
+	tests := []struct {
+		name  string
+		takes []take
+	}{
+		{
+			name: "a new bucket starts full and the burst is refused after",
+			takes: append(full, take{key: "a",
+				want: Result{Remaining: 0, RetryAfter: time.Second, Reset: 3 * time.Second}}),
+		},
+		{
+			name: "tokens refill at the rate",
+			takes: append(full,
+				take{after: 500 * time.Millisecond, key: "a",
+					want: Result{RetryAfter: 500 * time.Millisecond, Reset: 2500 * time.Millisecond}},
+				take{after: 500 * time.Millisecond, key: "a", want: Result{Allowed: true, Reset: 3 * time.Second}},
+			),
+		},
+		{
+			name: "an idle bucket refills up to the burst only",
+			takes: append(full, take{after: time.Hour, key: "a",
+				want: Result{Allowed: true, Remaining: 2, Reset: time.Second}}),
+		},
+		{
+			name:  "every key has its own bucket",
+			takes: append(full, take{key: "b", want: Result{Allowed: true, Remaining: 2, Reset: time.Second}}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, c := newTestStore()
+			for i, step := range tt.takes {
+				c.at = c.at.Add(step.after)
+				got, err := store.Take(context.Background(), step.key, limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != step.want {
+					t.Errorf("take %d of %s = %+v, want %+v", i+1, step.key, got, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store, c := newTestStore()
+	limit := PerMinute(60, 3)
+	for _, key := range []string{"idle", "busy"} {
+		if _, err := store.Take(context.Background(), key, limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.at = c.at.Add(time.Minute)
+	if _, err := store.Take(context.Background(), "busy", limit); err != nil {
+		t.Fatal(err)
+	}
+
+	store.sweep(30 * time.Second)
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("the idle bucket was kept")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Error("the busy bucket was dropped")
+	}
+}
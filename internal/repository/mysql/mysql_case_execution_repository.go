@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type CaseExecutionRepository struct {
+	Logger *slog.Logger
+}
+
+func NewCaseExecutionRepository(logger *slog.Logger) *CaseExecutionRepository {
+	return &CaseExecutionRepository{
+		Logger: logger,
+	}
+}
+
+// Ensure returns the ID of the execution of a case in a run, creating it without a result when it is missing
+func (r *CaseExecutionRepository) Ensure(tx *sqlx.Tx, runID int, caseKey string) (int, error) {
+	query := `
+		INSERT INTO case_executions (run_id, case_key, status, message, duration_ms, recorded_by, version, created_at, updated_at)
+		VALUES (?, ?, '', '', 0, '', 0, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
+	`
+
+	now := time.Now()
+	result, err := tx.Exec(query, runID, caseKey, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert case execution: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return int(id), nil
+}
+
+// GetByIDForUpdate retrieves an execution and locks it until the transaction ends,
+// so results recorded for the same case are applied one at a time
+func (r *CaseExecutionRepository) GetByIDForUpdate(tx *sqlx.Tx, id int) (*entity.CaseExecution, error) {
+	query := `
+		SELECT id, run_id, case_key, status, message, duration_ms, recorded_by, version, created_at, updated_at
+		FROM case_executions
+		WHERE id = ?
+		FOR UPDATE
+	`
+
+	var execution entity.CaseExecution
+	err := tx.Get(&execution, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &execution, nil
+}
+
+// ListByRunID retrieves the executions of a run by case key
+func (r *CaseExecutionRepository) ListByRunID(tx *sqlx.Tx, runID int) ([]*entity.CaseExecution, error) {
+	query := `
+		SELECT id, run_id, case_key, status, message, duration_ms, recorded_by, version, created_at, updated_at
+		FROM case_executions
+		WHERE run_id = ?
+		ORDER BY case_key
+	`
+
+	var executions []*entity.CaseExecution
+	if err := tx.Select(&executions, query, runID); err != nil {
+		return nil, err
+	}
+
+	return executions, nil
+}
+
+// RecordResult writes the result of an execution still at expectedVersion and increments its version
+func (r *CaseExecutionRepository) RecordResult(tx *sqlx.Tx, execution *entity.CaseExecution,
+	expectedVersion int) (*entity.CaseExecution, error) {
+	query := `
+		UPDATE case_executions
+		SET status = ?, message = ?, duration_ms = ?, recorded_by = ?, version = version + 1, updated_at = ?
+		WHERE id = ? AND version = ?
+	`
+
+	now := time.Now()
+	result, err := tx.Exec(query, execution.Status, execution.Message, execution.DurationMs, execution.RecordedBy,
+		now, execution.ID, expectedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record case result: %w", err)
+	}
+	if err = checkConditionalWrite(result); err != nil {
+		return nil, err
+	}
+
+	execution.Version = expectedVersion + 1
+	execution.UpdatedAt = now
+	return execution, nil
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type CaseExecutionStepRepository struct {
+	Logger *slog.Logger
+}
+
+func NewCaseExecutionStepRepository(logger *slog.Logger) *CaseExecutionStepRepository {
+	return &CaseExecutionStepRepository{
+		Logger: logger,
+	}
+}
+
+// Save writes the result of a step, replacing the one recorded before
+func (r *CaseExecutionStepRepository) Save(tx *sqlx.Tx, step *entity.CaseExecutionStep) (*entity.CaseExecutionStep, error) {
+	query := `
+		INSERT INTO case_execution_steps (execution_id, step, status, note, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE status = ?, note = ?, updated_by = ?, updated_at = ?
+	`
+
+	now := time.Now()
+	_, err := tx.Exec(query, step.ExecutionID, step.Step, step.Status, step.Note, step.UpdatedBy, now,
+		step.Status, step.Note, step.UpdatedBy, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save case execution step: %w", err)
+	}
+
+	step.UpdatedAt = now
+	return step, nil
+}
+
+// ListByExecutionIDs retrieves the steps of the executions in step order
+func (r *CaseExecutionStepRepository) ListByExecutionIDs(tx *sqlx.Tx, executionIDs []int) ([]*entity.CaseExecutionStep, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, execution_id, step, status, note, updated_by, updated_at
+		FROM case_execution_steps
+		WHERE execution_id IN (?)
+		ORDER BY execution_id, step
+	`, executionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build case execution step query: %w", err)
+	}
+
+	var steps []*entity.CaseExecutionStep
+	if err = tx.Select(&steps, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
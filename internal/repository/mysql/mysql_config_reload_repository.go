@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type ConfigReloadRepository struct {
+	Logger *slog.Logger
+}
+
+func NewConfigReloadRepository(logger *slog.Logger) *ConfigReloadRepository {
+	return &ConfigReloadRepository{
+		Logger: logger,
+	}
+}
+
+// Save records a configuration reload
+func (r *ConfigReloadRepository) Save(tx *sqlx.Tx, reload *entity.ConfigReload) (*entity.ConfigReload, error) {
+	query := `
+		INSERT INTO config_reloads (instance, trigger_source, outcome, changes, restart_keys, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	// Marshalling strings cannot fail, nil lists are stored as empty ones
+	changes, _ := json.Marshal(append([]string{}, reload.Changes...))
+	restartKeys, _ := json.Marshal(append([]string{}, reload.RestartKeys...))
+	now := time.Now()
+	result, err := tx.Exec(query, reload.Instance, reload.Trigger, reload.Outcome, changes, restartKeys,
+		reload.Error, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert config reload: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	reload.ID = int(id)
+	reload.CreatedAt = now
+
+	return reload, nil
+}
@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type ImportMappingRepository struct {
+	Logger *slog.Logger
+}
+
+func NewImportMappingRepository(logger *slog.Logger) *ImportMappingRepository {
+	return &ImportMappingRepository{
+		Logger: logger,
+	}
+}
+
+// SaveBatch inserts the mappings in one statement, an item of the tool that is already mapped
+// in the project is ErrDuplicate
+func (r *ImportMappingRepository) SaveBatch(tx *sqlx.Tx, mappings []*entity.ImportMapping) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	var query strings.Builder
+	query.WriteString(`INSERT INTO import_mappings (project_id, source, kind, source_id, target_id, created_at) VALUES `)
+	args := make([]any, 0, len(mappings)*6)
+	now := time.Now()
+	for i, mapping := range mappings {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(?, ?, ?, ?, ?, ?)")
+		args = append(args, mapping.ProjectID, mapping.Source, mapping.Kind, mapping.SourceID, mapping.TargetID, now)
+	}
+
+	if _, err := tx.Exec(query.String(), args...); err != nil {
+		return fmt.Errorf("failed to insert import mappings: %w", asDuplicate(err))
+	}
+	return nil
+}
+
+// ListBySource retrieves what the items of source were imported as in a project
+func (r *ImportMappingRepository) ListBySource(tx *sqlx.Tx, projectID int,
+	source string) ([]*entity.ImportMapping, error) {
+	query := `
+		SELECT id, project_id, source, kind, source_id, target_id, created_at
+		FROM import_mappings
+		WHERE project_id = ? AND source = ?
+	`
+
+	var mappings []*entity.ImportMapping
+	if err := tx.Select(&mappings, query, projectID, source); err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
@@ -1,14 +1,27 @@
 package mysql
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/project-weekend/qms-engine/internal/entity"
 )
 
+var (
+	// ErrVersionMismatch is returned by conditional writes when the row is missing or has another version
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrDuplicate is returned when a write violates a unique key
+	ErrDuplicate = errors.New("duplicate entry")
+)
+
+// mysqlDuplicateEntry is the MySQL error number of a unique key violation
+const mysqlDuplicateEntry = 1062
+
 type ProjectRepository struct {
 	Logger *slog.Logger
 }
@@ -22,19 +35,20 @@ func NewProjectRepository(logger *slog.Logger) *ProjectRepository {
 // Save creates a new project in the database
 func (p *ProjectRepository) Save(tx *sqlx.Tx, project *entity.Project) (*entity.Project, error) {
 	query := `
-		INSERT INTO projects (name, description, created_at, updated_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO projects (name, project_key, description, version, created_at, updated_at)
+		VALUES (?, ?, ?, 1, ?, ?)
 	`
 
 	now := time.Now()
 	result, err := tx.Exec(query,
 		project.Name,
+		project.Key,
 		project.Description,
 		time.Now(),
 		time.Now(),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert project: %w", err)
+		return nil, fmt.Errorf("failed to insert project: %w", asDuplicate(err))
 	}
 
 	// Get the last inserted ID
@@ -44,6 +58,7 @@ func (p *ProjectRepository) Save(tx *sqlx.Tx, project *entity.Project) (*entity.
 	}
 
 	project.ID = int(id)
+	project.Version = 1
 	project.CreatedAt = now
 	project.UpdatedAt = now
 
@@ -53,7 +68,7 @@ func (p *ProjectRepository) Save(tx *sqlx.Tx, project *entity.Project) (*entity.
 // GetByName retrieves a project by its name
 func (p *ProjectRepository) GetByName(tx *sqlx.Tx, name string) (*entity.Project, error) {
 	query := `
-		SELECT id, name, description, created_at, updated_at, deleted_at
+		SELECT id, name, project_key, description, version, created_at, updated_at, deleted_at
 		FROM projects
 		WHERE name = ? AND deleted_at IS NULL
 	`
@@ -66,3 +81,108 @@ func (p *ProjectRepository) GetByName(tx *sqlx.Tx, name string) (*entity.Project
 
 	return &project, nil
 }
+
+// GetByID retrieves a project by its ID
+func (p *ProjectRepository) GetByID(tx *sqlx.Tx, id int) (*entity.Project, error) {
+	return p.get(tx, id, "")
+}
+
+// GetByIDForUpdate retrieves a project by its ID and locks it until the transaction ends,
+// so imports into the same project are applied one at a time
+func (p *ProjectRepository) GetByIDForUpdate(tx *sqlx.Tx, id int) (*entity.Project, error) {
+	return p.get(tx, id, "FOR UPDATE")
+}
+
+func (p *ProjectRepository) get(tx *sqlx.Tx, id int, lock string) (*entity.Project, error) {
+	query := `
+		SELECT id, name, project_key, description, version, created_at, updated_at, deleted_at
+		FROM projects
+		WHERE id = ? AND deleted_at IS NULL
+	` + lock
+
+	var project entity.Project
+	err := tx.Get(&project, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// GetByIDs retrieves the projects with the given IDs, missing ones are left out
+func (p *ProjectRepository) GetByIDs(tx *sqlx.Tx, ids []int) ([]*entity.Project, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, name, project_key, description, version, created_at, updated_at, deleted_at
+		FROM projects
+		WHERE id IN (?) AND deleted_at IS NULL
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build project query: %w", err)
+	}
+
+	var projects []*entity.Project
+	if err = tx.Select(&projects, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// Update writes the name, key and description of a project still at expectedVersion and increments its version
+func (p *ProjectRepository) Update(tx *sqlx.Tx, project *entity.Project, expectedVersion int) (*entity.Project, error) {
+	query := `
+		UPDATE projects
+		SET name = ?, project_key = ?, description = ?, version = version + 1, updated_at = ?
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	now := time.Now()
+	result, err := tx.Exec(query, project.Name, project.Key, project.Description, now, project.ID, expectedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", asDuplicate(err))
+	}
+	if err = checkConditionalWrite(result); err != nil {
+		return nil, err
+	}
+
+	project.Version = expectedVersion + 1
+	project.UpdatedAt = now
+
+	return project, nil
+}
+
+// Delete soft deletes a project still at expectedVersion
+func (p *ProjectRepository) Delete(tx *sqlx.Tx, id int, expectedVersion int) error {
+	query := `
+		UPDATE projects
+		SET deleted_at = ?, version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	result, err := tx.Exec(query, time.Now(), id, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return checkConditionalWrite(result)
+}
+
+// checkConditionalWrite returns ErrVersionMismatch when a conditional write matched no row
+func checkConditionalWrite(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// asDuplicate returns ErrDuplicate for a unique key violation, and err otherwise
+func asDuplicate(err error) error {
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrDuplicate
+	}
+	return err
+}
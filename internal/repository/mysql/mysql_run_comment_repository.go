@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type RunCommentRepository struct {
+	Logger *slog.Logger
+}
+
+func NewRunCommentRepository(logger *slog.Logger) *RunCommentRepository {
+	return &RunCommentRepository{
+		Logger: logger,
+	}
+}
+
+// Save creates a new comment in the database
+func (r *RunCommentRepository) Save(tx *sqlx.Tx, comment *entity.RunComment) (*entity.RunComment, error) {
+	query := `
+		INSERT INTO run_comments (run_id, case_key, author, body, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := tx.Exec(query, comment.RunID, comment.CaseKey, comment.Author, comment.Body, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert run comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	comment.ID = int(id)
+	comment.CreatedAt = now
+	return comment, nil
+}
+
+// ListLatestByRunID retrieves the newest comments of a run, at most limit, oldest first
+func (r *RunCommentRepository) ListLatestByRunID(tx *sqlx.Tx, runID, limit int) ([]*entity.RunComment, error) {
+	query := `
+		SELECT id, run_id, case_key, author, body, created_at
+		FROM (
+			SELECT id, run_id, case_key, author, body, created_at
+			FROM run_comments
+			WHERE run_id = ?
+			ORDER BY id DESC
+			LIMIT ?
+		) latest
+		ORDER BY id
+	`
+
+	var comments []*entity.RunComment
+	if err := tx.Select(&comments, query, runID, limit); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
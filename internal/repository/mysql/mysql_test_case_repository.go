@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type TestCaseRepository struct {
+	Logger *slog.Logger
+}
+
+func NewTestCaseRepository(logger *slog.Logger) *TestCaseRepository {
+	return &TestCaseRepository{
+		Logger: logger,
+	}
+}
+
+// SaveBatch inserts the cases in one statement, a case whose external ID is already used in its
+// project is ErrDuplicate. The IDs are not set, read the cases back by external ID
+func (r *TestCaseRepository) SaveBatch(tx *sqlx.Tx, cases []*entity.TestCase) error {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	var query strings.Builder
+	query.WriteString(`INSERT INTO test_cases (project_id, suite_id, external_id, title, section, priority,
+		preconditions, description, tags, custom_fields, checksum, created_at, updated_at) VALUES `)
+	args := make([]any, 0, len(cases)*13)
+	now := time.Now()
+	for i, testCase := range cases {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, testCase.ProjectID, testCase.SuiteID, testCase.ExternalID, testCase.Title,
+			testCase.Section, testCase.Priority, testCase.Preconditions, testCase.Description, testCase.Tags,
+			testCase.CustomFields, testCase.Checksum, now, now)
+	}
+
+	if _, err := tx.Exec(query.String(), args...); err != nil {
+		return fmt.Errorf("failed to insert test cases: %w", asDuplicate(err))
+	}
+	return nil
+}
+
+// Update writes the content of a case, its suite and custom fields
+func (r *TestCaseRepository) Update(tx *sqlx.Tx, testCase *entity.TestCase) (*entity.TestCase, error) {
+	query := `
+		UPDATE test_cases
+		SET suite_id = ?, title = ?, section = ?, priority = ?, preconditions = ?, description = ?, tags = ?,
+			custom_fields = ?, checksum = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	now := time.Now()
+	_, err := tx.Exec(query, testCase.SuiteID, testCase.Title, testCase.Section, testCase.Priority,
+		testCase.Preconditions, testCase.Description, testCase.Tags, testCase.CustomFields, testCase.Checksum,
+		now, testCase.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update test case: %w", err)
+	}
+
+	testCase.UpdatedAt = now
+	return testCase, nil
+}
+
+// ListByExternalIDs retrieves the cases of a project with the given external IDs, missing ones are left out
+func (r *TestCaseRepository) ListByExternalIDs(tx *sqlx.Tx, projectID int, externalIDs []string) ([]*entity.TestCase, error) {
+	return r.listByExternalIDs(tx, projectID, externalIDs, "")
+}
+
+// ListByExternalIDsForUpdate is ListByExternalIDs locking the cases until the transaction ends.
+// The gaps of missing ones stay locked against inserts too
+func (r *TestCaseRepository) ListByExternalIDsForUpdate(tx *sqlx.Tx, projectID int,
+	externalIDs []string) ([]*entity.TestCase, error) {
+	return r.listByExternalIDs(tx, projectID, externalIDs, "FOR UPDATE")
+}
+
+func (r *TestCaseRepository) listByExternalIDs(tx *sqlx.Tx, projectID int, externalIDs []string,
+	lock string) ([]*entity.TestCase, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, project_id, suite_id, external_id, title, section, priority, preconditions, description,
+			tags, custom_fields, checksum, created_at, updated_at
+		FROM test_cases
+		WHERE project_id = ? AND external_id IN (?)
+		`+lock, projectID, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test case query: %w", err)
+	}
+
+	var cases []*entity.TestCase
+	if err = tx.Select(&cases, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+// GetByIDsForUpdate retrieves the cases of a project with the given IDs and locks them until the
+// transaction ends, missing ones are left out
+func (r *TestCaseRepository) GetByIDsForUpdate(tx *sqlx.Tx, projectID int, ids []int) ([]*entity.TestCase, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, project_id, suite_id, external_id, title, section, priority, preconditions, description,
+			tags, custom_fields, checksum, created_at, updated_at
+		FROM test_cases
+		WHERE project_id = ? AND id IN (?)
+		FOR UPDATE
+	`, projectID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test case query: %w", err)
+	}
+
+	var cases []*entity.TestCase
+	if err = tx.Select(&cases, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+// GetByID retrieves a case of a project
+func (r *TestCaseRepository) GetByID(tx *sqlx.Tx, projectID, id int) (*entity.TestCase, error) {
+	testCase := new(entity.TestCase)
+	err := tx.Get(testCase, `
+		SELECT id, project_id, suite_id, external_id, title, section, priority, preconditions, description,
+			tags, custom_fields, checksum, created_at, updated_at
+		FROM test_cases
+		WHERE project_id = ? AND id = ?
+	`, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return testCase, nil
+}
+
+// ListByProjectID retrieves at most limit cases of a project by ID after afterID, only those of
+// the suite suiteID when it is not 0
+func (r *TestCaseRepository) ListByProjectID(tx *sqlx.Tx, projectID, suiteID, afterID, limit int) ([]*entity.TestCase, error) {
+	var cases []*entity.TestCase
+	err := tx.Select(&cases, `
+		SELECT id, project_id, suite_id, external_id, title, section, priority, preconditions, description,
+			tags, custom_fields, checksum, created_at, updated_at
+		FROM test_cases
+		WHERE project_id = ? AND (? = 0 OR suite_id = ?) AND id > ?
+		ORDER BY id
+		LIMIT ?
+	`, projectID, suiteID, suiteID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type TestCaseStepRepository struct {
+	Logger *slog.Logger
+}
+
+func NewTestCaseStepRepository(logger *slog.Logger) *TestCaseStepRepository {
+	return &TestCaseStepRepository{
+		Logger: logger,
+	}
+}
+
+// ReplaceByCaseIDs deletes the steps of the cases and inserts steps in their place
+func (r *TestCaseStepRepository) ReplaceByCaseIDs(tx *sqlx.Tx, caseIDs []int, steps []*entity.TestCaseStep) error {
+	if len(caseIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`DELETE FROM test_case_steps WHERE case_id IN (?)`, caseIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build test case step query: %w", err)
+	}
+	if _, err = tx.Exec(tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to delete test case steps: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+
+	var insert strings.Builder
+	insert.WriteString(`INSERT INTO test_case_steps (case_id, position, action, expected) VALUES `)
+	args = make([]any, 0, len(steps)*4)
+	for i, step := range steps {
+		if i > 0 {
+			insert.WriteString(", ")
+		}
+		insert.WriteString("(?, ?, ?, ?)")
+		args = append(args, step.CaseID, step.Position, step.Action, step.Expected)
+	}
+	if _, err = tx.Exec(insert.String(), args...); err != nil {
+		return fmt.Errorf("failed to insert test case steps: %w", err)
+	}
+	return nil
+}
+
+// ListByCaseID retrieves the steps of a case by position
+func (r *TestCaseStepRepository) ListByCaseID(tx *sqlx.Tx, caseID int) ([]*entity.TestCaseStep, error) {
+	var steps []*entity.TestCaseStep
+	err := tx.Select(&steps, `
+		SELECT id, case_id, position, action, expected
+		FROM test_case_steps
+		WHERE case_id = ?
+		ORDER BY position
+	`, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type TestResultRepository struct {
+	Logger *slog.Logger
+}
+
+func NewTestResultRepository(logger *slog.Logger) *TestResultRepository {
+	return &TestResultRepository{
+		Logger: logger,
+	}
+}
+
+// SaveBatch inserts the results in one statement. Results whose sequence is already stored
+// in the run are ignored, the number of inserted rows is returned
+func (r *TestResultRepository) SaveBatch(tx *sqlx.Tx, results []*entity.TestResult) (int, error) {
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	var query strings.Builder
+	query.WriteString(`INSERT IGNORE INTO test_results
+		(run_id, sequence, case_key, status, duration_ms, message, created_at) VALUES `)
+	args := make([]any, 0, len(results)*7)
+	now := time.Now()
+	for i, result := range results {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, result.RunID, result.Sequence, result.CaseKey, result.Status,
+			result.DurationMs, result.Message, now)
+	}
+
+	inserted, err := tx.Exec(query.String(), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert test results: %w", err)
+	}
+	affected, err := inserted.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return int(affected), nil
+}
+
+// ListByRunIDs retrieves the first results of each run by sequence, at most limit per run,
+// only those with status when it is set
+func (r *TestResultRepository) ListByRunIDs(tx *sqlx.Tx, runIDs []int, status string, limit int) ([]*entity.TestResult, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, run_id, sequence, case_key, status, duration_ms, message, created_at
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY run_id ORDER BY sequence) AS position
+			FROM test_results
+			WHERE run_id IN (?) AND (? = '' OR status = ?)
+		) ranked
+		WHERE position <= ?
+		ORDER BY run_id, sequence
+	`, runIDs, status, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test result query: %w", err)
+	}
+
+	var results []*entity.TestResult
+	if err = tx.Select(&results, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CountByStatus counts the results of a run by status
+func (r *TestResultRepository) CountByStatus(tx *sqlx.Tx, runID int) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	err := tx.Select(&rows, `SELECT status, COUNT(*) AS count FROM test_results WHERE run_id = ? GROUP BY status`, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// StoredSequences returns which of the sequences are already stored in the run
+func (r *TestResultRepository) StoredSequences(tx *sqlx.Tx, runID int, sequences []int64) (map[int64]bool, error) {
+	stored := make(map[int64]bool)
+	if len(sequences) == 0 {
+		return stored, nil
+	}
+	query, args, err := sqlx.In(`SELECT sequence FROM test_results WHERE run_id = ? AND sequence IN (?)`,
+		runID, sequences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test result query: %w", err)
+	}
+
+	var found []int64
+	if err = tx.Select(&found, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, sequence := range found {
+		stored[sequence] = true
+	}
+	return stored, nil
+}
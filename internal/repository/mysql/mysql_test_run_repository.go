@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type TestRunRepository struct {
+	Logger *slog.Logger
+}
+
+func NewTestRunRepository(logger *slog.Logger) *TestRunRepository {
+	return &TestRunRepository{
+		Logger: logger,
+	}
+}
+
+// Save creates a new open test run in the database
+func (r *TestRunRepository) Save(tx *sqlx.Tx, run *entity.TestRun) (*entity.TestRun, error) {
+	query := `
+		INSERT INTO test_runs (project_id, name, status, last_sequence, result_count, created_at, updated_at)
+		VALUES (?, ?, ?, 0, 0, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := tx.Exec(query, run.ProjectID, run.Name, entity.TestRunStatusOpen, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert test run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	run.ID = int(id)
+	run.Status = entity.TestRunStatusOpen
+	run.CreatedAt = now
+	run.UpdatedAt = now
+
+	return run, nil
+}
+
+// GetByID retrieves a test run by its ID
+func (r *TestRunRepository) GetByID(tx *sqlx.Tx, id int) (*entity.TestRun, error) {
+	return r.get(tx, id, "")
+}
+
+// GetByIDForUpdate retrieves a test run by its ID and locks it until the transaction ends,
+// so uploads to the same run are applied one batch at a time
+func (r *TestRunRepository) GetByIDForUpdate(tx *sqlx.Tx, id int) (*entity.TestRun, error) {
+	return r.get(tx, id, "FOR UPDATE")
+}
+
+func (r *TestRunRepository) get(tx *sqlx.Tx, id int, lock string) (*entity.TestRun, error) {
+	query := `
+		SELECT id, project_id, name, status, last_sequence, result_count, created_at, updated_at, closed_at
+		FROM test_runs
+		WHERE id = ?
+	` + lock
+
+	var run entity.TestRun
+	err := tx.Get(&run, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// GetByIDs retrieves the test runs with the given IDs, missing ones are left out
+func (r *TestRunRepository) GetByIDs(tx *sqlx.Tx, ids []int) ([]*entity.TestRun, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, project_id, name, status, last_sequence, result_count, created_at, updated_at, closed_at
+		FROM test_runs
+		WHERE id IN (?)
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test run query: %w", err)
+	}
+
+	var runs []*entity.TestRun
+	if err = tx.Select(&runs, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// ListByProjectIDs retrieves the newest runs of each project, at most limit per project,
+// only those with status when it is set
+func (r *TestRunRepository) ListByProjectIDs(tx *sqlx.Tx, projectIDs []int, status string, limit int) ([]*entity.TestRun, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, project_id, name, status, last_sequence, result_count, created_at, updated_at, closed_at
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY id DESC) AS position
+			FROM test_runs
+			WHERE project_id IN (?) AND (? = '' OR status = ?)
+		) ranked
+		WHERE position <= ?
+		ORDER BY project_id, id DESC
+	`, projectIDs, status, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test run query: %w", err)
+	}
+
+	var runs []*entity.TestRun
+	if err = tx.Select(&runs, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// AdvanceSequence records a committed batch, it moves the last sequence of an open run forward
+func (r *TestRunRepository) AdvanceSequence(tx *sqlx.Tx, id int, lastSequence int64, added int) error {
+	query := `
+		UPDATE test_runs
+		SET last_sequence = GREATEST(last_sequence, ?), result_count = result_count + ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := tx.Exec(query, lastSequence, added, time.Now(), id, entity.TestRunStatusOpen)
+	if err != nil {
+		return fmt.Errorf("failed to advance test run sequence: %w", err)
+	}
+	return checkConditionalWrite(result)
+}
+
+// Close stops an open run from accepting results
+func (r *TestRunRepository) Close(tx *sqlx.Tx, id int) error {
+	return r.CloseAt(tx, id, time.Now())
+}
+
+// CloseAt closes an open run recording closedAt as its closed time, for runs of the past
+func (r *TestRunRepository) CloseAt(tx *sqlx.Tx, id int, closedAt time.Time) error {
+	query := `
+		UPDATE test_runs
+		SET status = ?, closed_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := tx.Exec(query, entity.TestRunStatusClosed, closedAt, id, entity.TestRunStatusOpen)
+	if err != nil {
+		return fmt.Errorf("failed to close test run: %w", err)
+	}
+	return checkConditionalWrite(result)
+}
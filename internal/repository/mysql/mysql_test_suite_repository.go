@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+)
+
+type TestSuiteRepository struct {
+	Logger *slog.Logger
+}
+
+func NewTestSuiteRepository(logger *slog.Logger) *TestSuiteRepository {
+	return &TestSuiteRepository{
+		Logger: logger,
+	}
+}
+
+// Save creates a new test suite in the database
+func (r *TestSuiteRepository) Save(tx *sqlx.Tx, suite *entity.TestSuite) (*entity.TestSuite, error) {
+	query := `
+		INSERT INTO test_suites (project_id, name, description, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := tx.Exec(query, suite.ProjectID, suite.Name, suite.Description, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert test suite: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	suite.ID = int(id)
+	suite.CreatedAt = now
+	suite.UpdatedAt = now
+
+	return suite, nil
+}
+
+// Update writes the name and description of a suite
+func (r *TestSuiteRepository) Update(tx *sqlx.Tx, suite *entity.TestSuite) (*entity.TestSuite, error) {
+	query := `
+		UPDATE test_suites
+		SET name = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	now := time.Now()
+	if _, err := tx.Exec(query, suite.Name, suite.Description, now, suite.ID); err != nil {
+		return nil, fmt.Errorf("failed to update test suite: %w", err)
+	}
+
+	suite.UpdatedAt = now
+	return suite, nil
+}
+
+// GetByIDs retrieves the suites of a project with the given IDs, missing ones are left out
+func (r *TestSuiteRepository) GetByIDs(tx *sqlx.Tx, projectID int, ids []int) ([]*entity.TestSuite, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, project_id, name, description, created_at, updated_at
+		FROM test_suites
+		WHERE project_id = ? AND id IN (?)
+	`, projectID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build test suite query: %w", err)
+	}
+
+	var suites []*entity.TestSuite
+	if err = tx.Select(&suites, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return suites, nil
+}
+
+// ListByProjectID retrieves the suites of a project by ID
+func (r *TestSuiteRepository) ListByProjectID(tx *sqlx.Tx, projectID int) ([]*entity.TestSuite, error) {
+	var suites []*entity.TestSuite
+	err := tx.Select(&suites, `
+		SELECT id, project_id, name, description, created_at, updated_at
+		FROM test_suites
+		WHERE project_id = ?
+		ORDER BY id
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return suites, nil
+}
@@ -0,0 +1,31 @@
+package dashboard
+
+import (
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+)
+
+const (
+	logTag = "service.dashboard"
+)
+
+type DashboardServiceImpl struct {
+	Logger               *slog.Logger
+	DB                   *sqlx.DB
+	ProjectRepository    *mysql.ProjectRepository
+	TestRunRepository    *mysql.TestRunRepository
+	TestResultRepository *mysql.TestResultRepository
+}
+
+func NewDashboardService(logger *slog.Logger, db *sqlx.DB, projectRepository *mysql.ProjectRepository,
+	testRunRepository *mysql.TestRunRepository, testResultRepository *mysql.TestResultRepository) *DashboardServiceImpl {
+	return &DashboardServiceImpl{
+		Logger:               logger,
+		DB:                   db,
+		ProjectRepository:    projectRepository,
+		TestRunRepository:    testRunRepository,
+		TestResultRepository: testResultRepository,
+	}
+}
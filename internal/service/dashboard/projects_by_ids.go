@@ -0,0 +1,31 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// ProjectsByIDs returns the projects by ID, missing and deleted ones are left out
+func (d *DashboardServiceImpl) ProjectsByIDs(ctx context.Context, ids []int) (map[int]*model.ProjectResponse, error) {
+	tx := d.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	projects, err := d.ProjectRepository.GetByIDs(tx, ids)
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "ProjectsByIDs GetByIDs error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	byID := make(map[int]*model.ProjectResponse, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = converter.ProjectToDetailResponse(project)
+	}
+	return byID, nil
+}
@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// TestResultsByRunIDs returns the first results of each run by sequence matching filter
+func (d *DashboardServiceImpl) TestResultsByRunIDs(ctx context.Context, runIDs []int,
+	filter model.ListFilter) (map[int][]*model.TestResultResponse, error) {
+	tx := d.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	results, err := d.TestResultRepository.ListByRunIDs(tx, runIDs, filter.Status, filter.First)
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "TestResultsByRunIDs ListByRunIDs error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	byRun := make(map[int][]*model.TestResultResponse, len(runIDs))
+	for _, result := range results {
+		byRun[result.RunID] = append(byRun[result.RunID], converter.TestResultToResponse(result))
+	}
+	return byRun, nil
+}
@@ -0,0 +1,31 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// TestRunsByIDs returns the test runs by ID, missing ones are left out
+func (d *DashboardServiceImpl) TestRunsByIDs(ctx context.Context, ids []int) (map[int]*model.TestRunResponse, error) {
+	tx := d.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	runs, err := d.TestRunRepository.GetByIDs(tx, ids)
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "TestRunsByIDs GetByIDs error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	byID := make(map[int]*model.TestRunResponse, len(runs))
+	for _, run := range runs {
+		byID[run.ID] = converter.TestRunToResponse(run)
+	}
+	return byID, nil
+}
@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// TestRunsByProjectIDs returns the newest runs of each project matching filter
+func (d *DashboardServiceImpl) TestRunsByProjectIDs(ctx context.Context, projectIDs []int,
+	filter model.ListFilter) (map[int][]*model.TestRunResponse, error) {
+	tx := d.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	runs, err := d.TestRunRepository.ListByProjectIDs(tx, projectIDs, filter.Status, filter.First)
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "TestRunsByProjectIDs ListByProjectIDs error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	byProject := make(map[int][]*model.TestRunResponse, len(projectIDs))
+	for _, run := range runs {
+		byProject[run.ProjectID] = append(byProject[run.ProjectID], converter.TestRunToResponse(run))
+	}
+	return byProject, nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// IDashboardService reads entities for many parents at once, so that nested queries
+// cost one database round trip per level instead of one per parent
+type IDashboardService interface {
+	ProjectsByIDs(ctx context.Context, ids []int) (map[int]*model.ProjectResponse, error)
+	TestRunsByIDs(ctx context.Context, ids []int) (map[int]*model.TestRunResponse, error)
+	TestRunsByProjectIDs(ctx context.Context, projectIDs []int,
+		filter model.ListFilter) (map[int][]*model.TestRunResponse, error)
+	TestResultsByRunIDs(ctx context.Context, runIDs []int,
+		filter model.ListFilter) (map[int][]*model.TestResultResponse, error)
+}
@@ -0,0 +1,50 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+// AddComment stores a comment on a case or on the run, closed runs can still be discussed
+func (e *ExecutionServiceImpl) AddComment(ctx context.Context, runID int, tester string,
+	request *model.AddCommentRequest) (*model.RunCommentResponse, error) {
+	tx := e.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	// Callers other than the transports may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		e.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	if _, err := e.getRun(ctx, tx, runID, false, false); err != nil {
+		return nil, err
+	}
+
+	comment, err := e.RunCommentRepository.Save(tx, &entity.RunComment{
+		RunID:   runID,
+		CaseKey: request.CaseKey,
+		Author:  tester,
+		Body:    request.Body,
+	})
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "Save run comment error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	if err = tx.Commit(); err != nil {
+		e.Logger.ErrorContext(ctx, "Commit run comment error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	return converter.RunCommentToResponse(comment), nil
+}
@@ -0,0 +1,66 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+)
+
+const (
+	logTag = "service.execution"
+	// sessionComments is how many of the latest comments a tester joining a session gets
+	sessionComments = 100
+)
+
+type ExecutionServiceImpl struct {
+	Logger                      *slog.Logger
+	DB                          *sqlx.DB
+	TestRunRepository           *mysql.TestRunRepository
+	CaseExecutionRepository     *mysql.CaseExecutionRepository
+	CaseExecutionStepRepository *mysql.CaseExecutionStepRepository
+	RunCommentRepository        *mysql.RunCommentRepository
+}
+
+func NewExecutionService(logger *slog.Logger, db *sqlx.DB, testRunRepository *mysql.TestRunRepository,
+	caseExecutionRepository *mysql.CaseExecutionRepository, caseExecutionStepRepository *mysql.CaseExecutionStepRepository,
+	runCommentRepository *mysql.RunCommentRepository) *ExecutionServiceImpl {
+	return &ExecutionServiceImpl{
+		Logger:                      logger,
+		DB:                          db,
+		TestRunRepository:           testRunRepository,
+		CaseExecutionRepository:     caseExecutionRepository,
+		CaseExecutionStepRepository: caseExecutionStepRepository,
+		RunCommentRepository:        runCommentRepository,
+	}
+}
+
+// getRun loads a run, locking it until the transaction ends when forUpdate is set.
+// With open set a closed run is refused
+func (e *ExecutionServiceImpl) getRun(ctx context.Context, tx *sqlx.Tx, id int, forUpdate, open bool) (*entity.TestRun, error) {
+	get := e.TestRunRepository.GetByID
+	if forUpdate {
+		get = e.TestRunRepository.GetByIDForUpdate
+	}
+	run, err := get(tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		e.Logger.ErrorContext(ctx, "Get test run error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	if open && run.Status != entity.TestRunStatusOpen {
+		return nil, common.NewServiceError(common.ErrCode_Unprocessable, []common.ErrorDetail{{
+			ErrorCode: "RUN_CLOSED",
+			Message:   "the test run is closed and accepts no more results",
+		}})
+	}
+	return run, nil
+}
@@ -0,0 +1,65 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// GetRunSession returns the run with the executions of its cases and its latest comments
+func (e *ExecutionServiceImpl) GetRunSession(ctx context.Context, runID int) (*model.RunSessionSnapshot, error) {
+	tx := e.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	run, err := e.getRun(ctx, tx, runID, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	executions, err := e.CaseExecutionRepository.ListByRunID(tx, runID)
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "GetRunSession ListByRunID error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	snapshot := &model.RunSessionSnapshot{
+		Run:        converter.TestRunToResponse(run),
+		Executions: make([]*model.CaseExecutionResponse, 0, len(executions)),
+		Comments:   []*model.RunCommentResponse{},
+	}
+
+	byID := make(map[int]*model.CaseExecutionResponse, len(executions))
+	executionIDs := make([]int, 0, len(executions))
+	for _, execution := range executions {
+		response := converter.CaseExecutionToResponse(execution)
+		snapshot.Executions = append(snapshot.Executions, response)
+		byID[execution.ID] = response
+		executionIDs = append(executionIDs, execution.ID)
+	}
+	if len(executionIDs) > 0 {
+		steps, err := e.CaseExecutionStepRepository.ListByExecutionIDs(tx, executionIDs)
+		if err != nil {
+			e.Logger.ErrorContext(ctx, "GetRunSession ListByExecutionIDs error", "tag", logTag, "error", err)
+			return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+		}
+		for _, step := range steps {
+			execution := byID[step.ExecutionID]
+			execution.Steps = append(execution.Steps, converter.CaseStepToResponse(execution.CaseKey, step))
+		}
+	}
+
+	comments, err := e.RunCommentRepository.ListLatestByRunID(tx, runID, sessionComments)
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "GetRunSession ListLatestByRunID error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	for _, comment := range comments {
+		snapshot.Comments = append(snapshot.Comments, converter.RunCommentToResponse(comment))
+	}
+	return snapshot, nil
+}
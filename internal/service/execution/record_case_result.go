@@ -0,0 +1,94 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+// RecordCaseResult records the result of a case unless another one was recorded since the tester
+// saw request.Version, which is a RESULT_CONFLICT carrying the current execution. The tester
+// resolves it by recording again with the current version, or by keeping the other result.
+// Manual results are kept apart from the uploaded ones, whose sequences belong to the uploader
+func (e *ExecutionServiceImpl) RecordCaseResult(ctx context.Context, runID int, tester string,
+	request *model.RecordCaseResultRequest) (*model.CaseExecutionResponse, error) {
+	tx := e.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	// Callers other than the transports may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		e.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	if _, err := e.getRun(ctx, tx, runID, false, true); err != nil {
+		return nil, err
+	}
+
+	execution, err := e.getExecution(ctx, tx, runID, request.CaseKey)
+	if err != nil {
+		return nil, err
+	}
+	if execution.Version != request.Version {
+		return nil, resultConflictError(execution)
+	}
+
+	execution.Status = request.Status
+	execution.Message = request.Message
+	execution.DurationMs = request.DurationMs
+	execution.RecordedBy = tester
+	execution, err = e.CaseExecutionRepository.RecordResult(tx, execution, request.Version)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "Record case result error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	return converter.CaseExecutionToResponse(execution), nil
+}
+
+// getExecution loads the execution of a case for update, creating it when it is missing
+func (e *ExecutionServiceImpl) getExecution(ctx context.Context, tx *sqlx.Tx, runID int,
+	caseKey string) (*entity.CaseExecution, error) {
+	executionID, err := e.CaseExecutionRepository.Ensure(tx, runID, caseKey)
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "RecordCaseResult Ensure error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	execution, err := e.CaseExecutionRepository.GetByIDForUpdate(tx, executionID)
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "RecordCaseResult GetByIDForUpdate error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return execution, nil
+}
+
+func resultConflictError(current *entity.CaseExecution) error {
+	message := "no result is recorded for the case, record it at version 0"
+	if current.Version > 0 {
+		message = fmt.Sprintf("%s recorded %s for the case, the execution is at version %d",
+			current.RecordedBy, current.Status, current.Version)
+	}
+	return common.NewServiceError(common.ErrCode_Conflict, []common.ErrorDetail{{
+		ErrorCode: "RESULT_CONFLICT",
+		Message:   message,
+		Path:      "version",
+		Meta: map[string]any{
+			"currentVersion": current.Version,
+			"current":        converter.CaseExecutionToResponse(current),
+		},
+	}})
+}
@@ -0,0 +1,58 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+// RecordStep records the result of a step, starting the execution of the case on its first step.
+// Steps are not versioned, the tester recording a step last wins
+func (e *ExecutionServiceImpl) RecordStep(ctx context.Context, runID int, tester string,
+	request *model.RecordStepRequest) (*model.CaseStepResponse, error) {
+	tx := e.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	// Callers other than the transports may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		e.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	if _, err := e.getRun(ctx, tx, runID, false, true); err != nil {
+		return nil, err
+	}
+
+	executionID, err := e.CaseExecutionRepository.Ensure(tx, runID, request.CaseKey)
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "RecordStep Ensure error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	step, err := e.CaseExecutionStepRepository.Save(tx, &entity.CaseExecutionStep{
+		ExecutionID: executionID,
+		Step:        request.Step,
+		Status:      request.Status,
+		Note:        request.Note,
+		UpdatedBy:   tester,
+	})
+	if err != nil {
+		e.Logger.ErrorContext(ctx, "Save case step error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	if err = tx.Commit(); err != nil {
+		e.Logger.ErrorContext(ctx, "Commit case step error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	return converter.CaseStepToResponse(request.CaseKey, step), nil
+}
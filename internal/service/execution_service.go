@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// IExecutionService records the manual execution of the cases of a run, tester names the one acting
+type IExecutionService interface {
+	GetRunSession(ctx context.Context, runID int) (*model.RunSessionSnapshot, error)
+	RecordStep(ctx context.Context, runID int, tester string, request *model.RecordStepRequest) (*model.CaseStepResponse, error)
+	RecordCaseResult(ctx context.Context, runID int, tester string,
+		request *model.RecordCaseResultRequest) (*model.CaseExecutionResponse, error)
+	AddComment(ctx context.Context, runID int, tester string, request *model.AddCommentRequest) (*model.RunCommentResponse, error)
+}
@@ -3,24 +3,51 @@ package project
 import (
 	"log/slog"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
 	"github.com/project-weekend/qms-engine/internal/repository/mysql"
 )
 
 const (
 	logTag = "service.project"
+	// maxReportErrors bounds the case errors reported by an import
+	maxReportErrors = 1000
 )
 
 type ProjectServiceImpl struct {
-	Logger            *slog.Logger
-	DB                *sqlx.DB
-	ProjectRepository *mysql.ProjectRepository
+	Logger                  *slog.Logger
+	DB                      *sqlx.DB
+	Validator               *validator.Validate
+	ProjectRepository       *mysql.ProjectRepository
+	TestSuiteRepository     *mysql.TestSuiteRepository
+	TestCaseRepository      *mysql.TestCaseRepository
+	TestCaseStepRepository  *mysql.TestCaseStepRepository
+	TestRunRepository       *mysql.TestRunRepository
+	TestResultRepository    *mysql.TestResultRepository
+	ImportMappingRepository *mysql.ImportMappingRepository
+	// BatchSize is how many rows an import writes in one statement
+	BatchSize int
+	// MaxImportItems bounds the cases and results of an imported export, it is imported in one transaction
+	MaxImportItems int
 }
 
-func NewProjectService(logger *slog.Logger, db *sqlx.DB, projectRepository *mysql.ProjectRepository) *ProjectServiceImpl {
+func NewProjectService(logger *slog.Logger, db *sqlx.DB, validator *validator.Validate,
+	projectRepository *mysql.ProjectRepository, testSuiteRepository *mysql.TestSuiteRepository,
+	testCaseRepository *mysql.TestCaseRepository, testCaseStepRepository *mysql.TestCaseStepRepository,
+	testRunRepository *mysql.TestRunRepository, testResultRepository *mysql.TestResultRepository,
+	importMappingRepository *mysql.ImportMappingRepository, batchSize, maxImportItems int) *ProjectServiceImpl {
 	return &ProjectServiceImpl{
-		Logger:            logger,
-		DB:                db,
-		ProjectRepository: projectRepository,
+		Logger:                  logger,
+		DB:                      db,
+		Validator:               validator,
+		ProjectRepository:       projectRepository,
+		TestSuiteRepository:     testSuiteRepository,
+		TestCaseRepository:      testCaseRepository,
+		TestCaseStepRepository:  testCaseStepRepository,
+		TestRunRepository:       testRunRepository,
+		TestResultRepository:    testResultRepository,
+		ImportMappingRepository: importMappingRepository,
+		BatchSize:               batchSize,
+		MaxImportItems:          maxImportItems,
 	}
 }
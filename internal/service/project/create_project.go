@@ -4,13 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+
 	"github.com/project-weekend/qms-engine/internal/common"
 	"github.com/project-weekend/qms-engine/internal/entity"
 	"github.com/project-weekend/qms-engine/internal/model"
 	"github.com/project-weekend/qms-engine/internal/model/converter"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
 )
 
 func (p *ProjectServiceImpl) CreateProject(ctx context.Context, request *model.CreateProjectRequest) (*model.CreateProjectResponse, error) {
@@ -20,6 +23,29 @@ func (p *ProjectServiceImpl) CreateProject(ctx context.Context, request *model.C
 	})
 	defer tx.Rollback()
 
+	// Callers other than the HTTP handlers may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		p.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	savedProject, err := p.createProject(ctx, tx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		p.Logger.ErrorContext(ctx, "Commit project error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	return converter.ProjectToResponse(savedProject), nil
+}
+
+// createProject saves a project in tx, its normalized name and its key must not be taken
+func (p *ProjectServiceImpl) createProject(ctx context.Context, tx *sqlx.Tx,
+	request *model.CreateProjectRequest) (*entity.Project, error) {
 	existingProject, err := p.ProjectRepository.GetByName(tx, request.Name)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
@@ -28,28 +54,30 @@ func (p *ProjectServiceImpl) CreateProject(ctx context.Context, request *model.C
 		}
 	} else if existingProject != nil {
 		p.Logger.WarnContext(ctx, "CreateProject: project name already exists", "tag", logTag, "name", request.Name)
-		return nil, common.NewServiceError(common.ErrCode_Forbidden, nil)
+		return nil, common.NewServiceError(common.ErrCode_Conflict, nil)
 	}
 
 	project := &entity.Project{
-		Name:        strings.ToLower(request.Name),
+		Name:        request.Name,
 		Description: request.Description,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		DeletedAt:   nil,
 	}
+	if request.Key != "" {
+		project.Key = &request.Key
+	}
 
 	savedProject, err := p.ProjectRepository.Save(tx, project)
-	if err != nil {
-		p.Logger.ErrorContext(ctx, "Save project error", "tag", logTag, "error", err)
-		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	if errors.Is(err, mysql.ErrDuplicate) {
+		// Another request created the name after the check above, or the key is taken
+		p.Logger.WarnContext(ctx, "CreateProject: project name or key already exists", "tag", logTag,
+			"name", request.Name, "key", request.Key)
+		return nil, common.NewServiceError(common.ErrCode_Conflict, nil)
 	}
-
-	err = tx.Commit()
 	if err != nil {
-		p.Logger.ErrorContext(ctx, "Commit project error", "tag", logTag, "error", err)
+		p.Logger.ErrorContext(ctx, "Save project error", "tag", logTag, "error", err)
 		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
 	}
-
-	return converter.ProjectToResponse(savedProject), nil
+	return savedProject, nil
 }
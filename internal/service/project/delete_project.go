@@ -0,0 +1,32 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+)
+
+// DeleteProject soft deletes a project that is still at expectedVersion, or at any version
+// for service.AnyVersion
+func (p *ProjectServiceImpl) DeleteProject(ctx context.Context, id, expectedVersion int) error {
+	tx := p.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	project, err := p.getForWrite(ctx, tx, id, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if err = p.ProjectRepository.Delete(tx, id, project.Version); err != nil {
+		return p.writeError(ctx, tx, id, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		p.Logger.ErrorContext(ctx, "Commit project error", "tag", logTag, "error", err)
+		return common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return nil
+}
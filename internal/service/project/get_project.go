@@ -0,0 +1,30 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+func (p *ProjectServiceImpl) GetProject(ctx context.Context, id int) (*model.ProjectResponse, error) {
+	tx := p.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	project, err := p.ProjectRepository.GetByID(tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		p.Logger.ErrorContext(ctx, "GetProject GetByID error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	return converter.ProjectToDetailResponse(project), nil
+}
@@ -0,0 +1,325 @@
+package project
+
+import (
+	"cmp"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/importer"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// projectImport is an import under way in tx. mapped holds what the items of the tool were
+// imported as, by kind and ID in the tool, added the mappings of the items first imported now
+type projectImport struct {
+	*ProjectServiceImpl
+	tx        *sqlx.Tx
+	projectID int
+	source    string
+	report    *model.ImportProjectResponse
+	mapped    map[string]map[string]int
+	added     []*entity.ImportMapping
+	// suiteIDs maps the ID of a suite in the tool to its ID here
+	suiteIDs map[string]int
+}
+
+// importExport writes the suites, cases and runs of an export into a project, counting them in report
+func (p *ProjectServiceImpl) importExport(tx *sqlx.Tx, projectID int, source string, export *importer.Export,
+	cases []*importedCase, report *model.ImportProjectResponse) error {
+	mappings, err := p.ImportMappingRepository.ListBySource(tx, projectID, source)
+	if err != nil {
+		return err
+	}
+	imp := &projectImport{
+		ProjectServiceImpl: p,
+		tx:                 tx,
+		projectID:          projectID,
+		source:             source,
+		report:             report,
+		mapped: map[string]map[string]int{
+			entity.ImportKindSuite: {}, entity.ImportKindCase: {}, entity.ImportKindRun: {},
+		},
+		suiteIDs: make(map[string]int, len(export.Suites)),
+	}
+	for _, mapping := range mappings {
+		imp.mapped[mapping.Kind][mapping.SourceID] = mapping.TargetID
+	}
+
+	if err = imp.importSuites(export.Suites); err != nil {
+		return err
+	}
+	if err = imp.importCases(cases); err != nil {
+		return err
+	}
+	if err = imp.importRuns(export.Runs); err != nil {
+		return err
+	}
+	for batch := range slices.Chunk(imp.added, p.BatchSize) {
+		if err = p.ImportMappingRepository.SaveBatch(tx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapTo records that the item of the tool was imported as targetID
+func (imp *projectImport) mapTo(kind, sourceID string, targetID int) {
+	imp.mapped[kind][sourceID] = targetID
+	imp.added = append(imp.added, &entity.ImportMapping{
+		ProjectID: imp.projectID, Source: imp.source, Kind: kind, SourceID: sourceID, TargetID: targetID,
+	})
+}
+
+func (imp *projectImport) importSuites(suites []*importer.Suite) error {
+	var ids []int
+	for _, s := range suites {
+		if id, ok := imp.mapped[entity.ImportKindSuite][s.SourceID]; ok {
+			ids = append(ids, id)
+		}
+	}
+	stored := make(map[int]*entity.TestSuite, len(ids))
+	if len(ids) > 0 {
+		existing, err := imp.TestSuiteRepository.GetByIDs(imp.tx, imp.projectID, ids)
+		if err != nil {
+			return err
+		}
+		for _, suite := range existing {
+			stored[suite.ID] = suite
+		}
+	}
+
+	for _, s := range suites {
+		suite := &entity.TestSuite{
+			ProjectID: imp.projectID, Name: truncate(cmp.Or(s.Name, s.SourceID), 255), Description: s.Description,
+		}
+		current, ok := stored[imp.mapped[entity.ImportKindSuite][s.SourceID]]
+		switch {
+		case !ok:
+			if _, err := imp.TestSuiteRepository.Save(imp.tx, suite); err != nil {
+				return err
+			}
+			imp.mapTo(entity.ImportKindSuite, s.SourceID, suite.ID)
+			imp.report.Suites.Created++
+		case current.Name != suite.Name || current.Description != suite.Description:
+			suite.ID = current.ID
+			if _, err := imp.TestSuiteRepository.Update(imp.tx, suite); err != nil {
+				return err
+			}
+			imp.report.Suites.Updated++
+		default:
+			suite.ID = current.ID
+			imp.report.Suites.Unchanged++
+		}
+		imp.suiteIDs[s.SourceID] = suite.ID
+	}
+	return nil
+}
+
+// importCases creates the new cases and updates the changed ones. A case of the tool imported for
+// the first time takes over the case with its key, e.g. one imported from a sheet before
+func (imp *projectImport) importCases(cases []*importedCase) error {
+	var ids []int
+	var keys []string
+	for _, c := range cases {
+		c.testCase.ProjectID = imp.projectID
+		if suiteID, ok := imp.suiteIDs[c.source.SuiteID]; ok {
+			c.testCase.SuiteID = &suiteID
+		}
+		if id, ok := imp.mapped[entity.ImportKindCase][c.source.SourceID]; ok {
+			ids = append(ids, id)
+		} else {
+			keys = append(keys, c.testCase.ExternalID)
+		}
+	}
+	byID, byKey, err := imp.storedCases(ids, keys)
+	if err != nil {
+		return err
+	}
+
+	created, updated := imp.planCases(cases, byID, byKey)
+	imp.report.Cases.Created += len(created)
+	imp.report.Cases.Updated += len(updated)
+	imp.report.Cases.Unchanged += len(cases) - len(created) - len(updated)
+
+	if err = imp.createCases(created); err != nil {
+		return err
+	}
+	for _, c := range updated {
+		if _, err = imp.TestCaseRepository.Update(imp.tx, c.testCase); err != nil {
+			return err
+		}
+	}
+	return imp.replaceSteps(slices.Concat(created, updated))
+}
+
+// planCases sorts the cases into those to create and the changed ones to update, giving the latter
+// the ID of the stored case
+func (imp *projectImport) planCases(cases []*importedCase, byID map[int]*entity.TestCase,
+	byKey map[string]*entity.TestCase) (created, updated []*importedCase) {
+	for _, c := range cases {
+		current, ok := byID[imp.mapped[entity.ImportKindCase][c.source.SourceID]]
+		if !ok {
+			if current, ok = byKey[c.testCase.ExternalID]; ok {
+				imp.mapTo(entity.ImportKindCase, c.source.SourceID, current.ID)
+			}
+		}
+		switch {
+		case !ok:
+			created = append(created, c)
+		case current.Checksum != c.testCase.Checksum:
+			c.testCase.ID = current.ID
+			updated = append(updated, c)
+		}
+	}
+	return created, updated
+}
+
+// storedCases locks the cases mapped before by ID and those with the keys of the others
+func (imp *projectImport) storedCases(ids []int, keys []string) (byID map[int]*entity.TestCase,
+	byKey map[string]*entity.TestCase, err error) {
+	byID = make(map[int]*entity.TestCase, len(ids))
+	for batch := range slices.Chunk(ids, imp.BatchSize) {
+		stored, err := imp.TestCaseRepository.GetByIDsForUpdate(imp.tx, imp.projectID, batch)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, testCase := range stored {
+			byID[testCase.ID] = testCase
+		}
+	}
+	byKey = make(map[string]*entity.TestCase, len(keys))
+	for batch := range slices.Chunk(keys, imp.BatchSize) {
+		stored, err := imp.TestCaseRepository.ListByExternalIDsForUpdate(imp.tx, imp.projectID, batch)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, testCase := range stored {
+			byKey[testCase.ExternalID] = testCase
+		}
+	}
+	return byID, byKey, nil
+}
+
+// createCases inserts the new cases, reads their IDs back and maps them
+func (imp *projectImport) createCases(created []*importedCase) error {
+	for batch := range slices.Chunk(created, imp.BatchSize) {
+		newCases := make([]*entity.TestCase, len(batch))
+		keys := make([]string, len(batch))
+		for i, c := range batch {
+			newCases[i] = c.testCase
+			keys[i] = c.testCase.ExternalID
+		}
+		if err := imp.TestCaseRepository.SaveBatch(imp.tx, newCases); err != nil {
+			return err
+		}
+		saved, err := imp.TestCaseRepository.ListByExternalIDs(imp.tx, imp.projectID, keys)
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]int, len(saved))
+		for _, testCase := range saved {
+			ids[testCase.ExternalID] = testCase.ID
+		}
+		for _, c := range batch {
+			c.testCase.ID = ids[c.testCase.ExternalID]
+			imp.mapTo(entity.ImportKindCase, c.source.SourceID, c.testCase.ID)
+		}
+	}
+	return nil
+}
+
+func (imp *projectImport) replaceSteps(cases []*importedCase) error {
+	for batch := range slices.Chunk(cases, imp.BatchSize) {
+		caseIDs := make([]int, len(batch))
+		var steps []*entity.TestCaseStep
+		for i, c := range batch {
+			caseIDs[i] = c.testCase.ID
+			for position, step := range c.steps {
+				steps = append(steps, &entity.TestCaseStep{
+					CaseID: c.testCase.ID, Position: position + 1, Action: step.Action, Expected: step.Expected,
+				})
+			}
+		}
+		if err := imp.TestCaseStepRepository.ReplaceByCaseIDs(imp.tx, caseIDs, steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importRuns creates the runs imported for the first time as closed runs of their results.
+// Runs imported before are history and kept as they are
+func (imp *projectImport) importRuns(runs []*importer.Run) error {
+	for _, r := range runs {
+		if _, ok := imp.mapped[entity.ImportKindRun][r.SourceID]; ok {
+			imp.report.Runs.Unchanged++
+			continue
+		}
+		run, err := imp.TestRunRepository.Save(imp.tx, &entity.TestRun{
+			ProjectID: imp.projectID, Name: truncate(cmp.Or(r.Name, r.SourceID), 100),
+		})
+		if err != nil {
+			return err
+		}
+		if err = imp.storeResults(run.ID, r.Results); err != nil {
+			return err
+		}
+		closedAt := time.Now()
+		if r.ClosedAt != nil {
+			closedAt = *r.ClosedAt
+		}
+		if err = imp.TestRunRepository.CloseAt(imp.tx, run.ID, closedAt); err != nil {
+			return err
+		}
+		imp.mapTo(entity.ImportKindRun, r.SourceID, run.ID)
+		imp.report.Runs.Created++
+	}
+	return nil
+}
+
+// storeResults stores the results of a new run in their order, those naming no case are left out
+func (imp *projectImport) storeResults(runID int, results []*importer.Result) error {
+	kept := make([]*entity.TestResult, 0, len(results))
+	for _, result := range results {
+		if result.CaseKey == "" {
+			continue
+		}
+		kept = append(kept, &entity.TestResult{
+			RunID:      runID,
+			Sequence:   int64(len(kept) + 1),
+			CaseKey:    truncate(result.CaseKey, 255),
+			Status:     result.Status,
+			DurationMs: max(result.DurationMs, 0),
+			Message:    truncate(result.Message, 65535),
+		})
+	}
+
+	stored := 0
+	for batch := range slices.Chunk(kept, imp.BatchSize) {
+		inserted, err := imp.TestResultRepository.SaveBatch(imp.tx, batch)
+		if err != nil {
+			return err
+		}
+		stored += inserted
+	}
+	imp.report.Results += stored
+	if stored == 0 {
+		return nil
+	}
+	return imp.TestRunRepository.AdvanceSequence(imp.tx, runID, int64(len(kept)), stored)
+}
+
+// truncate cuts s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,111 @@
+package project
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/importer"
+)
+
+func imported(cases ...*importer.Case) []*importedCase {
+	result := make([]*importedCase, len(cases))
+	for i, c := range cases {
+		result[i] = &importedCase{source: c, testCase: importedCaseOf(c, caseRow(c))}
+	}
+	return result
+}
+
+func sourceIDs(cases []*importedCase) []string {
+	var ids []string
+	for _, c := range cases {
+		ids = append(ids, c.source.SourceID)
+	}
+	return ids
+}
+
+func TestPlanCasesReimport(t *testing.T) {
+	login := &importer.Case{SourceID: "10100", Key: "ACC-7", Title: "Sign in", Steps: []*importer.Step{{Action: "Submit"}}}
+	reset := &importer.Case{SourceID: "10101", Key: "ACC-8", Title: "Reset a password",
+		CustomFields: map[string]string{"customfield_10200": "Web"}}
+	// stored holds the cases of the first import as 1 and 2, and 3 imported from a sheet with key ACC-9
+	stored := map[int]*entity.TestCase{}
+	for i, c := range imported(login, reset) {
+		stored[i+1] = &entity.TestCase{ID: i + 1, ExternalID: c.testCase.ExternalID, Checksum: c.testCase.Checksum}
+	}
+	sheetCase := &entity.TestCase{ID: 3, ExternalID: "ACC-9", Checksum: "of the sheet"}
+
+	tests := []struct {
+		name        string
+		cases       []*importer.Case
+		byKey       map[string]*entity.TestCase
+		wantCreated []string
+		wantUpdated map[string]int
+		wantAdded   []string
+	}{
+		{
+			name:  "the same export again changes nothing",
+			cases: []*importer.Case{login, reset},
+		},
+		{
+			name: "changed cases are updated in place",
+			cases: []*importer.Case{
+				{SourceID: "10100", Key: "ACC-7", Title: "Sign in with a password", Steps: login.Steps},
+				{SourceID: "10101", Key: "ACC-8", Title: "Reset a password",
+					CustomFields: map[string]string{"customfield_10200": "Mobile"}},
+			},
+			wantUpdated: map[string]int{"10100": 1, "10101": 2},
+		},
+		{
+			name: "a case renamed in the tool is found by its ID there, not by its key",
+			cases: []*importer.Case{
+				{SourceID: "10100", Key: "ACC-70", Title: "Sign in", Steps: login.Steps},
+				reset,
+			},
+			wantUpdated: map[string]int{"10100": 1},
+		},
+		{
+			name:        "cases new to the project are created",
+			cases:       []*importer.Case{login, reset, {SourceID: "10102", Key: "ACC-10", Title: "Sign out"}},
+			wantCreated: []string{"10102"},
+		},
+		{
+			name:        "a case imported before from a sheet is taken over by its key",
+			cases:       []*importer.Case{login, reset, {SourceID: "10103", Key: "ACC-9", Title: "Delete the account"}},
+			byKey:       map[string]*entity.TestCase{"ACC-9": sheetCase},
+			wantUpdated: map[string]int{"10103": 3},
+			wantAdded:   []string{"10103"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp := &projectImport{
+				projectID: 3,
+				source:    importer.SourceXray,
+				mapped:    map[string]map[string]int{entity.ImportKindCase: {"10100": 1, "10101": 2}},
+			}
+			created, updated := imp.planCases(imported(tt.cases...), stored, tt.byKey)
+
+			if got := sourceIDs(created); !reflect.DeepEqual(got, tt.wantCreated) {
+				t.Errorf("created = %v, want %v", got, tt.wantCreated)
+			}
+			var gotUpdated map[string]int
+			for _, c := range updated {
+				if gotUpdated == nil {
+					gotUpdated = map[string]int{}
+				}
+				gotUpdated[c.source.SourceID] = c.testCase.ID
+			}
+			if !reflect.DeepEqual(gotUpdated, tt.wantUpdated) {
+				t.Errorf("updated = %v, want %v", gotUpdated, tt.wantUpdated)
+			}
+			var added []string
+			for _, mapping := range imp.added {
+				added = append(added, mapping.SourceID)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added mappings = %v, want %v", added, tt.wantAdded)
+			}
+		})
+	}
+}
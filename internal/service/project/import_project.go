@@ -0,0 +1,241 @@
+package project
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/importer"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+)
+
+// importedCase is a valid case of an export, its entity lacks the project and suite until written
+type importedCase struct {
+	source   *importer.Case
+	testCase *entity.TestCase
+	steps    []*model.TestStepRow
+}
+
+// ImportProject imports the export of another tool in one transaction: its suites, its cases with
+// their steps and custom fields, and its runs with their results. The project is created by the
+// import when the request names one. Items imported before are found by their ID in the tool and
+// updated, runs already imported are kept as they are. Nothing is written while a case is invalid
+func (p *ProjectServiceImpl) ImportProject(ctx context.Context, request *model.ImportProjectRequest,
+	export io.Reader, trans ut.Translator) (*model.ImportProjectResponse, error) {
+	// Callers other than the transports may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		p.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+	parsed, err := p.parseExport(ctx, request, export)
+	if err != nil {
+		return nil, err
+	}
+	cases, err := p.checkCases(parsed.Cases, trans)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := p.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	report := &model.ImportProjectResponse{DryRun: request.DryRun, Unmapped: unmappedFields(parsed.Unmapped)}
+	project, err := p.importTarget(ctx, tx, request, report)
+	if err != nil {
+		return nil, err
+	}
+	report.ProjectID = project.ID
+
+	err = p.importExport(tx, project.ID, request.Source, parsed, cases, report)
+	// A dry run is rolled back once reported
+	if err == nil && !request.DryRun {
+		err = tx.Commit()
+	}
+	if errors.Is(err, mysql.ErrDuplicate) {
+		// Another import created one of the cases after they were listed
+		return nil, common.NewServiceError(common.ErrCode_Conflict, nil)
+	}
+	if err != nil {
+		p.Logger.ErrorContext(ctx, "ImportProject write error", "tag", logTag, "error", err,
+			"projectId", project.ID, "source", request.Source)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return report, nil
+}
+
+// parseExport reads the export, in the usual format of its tool when the request names none
+func (p *ProjectServiceImpl) parseExport(ctx context.Context, request *model.ImportProjectRequest,
+	export io.Reader) (*importer.Export, error) {
+	if request.Format == "" {
+		request.Format = importer.DefaultFormat(request.Source)
+	}
+	parsed, err := importer.Parse(request.Source, request.Format, export)
+	if errors.Is(err, importer.ErrUnsupportedFormat) {
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "UNSUPPORTED_FORMAT",
+			Message:   fmt.Sprintf("%s has no %s export", request.Source, request.Format),
+			Path:      "format",
+		}})
+	}
+	if err != nil {
+		// The parser error quotes the file, it is logged rather than sent back
+		p.Logger.WarnContext(ctx, "ImportProject: unreadable export", "tag", logTag, "error", err,
+			"source", request.Source, "format", request.Format)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_EXPORT",
+			Message:   fmt.Sprintf("the file is not a %s export of %s that can be read", request.Format, request.Source),
+			Path:      "format",
+		}})
+	}
+
+	items := len(parsed.Cases)
+	for _, run := range parsed.Runs {
+		items += len(run.Results)
+	}
+	if items > p.MaxImportItems {
+		return nil, common.NewServiceError(common.ErrCode_Unprocessable, []common.ErrorDetail{{
+			ErrorCode: "TOO_MANY_ITEMS",
+			Message:   "the export has more cases and results than an import takes, split it",
+			Meta:      map[string]any{"maxItems": p.MaxImportItems},
+		}})
+	}
+	return parsed, nil
+}
+
+// checkCases validates the cases of an export the way the rows of sheets are, a key repeated
+// in the export is an error on the later case. Paths start with the position of the case
+func (p *ProjectServiceImpl) checkCases(cases []*importer.Case, trans ut.Translator) ([]*importedCase, error) {
+	valid := make([]*importedCase, 0, len(cases))
+	var details []common.ErrorDetail
+	firsts := make(map[string]int, len(cases))
+	for i, c := range cases {
+		row := caseRow(c)
+		if err := normalize.Struct(row); err != nil {
+			return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+		}
+		var caseDetails []common.ErrorDetail
+		if err := p.Validator.Struct(row); err != nil {
+			caseDetails = common.ParseValidationErrors(err, trans)
+		}
+		if first, ok := firsts[row.ExternalID]; ok {
+			caseDetails = append(caseDetails, common.ErrorDetail{
+				ErrorCode: "DUPLICATE_EXTERNAL_ID",
+				Message:   fmt.Sprintf("the key is already used by case %d", first),
+				Path:      "externalId",
+				Meta:      map[string]any{"case": first},
+			})
+		} else if row.ExternalID != "" {
+			firsts[row.ExternalID] = i
+		}
+
+		if len(caseDetails) == 0 {
+			valid = append(valid, &importedCase{source: c, testCase: importedCaseOf(c, row), steps: row.Steps})
+		}
+		for _, detail := range caseDetails {
+			if len(details) < maxReportErrors {
+				detail.Path = fmt.Sprintf("cases[%d].%s", i, detail.Path)
+				details = append(details, detail)
+			}
+		}
+	}
+	if len(details) > 0 {
+		return nil, common.NewServiceError(common.ErrCode_Unprocessable, details)
+	}
+	return valid, nil
+}
+
+// caseRow is the case as a sheet row would hold it, its key being the external ID
+func caseRow(c *importer.Case) *model.TestCaseRow {
+	tags := make([]string, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	row := &model.TestCaseRow{
+		ExternalID:    c.Key,
+		Title:         c.Title,
+		Section:       c.Section,
+		Priority:      c.Priority,
+		Preconditions: c.Preconditions,
+		Description:   c.Description,
+		Tags:          tags,
+		Steps:         make([]*model.TestStepRow, len(c.Steps)),
+	}
+	for i, step := range c.Steps {
+		row.Steps[i] = &model.TestStepRow{Action: strings.TrimSpace(step.Action), Expected: strings.TrimSpace(step.Expected)}
+	}
+	return row
+}
+
+// importedCaseOf builds the case of a valid row, its checksum covers the content, the steps, the
+// suite and the custom fields
+func importedCaseOf(c *importer.Case, row *model.TestCaseRow) *entity.TestCase {
+	testCase := &entity.TestCase{
+		ExternalID:    row.ExternalID,
+		Title:         row.Title,
+		Section:       row.Section,
+		Priority:      row.Priority,
+		Preconditions: row.Preconditions,
+		Description:   row.Description,
+		Tags:          strings.Join(row.Tags, ","),
+	}
+	// Marshalling strings cannot fail, map keys are sorted
+	if len(c.CustomFields) > 0 {
+		fields, _ := json.Marshal(c.CustomFields)
+		customFields := string(fields)
+		testCase.CustomFields = &customFields
+	}
+	content, _ := json.Marshal(struct {
+		Row          *model.TestCaseRow
+		Suite        string
+		CustomFields map[string]string
+	}{row, c.SuiteID, c.CustomFields})
+	checksum := sha256.Sum256(content)
+	testCase.Checksum = hex.EncodeToString(checksum[:])
+	return testCase
+}
+
+// importTarget locks the project imported into, or creates it when the request names one
+func (p *ProjectServiceImpl) importTarget(ctx context.Context, tx *sqlx.Tx, request *model.ImportProjectRequest,
+	report *model.ImportProjectResponse) (*entity.Project, error) {
+	if request.Name != "" {
+		report.ProjectCreated = true
+		return p.createProject(ctx, tx, &model.CreateProjectRequest{Name: request.Name, Description: request.Description})
+	}
+
+	// Imports into the same project wait for each other
+	project, err := p.ProjectRepository.GetByIDForUpdate(tx, request.ProjectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+	}
+	if err != nil {
+		p.Logger.ErrorContext(ctx, "ImportProject GetByIDForUpdate error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return project, nil
+}
+
+func unmappedFields(fields []*importer.UnmappedField) []*model.UnmappedField {
+	unmapped := make([]*model.UnmappedField, len(fields))
+	for i, field := range fields {
+		unmapped[i] = &model.UnmappedField{Field: field.Field, Count: field.Count, Samples: field.Samples}
+	}
+	return unmapped
+}
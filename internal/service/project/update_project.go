@@ -0,0 +1,123 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+// UpdateProject applies the request to a project that is still at expectedVersion, or at any
+// version for service.AnyVersion
+func (p *ProjectServiceImpl) UpdateProject(ctx context.Context, id, expectedVersion int,
+	request *model.UpdateProjectRequest) (*model.ProjectResponse, error) {
+	tx := p.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	if err := normalize.Struct(request); err != nil {
+		p.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	project, err := p.getForWrite(ctx, tx, id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil && *request.Name != project.Name {
+		name := *request.Name
+		if _, err = p.ProjectRepository.GetByName(tx, name); err == nil {
+			p.Logger.WarnContext(ctx, "UpdateProject: project name already exists", "tag", logTag, "name", name)
+			return nil, common.NewServiceError(common.ErrCode_Conflict, nil)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			p.Logger.ErrorContext(ctx, "UpdateProject GetByName error", "tag", logTag, "error", err)
+			return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+		}
+		project.Name = name
+	}
+	if request.Key != nil {
+		project.Key = request.Key
+	}
+	if request.Description != nil {
+		project.Description = *request.Description
+	}
+
+	updatedProject, err := p.ProjectRepository.Update(tx, project, project.Version)
+	if errors.Is(err, mysql.ErrDuplicate) {
+		return nil, common.NewServiceError(common.ErrCode_Conflict, nil)
+	}
+	if err != nil {
+		return nil, p.writeError(ctx, tx, id, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		p.Logger.ErrorContext(ctx, "Commit project error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	return converter.ProjectToDetailResponse(updatedProject), nil
+}
+
+// getForWrite loads the project to change, failing when it is no longer at expectedVersion.
+// For service.AnyVersion the project is locked instead, so that the write applies to the version read
+func (p *ProjectServiceImpl) getForWrite(ctx context.Context, tx *sqlx.Tx, id, expectedVersion int) (*entity.Project, error) {
+	get := p.ProjectRepository.GetByID
+	if expectedVersion == service.AnyVersion {
+		get = p.ProjectRepository.GetByIDForUpdate
+	}
+	project, err := get(tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		p.Logger.ErrorContext(ctx, "GetByID error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	if expectedVersion != service.AnyVersion && project.Version != expectedVersion {
+		return nil, versionMismatchError(project.Version)
+	}
+	return project, nil
+}
+
+// writeError maps a failed conditional write. A version mismatch means another writer
+// got in between the read and the write, the current version is read again for the details.
+// The read locks the row, a plain read would return the version of the transaction's snapshot
+func (p *ProjectServiceImpl) writeError(ctx context.Context, tx *sqlx.Tx, id int, err error) error {
+	if !errors.Is(err, mysql.ErrVersionMismatch) {
+		p.Logger.ErrorContext(ctx, "Write project error", "tag", logTag, "error", err)
+		return common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	current, getErr := p.ProjectRepository.GetByIDForUpdate(tx, id)
+	if errors.Is(getErr, sql.ErrNoRows) {
+		return common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+	}
+	if getErr != nil {
+		p.Logger.ErrorContext(ctx, "GetByIDForUpdate error", "tag", logTag, "error", getErr)
+		return common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return versionMismatchError(current.Version)
+}
+
+func versionMismatchError(currentVersion int) error {
+	return common.NewServiceError(common.ErrCode_PreconditionFailed, []common.ErrorDetail{{
+		ErrorCode: "VERSION_MISMATCH",
+		Message:   fmt.Sprintf("the project is at version %d", currentVersion),
+		Path:      "version",
+		Meta:      map[string]any{"currentVersion": currentVersion},
+	}})
+}
@@ -2,10 +2,24 @@ package service
 
 import (
 	"context"
+	"io"
+
+	ut "github.com/go-playground/universal-translator"
 
 	"github.com/project-weekend/qms-engine/internal/model"
 )
 
+// AnyVersion is the expected version of writes sent with If-Match: *, they apply to the
+// project at whatever version it is
+const AnyVersion = 0
+
 type IProjectService interface {
 	CreateProject(ctx context.Context, request *model.CreateProjectRequest) (*model.CreateProjectResponse, error)
+	GetProject(ctx context.Context, id int) (*model.ProjectResponse, error)
+	UpdateProject(ctx context.Context, id, expectedVersion int, request *model.UpdateProjectRequest) (*model.ProjectResponse, error)
+	DeleteProject(ctx context.Context, id, expectedVersion int) error
+	// ImportProject imports the export of another tool into a project, creating it when the request
+	// names one. The case errors are translated with trans
+	ImportProject(ctx context.Context, request *model.ImportProjectRequest, export io.Reader,
+		trans ut.Translator) (*model.ImportProjectResponse, error)
 }
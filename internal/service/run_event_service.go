@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/project-weekend/qms-engine/internal/events"
+)
+
+// IRunEventService delivers the changes of test runs to their subscribers on every instance
+type IRunEventService interface {
+	// Publish sends data as an event of eventType about a run, failures are logged rather than returned
+	Publish(ctx context.Context, runID int, eventType string, data any)
+	// Subscribe follows the events of a run after lastEventID, zero starts without a backlog
+	Subscribe(runID int, lastEventID int64) *events.Subscription
+}
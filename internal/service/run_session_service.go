@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+)
+
+// IRunSessionService serves the WebSocket sessions in which testers execute the cases of a run together
+type IRunSessionService interface {
+	// Join upgrades r to the session of tester and serves it until it closes. It returns an error
+	// only when the request was not upgraded, errors in the session are sent localized with trans
+	Join(ctx context.Context, w http.ResponseWriter, r *http.Request, runID int, tester string, trans ut.Translator) error
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+type ITestCaseService interface {
+	ListTestSuites(ctx context.Context, projectID int) ([]*model.TestSuiteResponse, error)
+	// ListTestCases returns a page of the cases of a project, without their steps
+	ListTestCases(ctx context.Context, projectID int, request *model.ListTestCasesRequest) (*model.ListTestCasesResponse, error)
+	GetTestCase(ctx context.Context, projectID, id int) (*model.TestCaseResponse, error)
+	// ImportCases imports the cases of sheet into a project, the row errors are translated with trans
+	ImportCases(ctx context.Context, projectID int, request *model.ImportCasesRequest, sheet io.Reader,
+		trans ut.Translator) (*model.ImportCasesResponse, error)
+}
@@ -0,0 +1,65 @@
+package testcase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+)
+
+const (
+	logTag = "service.testcase"
+	// maxReportErrors bounds the row errors of an import report, the invalid rows are all counted
+	maxReportErrors = 1000
+)
+
+type TestCaseServiceImpl struct {
+	Logger                 *slog.Logger
+	DB                     *sqlx.DB
+	Validator              *validator.Validate
+	ProjectRepository      *mysql.ProjectRepository
+	TestSuiteRepository    *mysql.TestSuiteRepository
+	TestCaseRepository     *mysql.TestCaseRepository
+	TestCaseStepRepository *mysql.TestCaseStepRepository
+	// BatchSize is how many cases are written in one transaction
+	BatchSize int
+	// MaxRows bounds the rows of an imported sheet
+	MaxRows int
+}
+
+func NewTestCaseService(logger *slog.Logger, db *sqlx.DB, validator *validator.Validate,
+	projectRepository *mysql.ProjectRepository, testSuiteRepository *mysql.TestSuiteRepository,
+	testCaseRepository *mysql.TestCaseRepository, testCaseStepRepository *mysql.TestCaseStepRepository,
+	batchSize, maxRows int) *TestCaseServiceImpl {
+	return &TestCaseServiceImpl{
+		Logger:                 logger,
+		DB:                     db,
+		Validator:              validator,
+		ProjectRepository:      projectRepository,
+		TestSuiteRepository:    testSuiteRepository,
+		TestCaseRepository:     testCaseRepository,
+		TestCaseStepRepository: testCaseStepRepository,
+		BatchSize:              batchSize,
+		MaxRows:                maxRows,
+	}
+}
+
+// getProject loads a project that is not deleted
+func (t *TestCaseServiceImpl) getProject(ctx context.Context, tx *sqlx.Tx, id int) (*entity.Project, error) {
+	project, err := t.ProjectRepository.GetByID(tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		t.Logger.ErrorContext(ctx, "Get project error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return project, nil
+}
@@ -0,0 +1,221 @@
+package testcase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"slices"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+	"github.com/project-weekend/qms-engine/internal/repository/mysql"
+	"github.com/project-weekend/qms-engine/internal/spreadsheet"
+)
+
+// ImportCases imports the cases of a spreadsheet into a project, BatchSize cases per transaction.
+// Cases are matched by external ID: new ones are created, changed ones updated and the others left
+// alone, so an import that failed part way is resumed by running it again. Nothing is written while
+// a row is invalid, a dry run reports the invalid rows and what applying would do
+func (t *TestCaseServiceImpl) ImportCases(ctx context.Context, projectID int, request *model.ImportCasesRequest,
+	sheet io.Reader, trans ut.Translator) (*model.ImportCasesResponse, error) {
+	// Callers other than the transports may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		t.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+	if err := t.checkProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	rows, err := t.readSheet(ctx, request.Format, sheet)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := mapColumns(rows[0], request.Columns)
+	if err != nil {
+		return nil, err
+	}
+	report := &model.ImportCasesResponse{DryRun: request.DryRun}
+	valid, err := t.readRows(rows[1:], columns, projectID, report, trans)
+	if err != nil {
+		return nil, err
+	}
+	if report.Invalid > 0 && !request.DryRun {
+		return nil, common.NewServiceError(common.ErrCode_Unprocessable, report.Errors)
+	}
+
+	for start := 0; start < len(valid); start += t.BatchSize {
+		batch := valid[start:min(start+t.BatchSize, len(valid))]
+		if err = t.importBatch(ctx, projectID, batch, report); err != nil {
+			t.Logger.WarnContext(ctx, "ImportCases stopped, running it again resumes it", "tag", logTag,
+				"projectId", projectID, "created", report.Created, "updated", report.Updated, "row", batch[0].line)
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (t *TestCaseServiceImpl) checkProject(ctx context.Context, projectID int) error {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	_, err := t.getProject(ctx, tx, projectID)
+	return err
+}
+
+// readSheet reads the rows of sheet, the first one being the header
+func (t *TestCaseServiceImpl) readSheet(ctx context.Context, format string, sheet io.Reader) ([][]string, error) {
+	// The header comes on top of MaxRows
+	rows, err := spreadsheet.Read(sheet, format, t.MaxRows+1)
+	if errors.Is(err, spreadsheet.ErrTooManyRows) {
+		return nil, common.NewServiceError(common.ErrCode_Unprocessable, []common.ErrorDetail{{
+			ErrorCode: "TOO_MANY_ROWS",
+			Message:   "the sheet has more rows than an import takes, split it",
+			Meta:      map[string]any{"maxRows": t.MaxRows},
+		}})
+	}
+	if errors.Is(err, spreadsheet.ErrTooLarge) {
+		return nil, common.NewServiceError(common.ErrCode_PayloadTooLarge, []common.ErrorDetail{{
+			ErrorCode: "WORKBOOK_TOO_LARGE",
+			Message:   "the workbook is too large once unzipped, split it",
+			Meta:      map[string]any{"maxUnzippedBytes": spreadsheet.MaxUnzippedSize},
+		}})
+	}
+	if err != nil {
+		t.Logger.WarnContext(ctx, "ImportCases: unreadable sheet", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "INVALID_SPREADSHEET",
+			Message:   "the file is not a " + format + " file that can be read",
+			Path:      "format",
+		}})
+	}
+	if len(rows) == 0 {
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{
+			ErrorCode: "EMPTY_SPREADSHEET",
+			Message:   "the sheet has no header row",
+		}})
+	}
+	return rows, nil
+}
+
+// importBatch writes a batch in one transaction and counts it in report. A dry run only counts it
+func (t *TestCaseServiceImpl) importBatch(ctx context.Context, projectID int, batch []*importRow,
+	report *model.ImportCasesResponse) error {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  report.DryRun,
+	})
+	defer tx.Rollback()
+
+	externalIDs := make([]string, len(batch))
+	for i, row := range batch {
+		externalIDs[i] = row.testCase.ExternalID
+	}
+	list := t.TestCaseRepository.ListByExternalIDsForUpdate
+	if report.DryRun {
+		list = t.TestCaseRepository.ListByExternalIDs
+	} else if _, err := t.getProject(ctx, tx, projectID); err != nil {
+		return err
+	}
+	existing, err := list(tx, projectID, externalIDs)
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "ImportCases ListByExternalIDs error", "tag", logTag, "error", err)
+		return common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	created, updated := plan(batch, existing)
+	report.Created += len(created)
+	report.Updated += len(updated)
+	report.Unchanged += len(batch) - len(created) - len(updated)
+	if report.DryRun {
+		return nil
+	}
+
+	if err = t.writeBatch(tx, projectID, created, updated); err == nil {
+		err = tx.Commit()
+	}
+	if errors.Is(err, mysql.ErrDuplicate) {
+		// Another import created one of the cases after they were listed
+		return common.NewServiceError(common.ErrCode_Conflict, nil)
+	}
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "ImportCases write error", "tag", logTag, "error", err)
+		return common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return nil
+}
+
+// plan sorts the rows of a batch into the cases to create and the changed ones to update,
+// giving the latter the ID, suite and custom fields of the stored case
+func plan(batch []*importRow, existing []*entity.TestCase) (created, updated []*importRow) {
+	stored := make(map[string]*entity.TestCase, len(existing))
+	for _, testCase := range existing {
+		stored[testCase.ExternalID] = testCase
+	}
+	for _, row := range batch {
+		current, ok := stored[row.testCase.ExternalID]
+		switch {
+		case !ok:
+			created = append(created, row)
+		case current.Checksum != row.testCase.Checksum:
+			row.testCase.ID = current.ID
+			// Sheets have no suites or custom fields, those of imports from other tools are kept
+			row.testCase.SuiteID = current.SuiteID
+			row.testCase.CustomFields = current.CustomFields
+			updated = append(updated, row)
+		}
+	}
+	return created, updated
+}
+
+// writeBatch creates and updates the cases of a batch and replaces their steps
+func (t *TestCaseServiceImpl) writeBatch(tx *sqlx.Tx, projectID int, created, updated []*importRow) error {
+	newCases := make([]*entity.TestCase, len(created))
+	externalIDs := make([]string, len(created))
+	for i, row := range created {
+		newCases[i] = row.testCase
+		externalIDs[i] = row.testCase.ExternalID
+	}
+	if err := t.TestCaseRepository.SaveBatch(tx, newCases); err != nil {
+		return err
+	}
+	if len(created) > 0 {
+		saved, err := t.TestCaseRepository.ListByExternalIDs(tx, projectID, externalIDs)
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]int, len(saved))
+		for _, testCase := range saved {
+			ids[testCase.ExternalID] = testCase.ID
+		}
+		for _, row := range created {
+			row.testCase.ID = ids[row.testCase.ExternalID]
+		}
+	}
+	for _, row := range updated {
+		if _, err := t.TestCaseRepository.Update(tx, row.testCase); err != nil {
+			return err
+		}
+	}
+
+	var caseIDs []int
+	var steps []*entity.TestCaseStep
+	for _, row := range slices.Concat(created, updated) {
+		caseIDs = append(caseIDs, row.testCase.ID)
+		for i, step := range row.steps {
+			steps = append(steps, &entity.TestCaseStep{
+				CaseID: row.testCase.ID, Position: i + 1, Action: step.Action, Expected: step.Expected,
+			})
+		}
+	}
+	return t.TestCaseStepRepository.ReplaceByCaseIDs(tx, caseIDs, steps)
+}
@@ -0,0 +1,101 @@
+package testcase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// defaultPageSize is how many cases are listed when the request sets no limit
+const defaultPageSize = 100
+
+// ListTestSuites returns the suites of a project
+func (t *TestCaseServiceImpl) ListTestSuites(ctx context.Context, projectID int) ([]*model.TestSuiteResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	if _, err := t.getProject(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+	suites, err := t.TestSuiteRepository.ListByProjectID(tx, projectID)
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "List test suites error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	response := make([]*model.TestSuiteResponse, 0, len(suites))
+	for _, suite := range suites {
+		response = append(response, converter.TestSuiteToResponse(suite))
+	}
+	return response, nil
+}
+
+// ListTestCases returns the cases of a project after request.AfterID, the next page starts after the last one
+func (t *TestCaseServiceImpl) ListTestCases(ctx context.Context, projectID int,
+	request *model.ListTestCasesRequest) (*model.ListTestCasesResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	if _, err := t.getProject(ctx, tx, projectID); err != nil {
+		return nil, err
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	cases, err := t.TestCaseRepository.ListByProjectID(tx, projectID, request.SuiteID, request.AfterID, limit)
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "List test cases error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	response := &model.ListTestCasesResponse{Cases: make([]*model.TestCaseResponse, 0, len(cases))}
+	for _, testCase := range cases {
+		response.Cases = append(response.Cases, converter.TestCaseToResponse(testCase))
+	}
+	// A short page is the last one
+	if len(cases) == limit {
+		response.NextAfterID = cases[len(cases)-1].ID
+	}
+	return response, nil
+}
+
+// GetTestCase returns a case of a project with its steps
+func (t *TestCaseServiceImpl) GetTestCase(ctx context.Context, projectID, id int) (*model.TestCaseResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	testCase, err := t.TestCaseRepository.GetByID(tx, projectID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		t.Logger.ErrorContext(ctx, "Get test case error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	steps, err := t.TestCaseStepRepository.ListByCaseID(tx, testCase.ID)
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "List test case steps error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	response := converter.TestCaseToResponse(testCase)
+	response.Steps = make([]*model.TestStepResponse, 0, len(steps))
+	for _, step := range steps {
+		response.Steps = append(response.Steps, converter.TestStepToResponse(step))
+	}
+	return response, nil
+}
@@ -0,0 +1,202 @@
+package testcase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+	"github.com/project-weekend/qms-engine/internal/spreadsheet"
+)
+
+// importFields are the case fields a column can be mapped to, in the order of a case
+var importFields = []string{"externalId", "title", "section", "priority", "preconditions", "description",
+	"tags", "steps", "expected"}
+
+// requiredFields must be found in every sheet
+var requiredFields = []string{"externalId", "title"}
+
+var (
+	// stepNumber is the numbering spreadsheets often put before steps, e.g. "1." or "2)"
+	stepNumber = regexp.MustCompile(`^\d+[.)]\s*`)
+	// headerChars are dropped when matching a header to a field name
+	headerChars = regexp.MustCompile(`[^a-z0-9]`)
+)
+
+// importRow is a valid row of a sheet, line is its row number in the sheet from 1
+type importRow struct {
+	line     int
+	testCase *entity.TestCase
+	steps    []*model.TestStepRow
+}
+
+// mapColumns returns the column of every field found in header. Fields mapped by columns must be
+// found under the header they name, the others under a header matching their name
+func mapColumns(header []string, columns map[string]string) (map[string]int, error) {
+	found := make(map[string]int, len(importFields))
+	var details []common.ErrorDetail
+	for _, field := range importFields {
+		name, mapped := columns[field]
+		for i, cell := range header {
+			if mapped && strings.EqualFold(strings.TrimSpace(cell), name) ||
+				!mapped && headerChars.ReplaceAllString(strings.ToLower(cell), "") == strings.ToLower(field) {
+				found[field] = i
+				break
+			}
+		}
+		if _, ok := found[field]; !ok && mapped {
+			details = append(details, common.ErrorDetail{
+				ErrorCode: "COLUMN_NOT_FOUND",
+				Message:   fmt.Sprintf("the sheet has no column %q", name),
+				Path:      "columns[" + field + "]",
+			})
+		}
+	}
+	for _, field := range requiredFields {
+		if _, ok := found[field]; !ok && columns[field] == "" {
+			details = append(details, common.ErrorDetail{
+				ErrorCode: "MISSING_COLUMN",
+				Message:   fmt.Sprintf("no column holds %s, name its header in columns[%s]", field, field),
+				Path:      "columns[" + field + "]",
+			})
+		}
+	}
+	if len(details) > 0 {
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, details)
+	}
+	return found, nil
+}
+
+// readRows validates the rows below the header, adding the errors of invalid ones to report.
+// Blank rows are skipped, an external ID repeated in the sheet is an error on the later row
+func (t *TestCaseServiceImpl) readRows(rows [][]string, columns map[string]int, projectID int,
+	report *model.ImportCasesResponse, trans ut.Translator) ([]*importRow, error) {
+	valid := make([]*importRow, 0, len(rows))
+	firstLines := make(map[string]int, len(rows))
+	for i, cells := range rows {
+		line := i + 2
+		if spreadsheet.IsBlank(cells) {
+			continue
+		}
+		report.Rows++
+
+		row := rowOf(cells, columns)
+		if err := normalize.Struct(row); err != nil {
+			return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+		}
+		var details []common.ErrorDetail
+		if err := t.Validator.Struct(row); err != nil {
+			details = common.ParseValidationErrors(err, trans)
+		}
+		if first, ok := firstLines[row.ExternalID]; ok {
+			details = append(details, common.ErrorDetail{
+				ErrorCode: "DUPLICATE_EXTERNAL_ID",
+				Message:   fmt.Sprintf("the external ID is already used by row %d", first),
+				Path:      "externalId",
+				Meta:      map[string]any{"row": first},
+			})
+		} else if row.ExternalID != "" {
+			firstLines[row.ExternalID] = line
+		}
+
+		if len(details) > 0 {
+			report.Invalid++
+			addRowErrors(report, line, details)
+			continue
+		}
+		valid = append(valid, &importRow{line: line, testCase: caseOf(projectID, row), steps: row.Steps})
+	}
+	return valid, nil
+}
+
+// addRowErrors adds the errors of the row at line to report, prefixing their paths with the row
+func addRowErrors(report *model.ImportCasesResponse, line int, details []common.ErrorDetail) {
+	for _, detail := range details {
+		if len(report.Errors) == maxReportErrors {
+			return
+		}
+		detail.Path = fmt.Sprintf("rows[%d].%s", line, detail.Path)
+		report.Errors = append(report.Errors, detail)
+	}
+}
+
+// rowOf reads a case from the cells of a row. Every line of the steps column is a step, the
+// lines of the expected column are what the step in the same position should show
+func rowOf(cells []string, columns map[string]int) *model.TestCaseRow {
+	cell := func(field string) string {
+		if i, ok := columns[field]; ok && i < len(cells) {
+			return cells[i]
+		}
+		return ""
+	}
+
+	row := &model.TestCaseRow{
+		ExternalID:    cell("externalId"),
+		Title:         cell("title"),
+		Section:       cell("section"),
+		Priority:      cell("priority"),
+		Preconditions: cell("preconditions"),
+		Description:   cell("description"),
+		Tags:          splitTags(strings.Split(cell("tags"), ",")),
+	}
+	actions, expected := stepLines(cell("steps")), stepLines(cell("expected"))
+	for i := range max(len(actions), len(expected)) {
+		step := &model.TestStepRow{}
+		if i < len(actions) {
+			step.Action = actions[i]
+		}
+		if i < len(expected) {
+			step.Expected = expected[i]
+		}
+		row.Steps = append(row.Steps, step)
+	}
+	return row
+}
+
+// stepLines splits a cell into its non-empty lines without their numbering
+func stepLines(cell string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(cell, "\r\n", "\n"), "\n") {
+		if line = stepNumber.ReplaceAllString(strings.TrimSpace(line), ""); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitTags returns the tags that are not blank
+func splitTags(tags []string) []string {
+	kept := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// caseOf builds the case of a valid row, its checksum covers the content and the steps
+func caseOf(projectID int, row *model.TestCaseRow) *entity.TestCase {
+	// Marshalling a struct of strings cannot fail
+	content, _ := json.Marshal(row)
+	checksum := sha256.Sum256(content)
+	return &entity.TestCase{
+		ProjectID:     projectID,
+		ExternalID:    row.ExternalID,
+		Title:         row.Title,
+		Section:       row.Section,
+		Priority:      row.Priority,
+		Preconditions: row.Preconditions,
+		Description:   row.Description,
+		Tags:          strings.Join(row.Tags, ","),
+		Checksum:      hex.EncodeToString(checksum[:]),
+	}
+}
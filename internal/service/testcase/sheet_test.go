@@ -0,0 +1,151 @@
+package testcase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+var sheetHeader = []string{"External ID", "Summary", "Steps", "Expected Result", "Tags"}
+
+// sheetRows reads the rows of a sheet with sheetHeader the way ImportCases does, without validation
+func sheetRows(t *testing.T, projectID int, rows ...[]string) []*importRow {
+	t.Helper()
+	columns, err := mapColumns(sheetHeader, map[string]string{"title": "Summary", "expected": "Expected Result"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := make([]*importRow, 0, len(rows))
+	for i, cells := range rows {
+		row := rowOf(cells, columns)
+		if err = normalize.Struct(row); err != nil {
+			t.Fatal(err)
+		}
+		imported = append(imported, &importRow{line: i + 2, testCase: caseOf(projectID, row), steps: row.Steps})
+	}
+	return imported
+}
+
+// stored is what the first import of rows wrote, the case IDs counting from 1
+func stored(rows []*importRow, suiteID int, customFields string) []*entity.TestCase {
+	cases := make([]*entity.TestCase, len(rows))
+	for i, row := range rows {
+		testCase := *row.testCase
+		testCase.ID, testCase.SuiteID, testCase.CustomFields = i+1, &suiteID, &customFields
+		cases[i] = &testCase
+	}
+	return cases
+}
+
+func externalIDs(rows []*importRow) []string {
+	var ids []string
+	for _, row := range rows {
+		ids = append(ids, row.testCase.ExternalID)
+	}
+	return ids
+}
+
+func TestPlanReimport(t *testing.T) {
+	login := []string{"C-1", "Log in", "1. Open the page\n2) Submit", "Form shown\nSigned in", "auth, smoke"}
+	logout := []string{"C-2", "Log out", "Click log out", "Signed out", ""}
+	first := sheetRows(t, 3, login, logout)
+
+	tests := []struct {
+		name        string
+		rows        [][]string
+		wantCreated []string
+		wantUpdated []string
+	}{
+		{
+			name: "the same sheet again changes nothing",
+			rows: [][]string{login, logout},
+		},
+		{
+			name: "white space and step numbering do not count as changes",
+			rows: [][]string{
+				{" C-1 ", "Log in ", "Open the page\r\n\r\nSubmit", "1. Form shown\n2. Signed in", "auth,smoke,"},
+				logout,
+			},
+		},
+		{
+			name:        "an edited case is updated",
+			rows:        [][]string{login, {"C-2", "Log out", "Click log out", "Signed out and redirected", ""}},
+			wantUpdated: []string{"C-2"},
+		},
+		{
+			name:        "cases new to the sheet are created",
+			rows:        [][]string{login, logout, {"C-3", "Reset the password", "", "", ""}},
+			wantCreated: []string{"C-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, updated := plan(sheetRows(t, 3, tt.rows...), stored(first, 5, `{"owner":"qa"}`))
+			if got := externalIDs(created); !reflect.DeepEqual(got, tt.wantCreated) {
+				t.Errorf("created = %v, want %v", got, tt.wantCreated)
+			}
+			if got := externalIDs(updated); !reflect.DeepEqual(got, tt.wantUpdated) {
+				t.Errorf("updated = %v, want %v", got, tt.wantUpdated)
+			}
+			for _, row := range updated {
+				// Sheets carry no suites or custom fields, the stored ones are kept
+				if row.testCase.ID != 2 || *row.testCase.SuiteID != 5 || *row.testCase.CustomFields != `{"owner":"qa"}` {
+					t.Errorf("updated case = %+v, want the ID, suite and custom fields of the stored one", row.testCase)
+				}
+			}
+		})
+	}
+}
+
+func TestMapColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    []string
+		columns   map[string]string
+		want      map[string]int
+		wantCodes []string
+	}{
+		{
+			name:   "headers match field names loosely",
+			header: []string{"External-ID", "TITLE", "Pre Conditions"},
+			want:   map[string]int{"externalId": 0, "title": 1, "preconditions": 2},
+		},
+		{
+			name:    "mapped columns are found by their header",
+			header:  []string{"Key", "Summary"},
+			columns: map[string]string{"externalId": "key", "title": "Summary"},
+			want:    map[string]int{"externalId": 0, "title": 1},
+		},
+		{
+			name:      "a mapped column must exist",
+			header:    []string{"External ID", "Title"},
+			columns:   map[string]string{"section": "Folder"},
+			wantCodes: []string{"COLUMN_NOT_FOUND"},
+		},
+		{
+			name:      "the external ID and title are required",
+			header:    []string{"Summary"},
+			wantCodes: []string{"MISSING_COLUMN", "MISSING_COLUMN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := mapColumns(tt.header, tt.columns)
+			var codes []string
+			if serviceErr := common.AsServiceError(err); serviceErr != nil {
+				for _, detail := range serviceErr.Errors {
+					codes = append(codes, detail.ErrorCode)
+				}
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Fatalf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if tt.wantCodes == nil && !reflect.DeepEqual(found, tt.want) {
+				t.Errorf("columns = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package testrun
+
+import (
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+const (
+	logTag = "service.testrun"
+)
+
+// ProjectRepository is the part of mysql.ProjectRepository the runs need
+type ProjectRepository interface {
+	GetByID(tx *sqlx.Tx, id int) (*entity.Project, error)
+}
+
+// TestRunRepository is the part of mysql.TestRunRepository the runs need
+type TestRunRepository interface {
+	Save(tx *sqlx.Tx, run *entity.TestRun) (*entity.TestRun, error)
+	GetByID(tx *sqlx.Tx, id int) (*entity.TestRun, error)
+	GetByIDForUpdate(tx *sqlx.Tx, id int) (*entity.TestRun, error)
+	ListByProjectIDs(tx *sqlx.Tx, projectIDs []int, status string, limit int) ([]*entity.TestRun, error)
+	AdvanceSequence(tx *sqlx.Tx, id int, lastSequence int64, added int) error
+	Close(tx *sqlx.Tx, id int) error
+}
+
+// TestResultRepository is the part of mysql.TestResultRepository the runs need
+type TestResultRepository interface {
+	SaveBatch(tx *sqlx.Tx, results []*entity.TestResult) (int, error)
+	CountByStatus(tx *sqlx.Tx, runID int) (map[string]int, error)
+	StoredSequences(tx *sqlx.Tx, runID int, sequences []int64) (map[int64]bool, error)
+}
+
+type TestRunServiceImpl struct {
+	Logger               *slog.Logger
+	DB                   *sqlx.DB
+	ProjectRepository    ProjectRepository
+	TestRunRepository    TestRunRepository
+	TestResultRepository TestResultRepository
+	// Events tells the subscribers of a run about its changes once they are committed
+	Events service.IRunEventService
+	// BatchSize is how many uploaded results are inserted and acknowledged together
+	BatchSize int
+	// GateDefaults returns the thresholds of the quality gate that requests leave out, they change
+	// when the configuration is reloaded
+	GateDefaults func() *model.EvaluateGateRequest
+}
+
+func NewTestRunService(logger *slog.Logger, db *sqlx.DB, projectRepository ProjectRepository,
+	testRunRepository TestRunRepository, testResultRepository TestResultRepository,
+	events service.IRunEventService, batchSize int, gateDefaults func() *model.EvaluateGateRequest) *TestRunServiceImpl {
+	return &TestRunServiceImpl{
+		Logger:               logger,
+		DB:                   db,
+		ProjectRepository:    projectRepository,
+		TestRunRepository:    testRunRepository,
+		TestResultRepository: testResultRepository,
+		Events:               events,
+		BatchSize:            batchSize,
+		GateDefaults:         gateDefaults,
+	}
+}
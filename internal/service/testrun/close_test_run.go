@@ -0,0 +1,66 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// CloseTestRun stops a run from accepting results, closing a closed run changes nothing
+func (t *TestRunServiceImpl) CloseTestRun(ctx context.Context, id int) (*model.TestRunResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	run, err := t.getRun(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status == entity.TestRunStatusClosed {
+		return converter.TestRunToResponse(run), nil
+	}
+
+	if err = t.TestRunRepository.Close(tx, id); err != nil {
+		t.Logger.ErrorContext(ctx, "Close test run error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Logger.ErrorContext(ctx, "Commit test run error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	now := time.Now()
+	run.Status = entity.TestRunStatusClosed
+	run.ClosedAt = &now
+	run.UpdatedAt = now
+	response := converter.TestRunToResponse(run)
+	t.Events.Publish(ctx, id, model.RunEventStatus, response)
+	return response, nil
+}
+
+// getRun loads a run, locking it until the transaction ends when forUpdate is set
+func (t *TestRunServiceImpl) getRun(ctx context.Context, tx *sqlx.Tx, id int, forUpdate bool) (*entity.TestRun, error) {
+	get := t.TestRunRepository.GetByID
+	if forUpdate {
+		get = t.TestRunRepository.GetByIDForUpdate
+	}
+	run, err := get(tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		t.Logger.ErrorContext(ctx, "Get test run error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+	return run, nil
+}
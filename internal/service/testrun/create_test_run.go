@@ -0,0 +1,52 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+// CreateTestRun opens a run of the project that accepts results until it is closed
+func (t *TestRunServiceImpl) CreateTestRun(ctx context.Context, projectID int,
+	request *model.CreateTestRunRequest) (*model.TestRunResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	// Callers other than the HTTP handlers may not have normalized the request
+	if err := normalize.Struct(request); err != nil {
+		t.Logger.ErrorContext(ctx, "Normalize request error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+
+	if _, err := t.ProjectRepository.GetByID(tx, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		t.Logger.ErrorContext(ctx, "CreateTestRun GetByID error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	run, err := t.TestRunRepository.Save(tx, &entity.TestRun{ProjectID: projectID, Name: request.Name})
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "Save test run error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	if err = tx.Commit(); err != nil {
+		t.Logger.ErrorContext(ctx, "Commit test run error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	response := converter.TestRunToResponse(run)
+	t.Events.Publish(ctx, run.ID, model.RunEventStatus, response)
+	return response, nil
+}
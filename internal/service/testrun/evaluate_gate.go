@@ -0,0 +1,62 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+// EvaluateGate tells whether the results of a run meet the thresholds of the request, those it leaves
+// out are taken from GateDefaults. The pass rate leaves skipped results out, a run without other
+// results does not pass
+func (t *TestRunServiceImpl) EvaluateGate(ctx context.Context, runID int,
+	request *model.EvaluateGateRequest) (*model.GateResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	if _, err := t.getRun(ctx, tx, runID, false); err != nil {
+		return nil, err
+	}
+	counts, err := t.TestResultRepository.CountByStatus(tx, runID)
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "CountByStatus error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	minPassRate, maxFailed := request.MinPassRate, request.MaxFailed
+	defaults := t.GateDefaults()
+	if minPassRate == nil {
+		minPassRate = defaults.MinPassRate
+	}
+	if maxFailed == nil {
+		maxFailed = defaults.MaxFailed
+	}
+
+	response := &model.GateResponse{RunID: runID, Counts: counts, Reasons: []string{}}
+	passed := counts[entity.TestResultStatusPassed]
+	failed := counts[entity.TestResultStatusFailed] + counts[entity.TestResultStatusBlocked]
+	if executed := passed + failed; executed == 0 {
+		response.Reasons = append(response.Reasons, "the run has no passed, failed or blocked results")
+	} else {
+		// Rounded to two decimals so that the rate reads the same in every client
+		response.PassRate = math.Round(float64(passed)*10000/float64(executed)) / 100
+		if minPassRate != nil && response.PassRate < *minPassRate {
+			response.Reasons = append(response.Reasons,
+				fmt.Sprintf("the pass rate %.2f%% is below %.2f%%", response.PassRate, *minPassRate))
+		}
+	}
+	if maxFailed != nil && failed > *maxFailed {
+		response.Reasons = append(response.Reasons,
+			fmt.Sprintf("%d results failed or are blocked, at most %d may", failed, *maxFailed))
+	}
+	response.Passed = len(response.Reasons) == 0
+	return response, nil
+}
@@ -0,0 +1,25 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// GetTestRun returns a run, its last sequence tells an uploader where to resume
+func (t *TestRunServiceImpl) GetTestRun(ctx context.Context, id int) (*model.TestRunResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	run, err := t.getRun(ctx, tx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.TestRunToResponse(run), nil
+}
@@ -0,0 +1,48 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/model/converter"
+)
+
+// defaultListLimit is how many runs are listed when the request sets no limit
+const defaultListLimit = 20
+
+// ListTestRuns returns the newest runs of a project, those with the status of the request when it is set
+func (t *TestRunServiceImpl) ListTestRuns(ctx context.Context, projectID int,
+	request *model.ListTestRunsRequest) (*model.ListTestRunsResponse, error) {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	if _, err := t.ProjectRepository.GetByID(tx, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.NewServiceError(common.ErrCode_ResourceNotFound, nil)
+		}
+		t.Logger.ErrorContext(ctx, "ListTestRuns GetByID error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	runs, err := t.TestRunRepository.ListByProjectIDs(tx, []int{projectID}, request.Status, limit)
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "ListByProjectIDs error", "tag", logTag, "error", err)
+		return nil, common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	response := &model.ListTestRunsResponse{Runs: make([]*model.TestRunResponse, 0, len(runs))}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, converter.TestRunToResponse(run))
+	}
+	return response, nil
+}
@@ -0,0 +1,183 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+// UploadResults stores the results of stream in an open run, BatchSize results per transaction.
+// Every committed batch is acknowledged, so an uploader that gets cut off resumes after the
+// acknowledged sequence. Sequences must increase through the upload, results whose sequence is
+// already stored in the run are dropped as duplicates
+func (t *TestRunServiceImpl) UploadResults(ctx context.Context, runID int,
+	stream service.ResultStream) (*model.UploadResultsResponse, error) {
+	progress := &model.UploadResultsResponse{}
+	if err := t.checkOpen(ctx, runID, progress); err != nil {
+		return progress, err
+	}
+
+	batch := make([]*model.TestResultRequest, 0, t.BatchSize)
+	var previous int64
+	for {
+		result, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil && result.Sequence <= previous {
+			err = sequenceError(result.Sequence, previous)
+		}
+		if err != nil {
+			// What was read before the failure is kept, also when the uploader went away
+			if flushErr := t.flush(context.WithoutCancel(ctx), runID, batch, progress); flushErr != nil {
+				t.Logger.WarnContext(ctx, "UploadResults: failed to keep the last batch", "tag", logTag, "error", flushErr)
+			}
+			return progress, err
+		}
+
+		previous = result.Sequence
+		progress.Received++
+		batch = append(batch, result)
+		if len(batch) < t.BatchSize {
+			continue
+		}
+		if err = t.flush(ctx, runID, batch, progress); err != nil {
+			return progress, err
+		}
+		batch = batch[:0]
+		if err = stream.Ack(progress); err != nil {
+			return progress, err
+		}
+	}
+
+	if err := t.flush(ctx, runID, batch, progress); err != nil {
+		return progress, err
+	}
+	progress.Done = true
+	return progress, nil
+}
+
+// checkOpen fails the upload up front when the run is missing or closed
+func (t *TestRunServiceImpl) checkOpen(ctx context.Context, runID int, progress *model.UploadResultsResponse) error {
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  true,
+	})
+	defer tx.Rollback()
+
+	run, err := t.getRun(ctx, tx, runID, false)
+	if err != nil {
+		return err
+	}
+	progress.AckedSequence = run.LastSequence
+	if run.Status != entity.TestRunStatusOpen {
+		return runClosedError()
+	}
+	return nil
+}
+
+// flush commits a batch in one transaction. The run stays locked meanwhile, so concurrent
+// uploads to it cannot both store a sequence. uk_run_sequence drops the sequences already stored,
+// they are looked up beforehand only to leave them out of the event
+func (t *TestRunServiceImpl) flush(ctx context.Context, runID int, batch []*model.TestResultRequest,
+	progress *model.UploadResultsResponse) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	tx := t.DB.MustBeginTx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	defer tx.Rollback()
+
+	run, err := t.getRun(ctx, tx, runID, true)
+	if err != nil {
+		return err
+	}
+	if run.Status != entity.TestRunStatusOpen {
+		return runClosedError()
+	}
+
+	results := make([]*entity.TestResult, 0, len(batch))
+	sequences := make([]int64, 0, len(batch))
+	lastSequence := run.LastSequence
+	for _, result := range batch {
+		sequences = append(sequences, result.Sequence)
+		results = append(results, &entity.TestResult{
+			RunID:      runID,
+			Sequence:   result.Sequence,
+			CaseKey:    result.CaseKey,
+			Status:     result.Status,
+			DurationMs: result.DurationMs,
+			Message:    result.Message,
+		})
+		lastSequence = max(lastSequence, result.Sequence)
+	}
+
+	duplicates, err := t.TestResultRepository.StoredSequences(tx, runID, sequences)
+	stored := 0
+	if err == nil {
+		stored, err = t.TestResultRepository.SaveBatch(tx, results)
+	}
+	if err == nil && stored > 0 {
+		err = t.TestRunRepository.AdvanceSequence(tx, runID, lastSequence, stored)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		t.Logger.ErrorContext(ctx, "Store test results error", "tag", logTag, "error", err)
+		return common.NewServiceError(common.ErrCode_InternalServerError, nil)
+	}
+
+	progress.Stored += stored
+	progress.Duplicates += len(batch) - stored
+	progress.AckedSequence = lastSequence
+	if stored > 0 {
+		t.publishRecorded(ctx, run, results, duplicates, lastSequence, stored)
+	}
+	return nil
+}
+
+// publishRecorded tells the subscribers of run about a committed batch of results, counting
+// only the results that were inserted
+func (t *TestRunServiceImpl) publishRecorded(ctx context.Context, run *entity.TestRun, results []*entity.TestResult,
+	duplicates map[int64]bool, lastSequence int64, stored int) {
+	recorded := make(map[string]int)
+	for _, result := range results {
+		if !duplicates[result.Sequence] {
+			recorded[result.Status]++
+		}
+	}
+	t.Events.Publish(ctx, run.ID, model.RunEventResultRecorded, &model.ResultRecordedEvent{
+		RunID:        run.ID,
+		LastSequence: lastSequence,
+		ResultCount:  run.ResultCount + stored,
+		Recorded:     recorded,
+	})
+}
+
+// sequenceError refuses a result whose sequence does not follow the one before it in the upload
+func sequenceError(sequence, previous int64) error {
+	return common.NewServiceError(common.ErrCode_Unprocessable, []common.ErrorDetail{{
+		ErrorCode: "SEQUENCE_NOT_INCREASING",
+		Message: fmt.Sprintf("sequence %d does not follow %d, sequences must increase through the upload",
+			sequence, previous),
+		Path: "sequence",
+		Meta: map[string]any{"sequence": sequence, "previous": previous},
+	}})
+}
+
+func runClosedError() error {
+	return common.NewServiceError(common.ErrCode_Unprocessable, []common.ErrorDetail{{
+		ErrorCode: "RUN_CLOSED",
+		Message:   "the test run is closed and accepts no more results",
+	}})
+}
@@ -0,0 +1,245 @@
+package testrun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/entity"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+)
+
+// txConnector opens connections that only begin, commit and roll back transactions, the
+// repositories under test never send them a query
+type txConnector struct{}
+
+func (c txConnector) Connect(context.Context) (driver.Conn, error) { return txConn{}, nil }
+func (c txConnector) Driver() driver.Driver                        { return c }
+func (c txConnector) Open(string) (driver.Conn, error)             { return txConn{}, nil }
+
+type txConn struct{}
+
+func (txConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("no queries in tests") }
+func (txConn) Close() error                        { return nil }
+func (txConn) Begin() (driver.Tx, error)           { return txConn{}, nil }
+func (txConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return txConn{}, nil
+}
+func (txConn) Commit() error   { return nil }
+func (txConn) Rollback() error { return nil }
+
+// memoryRun keeps one run and its results, storing a sequence once as uk_run_sequence does.
+// batches records the sequences of every SaveBatch
+type memoryRun struct {
+	TestRunRepository
+	TestResultRepository
+	run     entity.TestRun
+	results map[int64]*entity.TestResult
+	batches [][]int64
+}
+
+func (m *memoryRun) GetByID(_ *sqlx.Tx, id int) (*entity.TestRun, error) {
+	if id != m.run.ID {
+		return nil, sql.ErrNoRows
+	}
+	run := m.run
+	return &run, nil
+}
+
+func (m *memoryRun) GetByIDForUpdate(tx *sqlx.Tx, id int) (*entity.TestRun, error) {
+	return m.GetByID(tx, id)
+}
+
+func (m *memoryRun) AdvanceSequence(_ *sqlx.Tx, _ int, lastSequence int64, added int) error {
+	m.run.LastSequence = max(m.run.LastSequence, lastSequence)
+	m.run.ResultCount += added
+	return nil
+}
+
+func (m *memoryRun) SaveBatch(_ *sqlx.Tx, results []*entity.TestResult) (int, error) {
+	var sequences []int64
+	inserted := 0
+	for _, result := range results {
+		sequences = append(sequences, result.Sequence)
+		if _, ok := m.results[result.Sequence]; !ok {
+			m.results[result.Sequence] = result
+			inserted++
+		}
+	}
+	m.batches = append(m.batches, sequences)
+	return inserted, nil
+}
+
+func (m *memoryRun) StoredSequences(_ *sqlx.Tx, _ int, sequences []int64) (map[int64]bool, error) {
+	stored := make(map[int64]bool)
+	for _, sequence := range sequences {
+		if _, ok := m.results[sequence]; ok {
+			stored[sequence] = true
+		}
+	}
+	return stored, nil
+}
+
+// recordedEvents counts the results that the published events say were recorded
+type recordedEvents struct {
+	service.IRunEventService
+	recorded int
+}
+
+func (e *recordedEvents) Publish(_ context.Context, _ int, _ string, data any) {
+	if event, ok := data.(*model.ResultRecordedEvent); ok {
+		for _, count := range event.Recorded {
+			e.recorded += count
+		}
+	}
+}
+
+// sliceStream uploads the sequences and then fails with err, or ends when it is nil. acks records
+// the acknowledged sequences
+type sliceStream struct {
+	sequences []int64
+	err       error
+	acks      []int64
+}
+
+func (s *sliceStream) Next() (*model.TestResultRequest, error) {
+	if len(s.sequences) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	sequence := s.sequences[0]
+	s.sequences = s.sequences[1:]
+	return &model.TestResultRequest{Sequence: sequence, CaseKey: "C-1", Status: entity.TestResultStatusPassed}, nil
+}
+
+func (s *sliceStream) Ack(progress *model.UploadResultsResponse) error {
+	s.acks = append(s.acks, progress.AckedSequence)
+	return nil
+}
+
+func TestUploadResults(t *testing.T) {
+	invalid := common.NewServiceError(common.ErrCode_BadRequest, []common.ErrorDetail{{ErrorCode: "VALIDATION_ERROR"}})
+	tests := []struct {
+		name        string
+		stored      []int64
+		closed      bool
+		batchSize   int
+		upload      []int64
+		streamErr   error
+		wantCode    string
+		wantAcks    []int64
+		wantBatches [][]int64
+		want        model.UploadResultsResponse
+	}{
+		{
+			name:        "results are committed and acknowledged a batch at a time",
+			batchSize:   2,
+			upload:      []int64{1, 2, 3, 5, 8},
+			wantAcks:    []int64{2, 5},
+			wantBatches: [][]int64{{1, 2}, {3, 5}, {8}},
+			want:        model.UploadResultsResponse{AckedSequence: 8, Received: 5, Stored: 5, Done: true},
+		},
+		{
+			name:        "sequences must increase, what was read before is kept",
+			batchSize:   10,
+			upload:      []int64{1, 2, 2},
+			wantCode:    "SEQUENCE_NOT_INCREASING",
+			wantBatches: [][]int64{{1, 2}},
+			want:        model.UploadResultsResponse{AckedSequence: 2, Received: 2, Stored: 2},
+		},
+		{
+			name:        "a failed stream keeps the results read before",
+			batchSize:   2,
+			upload:      []int64{1, 2, 3},
+			streamErr:   invalid,
+			wantCode:    "VALIDATION_ERROR",
+			wantAcks:    []int64{2},
+			wantBatches: [][]int64{{1, 2}, {3}},
+			want:        model.UploadResultsResponse{AckedSequence: 3, Received: 3, Stored: 3},
+		},
+		{
+			name:        "an upload resumes after the last sequence of the run",
+			stored:      []int64{1, 2, 3},
+			batchSize:   10,
+			upload:      []int64{4, 5},
+			wantBatches: [][]int64{{4, 5}},
+			want:        model.UploadResultsResponse{AckedSequence: 5, Received: 2, Stored: 2, Done: true},
+		},
+		{
+			name:      "an empty upload acknowledges the last sequence of the run",
+			stored:    []int64{1, 2, 3},
+			batchSize: 10,
+			want:      model.UploadResultsResponse{AckedSequence: 3, Done: true},
+		},
+		{
+			name:        "stored sequences sent again are dropped as duplicates",
+			stored:      []int64{1, 2, 3},
+			batchSize:   10,
+			upload:      []int64{2, 3, 4, 5},
+			wantBatches: [][]int64{{2, 3, 4, 5}},
+			want:        model.UploadResultsResponse{AckedSequence: 5, Received: 4, Stored: 2, Duplicates: 2, Done: true},
+		},
+		{
+			name:      "closed runs take no results",
+			stored:    []int64{1},
+			closed:    true,
+			batchSize: 10,
+			upload:    []int64{2},
+			wantCode:  "RUN_CLOSED",
+			want:      model.UploadResultsResponse{AckedSequence: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &memoryRun{
+				run:     entity.TestRun{ID: 4, Status: entity.TestRunStatusOpen, ResultCount: len(tt.stored)},
+				results: map[int64]*entity.TestResult{},
+			}
+			for _, sequence := range tt.stored {
+				runs.results[sequence] = &entity.TestResult{RunID: 4, Sequence: sequence}
+				runs.run.LastSequence = sequence
+			}
+			if tt.closed {
+				runs.run.Status = entity.TestRunStatusClosed
+			}
+			events := &recordedEvents{}
+			runService := NewTestRunService(slog.New(slog.NewTextHandler(io.Discard, nil)),
+				sqlx.NewDb(sql.OpenDB(txConnector{}), "mysql"), nil, runs, runs, events, tt.batchSize, nil)
+			stream := &sliceStream{sequences: tt.upload, err: tt.streamErr}
+
+			progress, err := runService.UploadResults(context.Background(), 4, stream)
+			var code string
+			if serviceErr := common.AsServiceError(err); serviceErr != nil && len(serviceErr.Errors) > 0 {
+				code = serviceErr.Errors[0].ErrorCode
+			}
+			if code != tt.wantCode || (tt.wantCode == "" && err != nil) {
+				t.Fatalf("err = %v, want %q", err, tt.wantCode)
+			}
+			if *progress != tt.want {
+				t.Errorf("progress = %+v, want %+v", *progress, tt.want)
+			}
+			if !reflect.DeepEqual(stream.acks, tt.wantAcks) {
+				t.Errorf("acks = %v, want %v", stream.acks, tt.wantAcks)
+			}
+			if !reflect.DeepEqual(runs.batches, tt.wantBatches) {
+				t.Errorf("batches = %v, want %v", runs.batches, tt.wantBatches)
+			}
+			// Duplicates are left out of the events and the run
+			if events.recorded != tt.want.Stored || runs.run.ResultCount != len(tt.stored)+tt.want.Stored {
+				t.Errorf("recorded = %d, result count = %d, want %d stored", events.recorded,
+					runs.run.ResultCount, tt.want.Stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/project-weekend/qms-engine/internal/model"
+)
+
+type ITestRunService interface {
+	CreateTestRun(ctx context.Context, projectID int, request *model.CreateTestRunRequest) (*model.TestRunResponse, error)
+	GetTestRun(ctx context.Context, id int) (*model.TestRunResponse, error)
+	ListTestRuns(ctx context.Context, projectID int, request *model.ListTestRunsRequest) (*model.ListTestRunsResponse, error)
+	EvaluateGate(ctx context.Context, runID int, request *model.EvaluateGateRequest) (*model.GateResponse, error)
+	CloseTestRun(ctx context.Context, id int) (*model.TestRunResponse, error)
+	UploadResults(ctx context.Context, runID int, stream ResultStream) (*model.UploadResultsResponse, error)
+}
+
+// ResultStream is an upload of results as read by a transport, e.g. an NDJSON body or a gRPC stream
+type ResultStream interface {
+	// Next returns the next normalized and validated result, io.EOF once the upload is complete
+	Next() (*model.TestResultRequest, error)
+	// Ack reports the progress after a batch of results was committed
+	Ack(progress *model.UploadResultsResponse) error
+}
@@ -0,0 +1,138 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	// MaxUnzippedSize bounds the parts of a workbook once unzipped. A few MB of zipped XML can
+	// unzip to gigabytes, they are refused before they are held in memory
+	MaxUnzippedSize = 128 << 20
+)
+
+var (
+	// ErrTooManyRows is returned when a sheet holds more rows than the caller takes
+	ErrTooManyRows = errors.New("spreadsheet: too many rows")
+	// ErrTooLarge is returned when a workbook unzips to more than MaxUnzippedSize
+	ErrTooLarge = errors.New("spreadsheet: workbook too large once unzipped")
+)
+
+// Read returns the rows of a CSV file or of the first sheet of an XLSX workbook, at most maxRows
+// of them. Cells are text as displayed, rows may be shorter than others when they end with empty cells
+func Read(r io.Reader, format string, maxRows int) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, maxRows)
+	case FormatXLSX:
+		return readXLSX(r, maxRows)
+	}
+	return nil, fmt.Errorf("spreadsheet: unknown format %q", format)
+}
+
+func readCSV(r io.Reader, maxRows int) ([][]string, error) {
+	reader := csv.NewReader(skipBOM(r))
+	// Exports often end rows early or quote loosely
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("spreadsheet: %w", err)
+		}
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		rows = append(rows, row)
+	}
+}
+
+// skipBOM drops the byte order mark Excel writes at the start of UTF-8 CSV files
+func skipBOM(r io.Reader) io.Reader {
+	bom := []byte("\xef\xbb\xbf")
+	head := make([]byte, len(bom))
+	n, _ := io.ReadFull(r, head)
+	if bytes.Equal(head[:n], bom) {
+		return r
+	}
+	return io.MultiReader(bytes.NewReader(head[:n]), r)
+}
+
+func readXLSX(r io.Reader, maxRows int) ([][]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: %w", err)
+	}
+	if err = checkUnzippedSize(content); err != nil {
+		return nil, err
+	}
+	workbook, err := excelize.OpenReader(bytes.NewReader(content), excelize.Options{UnzipSizeLimit: MaxUnzippedSize})
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: %w", err)
+	}
+	defer workbook.Close()
+
+	iterator, err := workbook.Rows(workbook.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: %w", err)
+	}
+	defer iterator.Close()
+
+	var rows [][]string
+	for iterator.Next() {
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		row, err := iterator.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("spreadsheet: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if err = iterator.Error(); err != nil {
+		return nil, fmt.Errorf("spreadsheet: %w", err)
+	}
+	return rows, nil
+}
+
+// checkUnzippedSize adds up the sizes the zip directory of a workbook declares for its parts.
+// Reading a part past its declared size fails, so they cannot understate it
+func checkUnzippedSize(content []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("spreadsheet: %w", err)
+	}
+	var size uint64
+	for _, file := range archive.File {
+		size += file.UncompressedSize64
+		if size > MaxUnzippedSize {
+			return ErrTooLarge
+		}
+	}
+	return nil
+}
+
+// IsBlank reports whether every cell of row is empty or white space
+func IsBlank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
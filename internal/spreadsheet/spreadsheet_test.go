@@ -0,0 +1,152 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// workbook writes rows to the first sheet of a new XLSX workbook
+func workbook(t *testing.T, rows [][]any) []byte {
+	t.Helper()
+	file := excelize.NewFile()
+	defer file.Close()
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = file.SetSheetRow(file.GetSheetName(0), cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// zipBomb is a zip whose one part declares more than MaxUnzippedSize without holding it
+func zipBomb(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	part, err := archive.CreateRaw(&zip.FileHeader{
+		Name:               "xl/worksheets/sheet1.xml",
+		Method:             zip.Store,
+		CompressedSize64:   1,
+		UncompressedSize64: MaxUnzippedSize + 1,
+	})
+	if err == nil {
+		_, err = part.Write([]byte("<"))
+	}
+	if err == nil {
+		err = archive.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content []byte
+		maxRows int
+		want    [][]string
+		wantErr error
+	}{
+		{
+			name:    "CSV rows may be ragged",
+			format:  FormatCSV,
+			content: []byte("External ID,Title,Tags\nC-1,Log in\nC-2,Log out,\"auth, smoke\"\n"),
+			maxRows: 10,
+			want:    [][]string{{"External ID", "Title", "Tags"}, {"C-1", "Log in"}, {"C-2", "Log out", "auth, smoke"}},
+		},
+		{
+			name:    "the byte order mark of Excel CSV files is dropped",
+			format:  FormatCSV,
+			content: []byte("\xef\xbb\xbfExternal ID,Title\nC-1,Log in\n"),
+			maxRows: 10,
+			want:    [][]string{{"External ID", "Title"}, {"C-1", "Log in"}},
+		},
+		{
+			name:    "loose quotes are kept",
+			format:  FormatCSV,
+			content: []byte("C-1,Click \"Save\" twice\n"),
+			maxRows: 10,
+			want:    [][]string{{"C-1", `Click "Save" twice`}},
+		},
+		{
+			name:    "CSV files with more rows than taken are refused",
+			format:  FormatCSV,
+			content: []byte("a\nb\nc\n"),
+			maxRows: 2,
+			wantErr: ErrTooManyRows,
+		},
+		{
+			name:   "XLSX cells are read as displayed",
+			format: FormatXLSX,
+			content: workbook(t, [][]any{
+				{"External ID", "Title", "Priority"},
+				{"C-1", "Log in", 1},
+				{"C-2", "Log out"},
+			}),
+			maxRows: 10,
+			want:    [][]string{{"External ID", "Title", "Priority"}, {"C-1", "Log in", "1"}, {"C-2", "Log out"}},
+		},
+		{
+			name:    "XLSX sheets with more rows than taken are refused",
+			format:  FormatXLSX,
+			content: workbook(t, [][]any{{"a"}, {"b"}, {"c"}}),
+			maxRows: 2,
+			wantErr: ErrTooManyRows,
+		},
+		{
+			name:    "workbooks unzipping past the limit are refused before they are read",
+			format:  FormatXLSX,
+			content: zipBomb(t),
+			maxRows: 10,
+			wantErr: ErrTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(bytes.NewReader(tt.content), tt.format, tt.maxRows)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %q, want %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRefusesUnreadableFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+	}{
+		{name: "text is not a workbook", format: FormatXLSX, content: "External ID,Title\n"},
+		{name: "unknown formats", format: "ods", content: "External ID,Title\n"},
+		{name: "an empty body is not a workbook", format: FormatXLSX, content: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.content), tt.format, 10)
+			if err == nil || errors.Is(err, ErrTooManyRows) || errors.Is(err, ErrTooLarge) {
+				t.Errorf("err = %v, want an unreadable file", err)
+			}
+		})
+	}
+}
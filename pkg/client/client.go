@@ -0,0 +1,211 @@
+// Package client is the Go client of the qms-engine API. It authenticates with an API key,
+// retries reads that hit server errors or rate limits with backoff, sends an Idempotency-Key
+// with every POST and PATCH so that callers can repeat them safely, and returns API errors as *Error.
+//
+// It covers the routes of api/openapi.json, methods are added as routes are.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyHeader         = "X-API-Key"
+	idempotencyKeyHeader = "Idempotency-Key"
+	userAgent            = "qms-engine-go-client"
+
+	defaultMaxRetries = 3
+	defaultMinBackoff = 200 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Config contains the client settings, zero values take the defaults
+type Config struct {
+	// BaseURL is the engine address, e.g. https://qms.example.com
+	BaseURL string
+	// APIKey is sent in X-API-Key
+	APIKey string
+	// HTTPClient sends the requests, http.DefaultClient by default
+	HTTPClient *http.Client
+	// MaxRetries bounds the retries of a GET, 3 by default. A negative value disables retries.
+	// Writes are not retried, a failed write is reported to the caller
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the exponential delay between retries. A Retry-After
+	// header sent by the engine is honored up to MaxBackoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client calls the engine API, it is safe for concurrent use
+type Client struct {
+	cfg Config
+}
+
+// New creates a client of the engine at cfg.BaseURL
+func New(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("client: BaseURL is required")
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(defaultMaxBackoff, cfg.MinBackoff)
+	}
+	return &Client{cfg: cfg}, nil
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey makes the POST or PATCH sent with ctx use key, so that a call repeated by
+// the caller, e.g. after a crash, is recognized by the engine. Without it every call gets a random key
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// request is an API call, body is sent as JSON and the response is decoded into out when set
+type request struct {
+	method string
+	path   string
+	header http.Header
+	body   any
+	out    any
+}
+
+// do sends req. A GET is retried on transport errors, 5xx and 429 until it succeeds, the retries
+// run out or ctx is done, other methods are sent once
+func (c *Client) do(ctx context.Context, req request) error {
+	body, err := c.encode(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		if req.method != http.MethodGet || !retryable(resp, err) || attempt >= c.cfg.MaxRetries {
+			if err != nil {
+				return err
+			}
+			return c.decode(resp, req.out)
+		}
+
+		delay := c.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// encode marshals the body once so that retries send the same bytes, and sets the
+// idempotency key of POST and PATCH
+func (c *Client) encode(ctx context.Context, req *request) ([]byte, error) {
+	if req.header == nil {
+		req.header = http.Header{}
+	}
+	if req.method == http.MethodPost || req.method == http.MethodPatch {
+		key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+		if key == "" {
+			key = randomKey()
+		}
+		req.header.Set(idempotencyKeyHeader, key)
+	}
+	if req.body == nil {
+		return nil, nil
+	}
+	body, err := json.Marshal(req.body)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to encode request: %w", err)
+	}
+	req.header.Set("Content-Type", "application/json")
+	return body, nil
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, c.cfg.BaseURL+req.path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to build request: %w", err)
+	}
+	httpReq.Header = req.header.Clone()
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	if c.cfg.APIKey != "" {
+		httpReq.Header.Set(apiKeyHeader, c.cfg.APIKey)
+	}
+	return c.cfg.HTTPClient.Do(httpReq)
+}
+
+// decode closes the response, decoding a success into out and an error into *Error
+func (c *Client) decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("client: failed to read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp, data)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("client: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// retryable reports whether the outcome of an attempt is worth retrying. Cancellation is not
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before the next attempt: the Retry-After of the engine when it
+// sent one, otherwise an exponential delay with full jitter
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.cfg.MaxBackoff)
+		}
+	}
+	ceiling := float64(c.cfg.MinBackoff) * math.Pow(2, float64(attempt))
+	ceiling = min(ceiling, float64(c.cfg.MaxBackoff))
+	return c.cfg.MinBackoff + time.Duration(mathrand.Float64()*(ceiling-float64(c.cfg.MinBackoff)))
+}
+
+func randomKey() string {
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+	return hex.EncodeToString(key)
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-weekend/qms-engine/handlers"
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/config"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/service"
+	serverconfig "github.com/project-weekend/qms-engine/server/config"
+)
+
+const testAPIKey = "secret"
+
+// fakeProjects answers with the errors queued in errs before succeeding, calls counts the calls
+// and deleted records the expected versions of the deletes
+type fakeProjects struct {
+	service.IProjectService
+	errs    []error
+	calls   atomic.Int32
+	deleted []int
+}
+
+func (f *fakeProjects) next() error {
+	call := int(f.calls.Add(1)) - 1
+	if call < len(f.errs) {
+		return f.errs[call]
+	}
+	return nil
+}
+
+func (f *fakeProjects) CreateProject(context.Context, *model.CreateProjectRequest) (*model.CreateProjectResponse, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &model.CreateProjectResponse{ID: 7, Version: 1}, nil
+}
+
+func (f *fakeProjects) GetProject(_ context.Context, id int) (*model.ProjectResponse, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &model.ProjectResponse{ID: id, Name: "checkout", Version: 3}, nil
+}
+
+func (f *fakeProjects) DeleteProject(_ context.Context, _ int, expectedVersion int) error {
+	f.deleted = append(f.deleted, expectedVersion)
+	return f.next()
+}
+
+// fakeRuns records the requests of the listing and gate routes
+type fakeRuns struct {
+	service.ITestRunService
+	list *model.ListTestRunsRequest
+	gate *model.EvaluateGateRequest
+}
+
+func (f *fakeRuns) ListTestRuns(_ context.Context, projectID int,
+	request *model.ListTestRunsRequest) (*model.ListTestRunsResponse, error) {
+	f.list = request
+	return &model.ListTestRunsResponse{Runs: []*model.TestRunResponse{
+		{ID: 2, ProjectID: projectID, Status: request.Status},
+		{ID: 1, ProjectID: projectID, Status: request.Status},
+	}}, nil
+}
+
+func (f *fakeRuns) EvaluateGate(_ context.Context, runID int, request *model.EvaluateGateRequest) (*model.GateResponse, error) {
+	f.gate = request
+	return &model.GateResponse{RunID: runID, PassRate: 80, Counts: map[string]int{"passed": 8, "failed": 2},
+		Reasons: []string{"the pass rate 80.00% is below 90.00%"}}, nil
+}
+
+// newTestServer serves the real handlers and middleware in front of the fake services
+func newTestServer(t *testing.T, projects *fakeProjects, runs *fakeRuns) *Client {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	live := config.NewLiveConfig(&serverconfig.Config{
+		Auth: serverconfig.AuthConfig{APIKeys: []string{"ci:" + testAPIKey}},
+	})
+	engine := gin.New()
+	engine.Use(config.ErrorMiddleware(), config.AuthMiddleware(live))
+	pass := func(*gin.Context) {}
+	routeConfig := handlers.RouteConfig{
+		AppEngine:   engine,
+		Limits:      func(string) gin.HandlerFunc { return pass },
+		Contract:    pass,
+		Idempotency: pass,
+		QMSEngineService: handlers.NewQMSEngineService(logger, config.NewValidator(), projects, runs,
+			nil, nil, nil),
+	}
+	routeConfig.RegisterRoutes()
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	client, err := New(Config{
+		BaseURL:    server.URL,
+		APIKey:     testAPIKey,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRetries(t *testing.T) {
+	unavailable := common.NewServiceError(common.ErrCode_ServiceUnavailable, nil)
+	tests := []struct {
+		name      string
+		errs      []error
+		call      func(context.Context, *Client) error
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name: "reads are retried until they succeed",
+			errs: []error{unavailable, unavailable},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetProject(ctx, 1)
+				return err
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "reads give up once the retries run out",
+			errs: []error{unavailable, unavailable, unavailable, unavailable, unavailable},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetProject(ctx, 1)
+				return err
+			},
+			wantErr:   ErrServiceUnavailable,
+			wantCalls: 4,
+		},
+		{
+			name: "errors of the caller are not retried",
+			errs: []error{common.NewServiceError(common.ErrCode_ResourceNotFound, nil)},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetProject(ctx, 1)
+				return err
+			},
+			wantErr:   ErrNotFound,
+			wantCalls: 1,
+		},
+		{
+			name: "creates are sent once",
+			errs: []error{unavailable},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.CreateProject(ctx, &CreateProjectRequest{Name: "checkout"})
+				return err
+			},
+			wantErr:   ErrServiceUnavailable,
+			wantCalls: 1,
+		},
+		{
+			name: "deletes are sent once",
+			errs: []error{common.NewServiceError(common.ErrCode_InternalServerError, nil)},
+			call: func(ctx context.Context, c *Client) error {
+				return c.DeleteProject(ctx, 1, 3)
+			},
+			wantErr:   ErrInternal,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects := &fakeProjects{errs: tt.errs}
+			client := newTestServer(t, projects, &fakeRuns{})
+
+			err := tt.call(context.Background(), client)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls := projects.calls.Load(); calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	projects := &fakeProjects{errs: []error{common.NewServiceError(common.ErrCode_PreconditionFailed,
+		[]common.ErrorDetail{{ErrorCode: "VERSION_MISMATCH", Meta: map[string]any{"currentVersion": 4}}})}}
+	client := newTestServer(t, projects, &fakeRuns{})
+
+	err := client.DeleteProject(context.Background(), 1, 3)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("err = %v, want a PRECONDITION_FAILED *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusPreconditionFailed || apiErr.CurrentVersion() != 4 {
+		t.Errorf("status = %d, current version = %d", apiErr.StatusCode, apiErr.CurrentVersion())
+	}
+
+	client.cfg.APIKey = "wrong"
+	if _, err = client.GetProject(context.Background(), 1); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v, want UNAUTHORIZED", err)
+	}
+}
+
+func TestCreateProject(t *testing.T) {
+	client := newTestServer(t, &fakeProjects{}, &fakeRuns{})
+	project, err := client.CreateProject(context.Background(), &CreateProjectRequest{Name: "checkout"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.ID != 7 || project.Version != 1 {
+		t.Errorf("project = %+v", project)
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	projects := &fakeProjects{}
+	client := newTestServer(t, projects, &fakeRuns{})
+	for _, version := range []int{3, AnyVersion} {
+		if err := client.DeleteProject(context.Background(), 1, version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(projects.deleted) != 2 || projects.deleted[0] != 3 || projects.deleted[1] != service.AnyVersion {
+		t.Errorf("expected versions = %v, want [3 %d]", projects.deleted, service.AnyVersion)
+	}
+}
+
+func TestListTestRuns(t *testing.T) {
+	runs := &fakeRuns{}
+	client := newTestServer(t, &fakeProjects{}, runs)
+
+	listed, err := client.ListTestRuns(context.Background(), 5, ListTestRunsOptions{Status: "closed", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[0].ID != 2 || listed[0].ProjectID != 5 {
+		t.Errorf("runs = %+v", listed)
+	}
+	if runs.list.Status != "closed" || runs.list.Limit != 2 {
+		t.Errorf("request = %+v", runs.list)
+	}
+
+	_, err = client.ListTestRuns(context.Background(), 5, ListTestRunsOptions{Status: "pending"})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("err = %v, want BAD_REQUEST", err)
+	}
+}
+
+func TestEvaluateGate(t *testing.T) {
+	runs := &fakeRuns{}
+	client := newTestServer(t, &fakeProjects{}, runs)
+
+	minPassRate, maxFailed := 90.0, 0
+	gate, err := client.EvaluateGate(context.Background(), 9, GateOptions{MinPassRate: &minPassRate, MaxFailed: &maxFailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gate.RunID != 9 || gate.Passed || gate.PassRate != 80 || gate.Counts["failed"] != 2 || len(gate.Reasons) != 1 {
+		t.Errorf("gate = %+v", gate)
+	}
+	if runs.gate.MinPassRate == nil || *runs.gate.MinPassRate != 90 || runs.gate.MaxFailed == nil || *runs.gate.MaxFailed != 0 {
+		t.Errorf("request = %+v", runs.gate)
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	unavailable := common.NewServiceError(common.ErrCode_ServiceUnavailable, nil)
+	client := newTestServer(t, &fakeProjects{errs: []error{unavailable, unavailable, unavailable}}, &fakeRuns{})
+	client.cfg.MinBackoff, client.cfg.MaxBackoff = time.Minute, time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetProject(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the deadline of the context", err)
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Error is an error response of the engine, decoded from its ServiceError body.
+// Match it with errors.Is against the Err variables or errors.As to read the details
+type Error struct {
+	StatusCode int
+	Code       string        `json:"code"`
+	Message    string        `json:"message"`
+	Details    []ErrorDetail `json:"error"`
+}
+
+// ErrorDetail is a problem with one part of the request, Path names the field
+type ErrorDetail struct {
+	ErrorCode string         `json:"errorCode"`
+	Message   string         `json:"message"`
+	Path      string         `json:"path"`
+	Meta      map[string]any `json:"meta"`
+}
+
+// The error codes of the engine, errors.Is(err, ErrNotFound) reports whether err is an *Error with that code
+var (
+	ErrBadRequest           = &Error{Code: "BAD_REQUEST"}
+	ErrUnauthorized         = &Error{Code: "UNAUTHORIZED"}
+	ErrForbidden            = &Error{Code: "FORBIDDEN"}
+	ErrNotFound             = &Error{Code: "RESOURCE_NOT_FOUND"}
+	ErrConflict             = &Error{Code: "CONFLICT"}
+	ErrPreconditionFailed   = &Error{Code: "PRECONDITION_FAILED"}
+	ErrPreconditionRequired = &Error{Code: "PRECONDITION_REQUIRED"}
+	ErrPayloadTooLarge      = &Error{Code: "PAYLOAD_TOO_LARGE"}
+	ErrUnprocessable        = &Error{Code: "UNPROCESSABLE"}
+	ErrTooManyRequests      = &Error{Code: "TOO_MANY_REQUESTS"}
+	ErrInternal             = &Error{Code: "INTERNAL_SERVER_ERROR"}
+	ErrServiceUnavailable   = &Error{Code: "SERVICE_UNAVAILABLE"}
+)
+
+func (e *Error) Error() string {
+	if len(e.Details) > 0 && e.Details[0].Message != "" {
+		return fmt.Sprintf("qms-engine: %s (%d): %s: %s", e.Code, e.StatusCode, e.Message, e.Details[0].Message)
+	}
+	return fmt.Sprintf("qms-engine: %s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Is matches the Err variables by code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// CurrentVersion returns the version of the resource sent with PRECONDITION_FAILED, so that
+// the caller can reload it and retry. It returns 0 when the error carries none
+func (e *Error) CurrentVersion() int {
+	for _, detail := range e.Details {
+		if version, ok := detail.Meta["currentVersion"].(float64); ok {
+			return int(version)
+		}
+	}
+	return 0
+}
+
+// newError decodes an error response. Bodies that are not a ServiceError, e.g. from a
+// proxy, keep the status with a code derived from it
+func newError(resp *http.Response, data []byte) error {
+	apiErr := &Error{}
+	if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Code == "" {
+		apiErr = &Error{Code: codeOfStatus(resp.StatusCode), Message: http.StatusText(resp.StatusCode)}
+	}
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
+
+func codeOfStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrBadRequest.Code
+	case http.StatusUnauthorized:
+		return ErrUnauthorized.Code
+	case http.StatusForbidden:
+		return ErrForbidden.Code
+	case http.StatusNotFound:
+		return ErrNotFound.Code
+	case http.StatusTooManyRequests:
+		return ErrTooManyRequests.Code
+	case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return ErrServiceUnavailable.Code
+	}
+	return ErrInternal.Code
+}
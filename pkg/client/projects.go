@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CreateProjectRequest holds a new project, the engine lower cases its name
+// CreateProjectRequest creates a project, Key is the optional upper case prefix of the IDs of its
+// test cases, e.g. QMS in QMS-42
+type CreateProjectRequest struct {
+	Name        string `json:"name"`
+	Key         string `json:"key,omitempty"`
+	Description string `json:"description"`
+}
+
+type CreateProjectResponse struct {
+	ID        int       `json:"id"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// UpdateProjectRequest changes the fields that are set, nil fields are kept
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Key         *string `json:"key,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type ProjectResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Key         string    `json:"key,omitempty"`
+	Description string    `json:"description"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CreateProject creates a project, names are unique once case folded and keys are unique
+func (c *Client) CreateProject(ctx context.Context, req *CreateProjectRequest) (*CreateProjectResponse, error) {
+	out := new(CreateProjectResponse)
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/project", body: req, out: out}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetProject returns the project with id
+func (c *Client) GetProject(ctx context.Context, id int) (*ProjectResponse, error) {
+	out := new(ProjectResponse)
+	if err := c.do(ctx, request{method: http.MethodGet, path: projectPath(id), out: out}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateProject changes the fields of req that are set. version is the version of the caller's
+// copy, an ErrPreconditionFailed error means the project changed since it was read. AnyVersion
+// changes the project whatever its version
+func (c *Client) UpdateProject(ctx context.Context, id, version int, req *UpdateProjectRequest) (*ProjectResponse, error) {
+	out := new(ProjectResponse)
+	err := c.do(ctx, request{
+		method: http.MethodPatch,
+		path:   projectPath(id),
+		header: ifMatch(version),
+		body:   req,
+		out:    out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteProject deletes the project when it is still at version, or at any version for AnyVersion
+func (c *Client) DeleteProject(ctx context.Context, id, version int) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: projectPath(id), header: ifMatch(version)})
+}
+
+// AnyVersion writes a project at whatever version it is, e.g. to delete it unconditionally
+const AnyVersion = 0
+
+func projectPath(id int) string {
+	return "/api/v1/project/" + strconv.Itoa(id)
+}
+
+// ifMatch sends version as the ETag the engine returned for it, AnyVersion as *
+func ifMatch(version int) http.Header {
+	header := http.Header{}
+	if version == AnyVersion {
+		header.Set("If-Match", "*")
+	} else {
+		header.Set("If-Match", strconv.Quote(strconv.Itoa(version)))
+	}
+	return header
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+type CreateTestRunRequest struct {
+	Name string `json:"name"`
+}
+
+// TestRunResponse describes a run, uploads that were cut off resume after LastSequence
+type TestRunResponse struct {
+	ID           int        `json:"id"`
+	ProjectID    int        `json:"projectId"`
+	Name         string     `json:"name"`
+	Status       string     `json:"status"`
+	LastSequence int64      `json:"lastSequence"`
+	ResultCount  int        `json:"resultCount"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ClosedAt     *time.Time `json:"closedAt"`
+}
+
+// TestResultRequest is one result of an upload. Status is passed, failed, skipped or blocked
+type TestResultRequest struct {
+	Sequence   int64  `json:"sequence"`
+	CaseKey    string `json:"caseKey"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	Message    string `json:"message"`
+}
+
+// UploadResultsResponse reports the progress of an upload. AckedSequence is the sequence up to
+// which results of the run are committed
+type UploadResultsResponse struct {
+	AckedSequence int64 `json:"ackedSequence"`
+	Received      int   `json:"received"`
+	Stored        int   `json:"stored"`
+	Duplicates    int   `json:"duplicates"`
+	Done          bool  `json:"done"`
+}
+
+// ListTestRunsOptions narrows the runs listed. Status is open or closed, all runs when empty.
+// Limit defaults to 20 on the engine
+type ListTestRunsOptions struct {
+	Status string
+	Limit  int
+}
+
+// GateOptions holds the thresholds of a quality gate. MinPassRate is a percentage of the passed,
+// failed and blocked results, MaxFailed bounds the failed and blocked ones. Thresholds left nil
+// are taken from the quality gate configured on the server
+type GateOptions struct {
+	MinPassRate *float64
+	MaxFailed   *int
+}
+
+// GateResponse is the verdict of a quality gate, Reasons lists the thresholds that were missed
+type GateResponse struct {
+	RunID    int            `json:"runId"`
+	Passed   bool           `json:"passed"`
+	PassRate float64        `json:"passRate"`
+	Counts   map[string]int `json:"counts"`
+	Reasons  []string       `json:"reasons"`
+}
+
+type listTestRunsResponse struct {
+	Runs []*TestRunResponse `json:"runs"`
+}
+
+// CreateTestRun opens a test run of the project with projectID
+func (c *Client) CreateTestRun(ctx context.Context, projectID int, req *CreateTestRunRequest) (*TestRunResponse, error) {
+	out := new(TestRunResponse)
+	path := projectPath(projectID) + "/run"
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: req, out: out}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTestRun returns the test run with id, its LastSequence is where an upload resumes
+func (c *Client) GetTestRun(ctx context.Context, id int) (*TestRunResponse, error) {
+	out := new(TestRunResponse)
+	if err := c.do(ctx, request{method: http.MethodGet, path: runPath(id), out: out}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTestRuns returns the newest runs of the project with projectID
+func (c *Client) ListTestRuns(ctx context.Context, projectID int, opts ListTestRunsOptions) ([]*TestRunResponse, error) {
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", opts.Status)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	out := new(listTestRunsResponse)
+	path := projectPath(projectID) + "/runs" + encodeQuery(query)
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, out: out}); err != nil {
+		return nil, err
+	}
+	return out.Runs, nil
+}
+
+// EvaluateGate tells whether the results of the run with id meet the thresholds of opts,
+// e.g. to fail a CI pipeline. A run without passed, failed or blocked results does not pass
+func (c *Client) EvaluateGate(ctx context.Context, id int, opts GateOptions) (*GateResponse, error) {
+	query := url.Values{}
+	if opts.MinPassRate != nil {
+		query.Set("minPassRate", strconv.FormatFloat(*opts.MinPassRate, 'f', -1, 64))
+	}
+	if opts.MaxFailed != nil {
+		query.Set("maxFailed", strconv.Itoa(*opts.MaxFailed))
+	}
+	out := new(GateResponse)
+	path := runPath(id) + "/gate" + encodeQuery(query)
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, out: out}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CloseTestRun closes the test run with id to further results
+func (c *Client) CloseTestRun(ctx context.Context, id int) (*TestRunResponse, error) {
+	out := new(TestRunResponse)
+	if err := c.do(ctx, request{method: http.MethodPost, path: runPath(id) + "/close", out: out}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UploadResults streams results into the open run with id. Sequences must increase through
+// results. When the upload is cut off or fails on the engine's side it is resumed after the
+// last sequence the engine committed, so no result is stored twice. The counts of the returned
+// progress cover every attempt
+func (c *Client) UploadResults(ctx context.Context, id int, results []*TestResultRequest) (*UploadResultsResponse, error) {
+	total := &UploadResultsResponse{}
+	for attempt := 0; ; attempt++ {
+		progress, err := c.uploadOnce(ctx, id, results)
+		total.AckedSequence = max(total.AckedSequence, progress.AckedSequence)
+		total.Received += progress.Received
+		total.Stored += progress.Stored
+		total.Duplicates += progress.Duplicates
+		if err == nil {
+			total.Done = true
+			return total, nil
+		}
+		if !resumable(err) || attempt >= c.cfg.MaxRetries {
+			return total, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-time.After(c.backoff(attempt, nil)):
+		}
+		run, err := c.GetTestRun(ctx, id)
+		if err != nil {
+			return total, err
+		}
+		results = after(results, run.LastSequence)
+	}
+}
+
+// uploadOnce sends results in one request and reads the progress lines of the engine
+func (c *Client) uploadOnce(ctx context.Context, id int, results []*TestResultRequest) (*UploadResultsResponse, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return &UploadResultsResponse{}, fmt.Errorf("client: failed to encode result: %w", err)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", ndjsonContentType)
+	req := request{method: http.MethodPost, path: runPath(id) + "/results", header: header}
+	resp, err := c.send(ctx, req, body.Bytes())
+	if err != nil {
+		return &UploadResultsResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &UploadResultsResponse{}, c.decode(resp, nil)
+	}
+	defer resp.Body.Close()
+	return readProgress(resp)
+}
+
+// uploadLine is a progress line, its error is decoded as an *Error
+type uploadLine struct {
+	UploadResultsResponse
+	Error *Error `json:"error"`
+}
+
+// readProgress returns the last progress line, failing when the stream ended without done
+func readProgress(resp *http.Response) (*UploadResultsResponse, error) {
+	progress := &UploadResultsResponse{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := new(uploadLine)
+		if err := json.Unmarshal(scanner.Bytes(), line); err != nil {
+			return progress, fmt.Errorf("client: failed to decode progress: %w", err)
+		}
+		*progress = line.UploadResultsResponse
+		if line.Error != nil {
+			line.Error.StatusCode = resp.StatusCode
+			return progress, line.Error
+		}
+		if progress.Done {
+			return progress, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return progress, err
+	}
+	return progress, io.ErrUnexpectedEOF
+}
+
+// resumable reports whether a failed upload is worth resuming: the connection broke
+// or the engine failed, while requests it refused would be refused again
+func resumable(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == ErrInternal.Code || apiErr.Code == ErrServiceUnavailable.Code ||
+			apiErr.Code == ErrTooManyRequests.Code
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// after returns the results with a sequence above lastSequence
+func after(results []*TestResultRequest, lastSequence int64) []*TestResultRequest {
+	remaining := make([]*TestResultRequest, 0, len(results))
+	for _, result := range results {
+		if result.Sequence > lastSequence {
+			remaining = append(remaining, result)
+		}
+	}
+	return remaining
+}
+
+func runPath(id int) string {
+	return "/api/v1/run/" + strconv.Itoa(id)
+}
+
+func encodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
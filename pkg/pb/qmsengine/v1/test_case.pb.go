@@ -0,0 +1,769 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: qmsengine/v1/test_case.proto
+
+package qmsenginev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TestSuite struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProjectId     int64                  `protobuf:"varint,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestSuite) Reset() {
+	*x = TestSuite{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestSuite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestSuite) ProtoMessage() {}
+
+func (x *TestSuite) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestSuite.ProtoReflect.Descriptor instead.
+func (*TestSuite) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TestSuite) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TestSuite) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *TestSuite) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TestSuite) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TestSuite) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TestSuite) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type TestCase struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProjectId int64                  `protobuf:"varint,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// suite_id is unset for cases outside of suites
+	SuiteId *int64 `protobuf:"varint,3,opt,name=suite_id,json=suiteId,proto3,oneof" json:"suite_id,omitempty"`
+	// external_id is the ID of the case where it was written, unique in the project
+	ExternalId    string            `protobuf:"bytes,4,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Title         string            `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Section       string            `protobuf:"bytes,6,opt,name=section,proto3" json:"section,omitempty"`
+	Priority      string            `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Preconditions string            `protobuf:"bytes,8,opt,name=preconditions,proto3" json:"preconditions,omitempty"`
+	Description   string            `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	Tags          []string          `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	CustomFields  map[string]string `protobuf:"bytes,11,rep,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// steps are only set by GetTestCase
+	Steps         []*TestStep            `protobuf:"bytes,12,rep,name=steps,proto3" json:"steps,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestCase) Reset() {
+	*x = TestCase{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestCase) ProtoMessage() {}
+
+func (x *TestCase) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestCase.ProtoReflect.Descriptor instead.
+func (*TestCase) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TestCase) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TestCase) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *TestCase) GetSuiteId() int64 {
+	if x != nil && x.SuiteId != nil {
+		return *x.SuiteId
+	}
+	return 0
+}
+
+func (x *TestCase) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *TestCase) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TestCase) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *TestCase) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *TestCase) GetPreconditions() string {
+	if x != nil {
+		return x.Preconditions
+	}
+	return ""
+}
+
+func (x *TestCase) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TestCase) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TestCase) GetCustomFields() map[string]string {
+	if x != nil {
+		return x.CustomFields
+	}
+	return nil
+}
+
+func (x *TestCase) GetSteps() []*TestStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *TestCase) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TestCase) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type TestStep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// position starts at 1
+	Position      int32  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	Action        string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Expected      string `protobuf:"bytes,3,opt,name=expected,proto3" json:"expected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestStep) Reset() {
+	*x = TestStep{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestStep) ProtoMessage() {}
+
+func (x *TestStep) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestStep.ProtoReflect.Descriptor instead.
+func (*TestStep) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TestStep) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *TestStep) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *TestStep) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+type ListTestSuitesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTestSuitesRequest) Reset() {
+	*x = ListTestSuitesRequest{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTestSuitesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTestSuitesRequest) ProtoMessage() {}
+
+func (x *ListTestSuitesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTestSuitesRequest.ProtoReflect.Descriptor instead.
+func (*ListTestSuitesRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTestSuitesRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+type ListTestSuitesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TestSuites    []*TestSuite           `protobuf:"bytes,1,rep,name=test_suites,json=testSuites,proto3" json:"test_suites,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTestSuitesResponse) Reset() {
+	*x = ListTestSuitesResponse{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTestSuitesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTestSuitesResponse) ProtoMessage() {}
+
+func (x *ListTestSuitesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTestSuitesResponse.ProtoReflect.Descriptor instead.
+func (*ListTestSuitesResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTestSuitesResponse) GetTestSuites() []*TestSuite {
+	if x != nil {
+		return x.TestSuites
+	}
+	return nil
+}
+
+type ListTestCasesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// suite_id keeps the cases of a suite, all cases are listed when it is 0
+	SuiteId int64 `protobuf:"varint,2,opt,name=suite_id,json=suiteId,proto3" json:"suite_id,omitempty"`
+	AfterId int64 `protobuf:"varint,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	// page_size is 100 when it is 0, at most 500
+	PageSize      int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTestCasesRequest) Reset() {
+	*x = ListTestCasesRequest{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTestCasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTestCasesRequest) ProtoMessage() {}
+
+func (x *ListTestCasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTestCasesRequest.ProtoReflect.Descriptor instead.
+func (*ListTestCasesRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTestCasesRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *ListTestCasesRequest) GetSuiteId() int64 {
+	if x != nil {
+		return x.SuiteId
+	}
+	return 0
+}
+
+func (x *ListTestCasesRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *ListTestCasesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListTestCasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TestCases     []*TestCase            `protobuf:"bytes,1,rep,name=test_cases,json=testCases,proto3" json:"test_cases,omitempty"`
+	NextAfterId   int64                  `protobuf:"varint,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTestCasesResponse) Reset() {
+	*x = ListTestCasesResponse{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTestCasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTestCasesResponse) ProtoMessage() {}
+
+func (x *ListTestCasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTestCasesResponse.ProtoReflect.Descriptor instead.
+func (*ListTestCasesResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTestCasesResponse) GetTestCases() []*TestCase {
+	if x != nil {
+		return x.TestCases
+	}
+	return nil
+}
+
+func (x *ListTestCasesResponse) GetNextAfterId() int64 {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return 0
+}
+
+type GetTestCaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTestCaseRequest) Reset() {
+	*x = GetTestCaseRequest{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTestCaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTestCaseRequest) ProtoMessage() {}
+
+func (x *GetTestCaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTestCaseRequest.ProtoReflect.Descriptor instead.
+func (*GetTestCaseRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTestCaseRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *GetTestCaseRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetTestCaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TestCase      *TestCase              `protobuf:"bytes,1,opt,name=test_case,json=testCase,proto3" json:"test_case,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTestCaseResponse) Reset() {
+	*x = GetTestCaseResponse{}
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTestCaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTestCaseResponse) ProtoMessage() {}
+
+func (x *GetTestCaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_case_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTestCaseResponse.ProtoReflect.Descriptor instead.
+func (*GetTestCaseResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_case_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTestCaseResponse) GetTestCase() *TestCase {
+	if x != nil {
+		return x.TestCase
+	}
+	return nil
+}
+
+var File_qmsengine_v1_test_case_proto protoreflect.FileDescriptor
+
+const file_qmsengine_v1_test_case_proto_rawDesc = "" +
+	"\n" +
+	"\x1cqmsengine/v1/test_case.proto\x12\fqmsengine.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe6\x01\n" +
+	"\tTestSuite\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x02 \x01(\x03R\tprojectId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xe3\x04\n" +
+	"\bTestCase\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x02 \x01(\x03R\tprojectId\x12\x1e\n" +
+	"\bsuite_id\x18\x03 \x01(\x03H\x00R\asuiteId\x88\x01\x01\x12\x1f\n" +
+	"\vexternal_id\x18\x04 \x01(\tR\n" +
+	"externalId\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x18\n" +
+	"\asection\x18\x06 \x01(\tR\asection\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12$\n" +
+	"\rpreconditions\x18\b \x01(\tR\rpreconditions\x12 \n" +
+	"\vdescription\x18\t \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x12M\n" +
+	"\rcustom_fields\x18\v \x03(\v2(.qmsengine.v1.TestCase.CustomFieldsEntryR\fcustomFields\x12,\n" +
+	"\x05steps\x18\f \x03(\v2\x16.qmsengine.v1.TestStepR\x05steps\x129\n" +
+	"\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a?\n" +
+	"\x11CustomFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
+	"\t_suite_id\"Z\n" +
+	"\bTestStep\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x05R\bposition\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x1a\n" +
+	"\bexpected\x18\x03 \x01(\tR\bexpected\"6\n" +
+	"\x15ListTestSuitesRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\"R\n" +
+	"\x16ListTestSuitesResponse\x128\n" +
+	"\vtest_suites\x18\x01 \x03(\v2\x17.qmsengine.v1.TestSuiteR\n" +
+	"testSuites\"\x88\x01\n" +
+	"\x14ListTestCasesRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x19\n" +
+	"\bsuite_id\x18\x02 \x01(\x03R\asuiteId\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\x03R\aafterId\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"r\n" +
+	"\x15ListTestCasesResponse\x125\n" +
+	"\n" +
+	"test_cases\x18\x01 \x03(\v2\x16.qmsengine.v1.TestCaseR\ttestCases\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\x03R\vnextAfterId\"C\n" +
+	"\x12GetTestCaseRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"J\n" +
+	"\x13GetTestCaseResponse\x123\n" +
+	"\ttest_case\x18\x01 \x01(\v2\x16.qmsengine.v1.TestCaseR\btestCase2\x9c\x02\n" +
+	"\x0fTestCaseService\x12[\n" +
+	"\x0eListTestSuites\x12#.qmsengine.v1.ListTestSuitesRequest\x1a$.qmsengine.v1.ListTestSuitesResponse\x12X\n" +
+	"\rListTestCases\x12\".qmsengine.v1.ListTestCasesRequest\x1a#.qmsengine.v1.ListTestCasesResponse\x12R\n" +
+	"\vGetTestCase\x12 .qmsengine.v1.GetTestCaseRequest\x1a!.qmsengine.v1.GetTestCaseResponseBGZEgithub.com/project-weekend/qms-engine/pkg/pb/qmsengine/v1;qmsenginev1b\x06proto3"
+
+var (
+	file_qmsengine_v1_test_case_proto_rawDescOnce sync.Once
+	file_qmsengine_v1_test_case_proto_rawDescData []byte
+)
+
+func file_qmsengine_v1_test_case_proto_rawDescGZIP() []byte {
+	file_qmsengine_v1_test_case_proto_rawDescOnce.Do(func() {
+		file_qmsengine_v1_test_case_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_qmsengine_v1_test_case_proto_rawDesc), len(file_qmsengine_v1_test_case_proto_rawDesc)))
+	})
+	return file_qmsengine_v1_test_case_proto_rawDescData
+}
+
+var file_qmsengine_v1_test_case_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_qmsengine_v1_test_case_proto_goTypes = []any{
+	(*TestSuite)(nil),              // 0: qmsengine.v1.TestSuite
+	(*TestCase)(nil),               // 1: qmsengine.v1.TestCase
+	(*TestStep)(nil),               // 2: qmsengine.v1.TestStep
+	(*ListTestSuitesRequest)(nil),  // 3: qmsengine.v1.ListTestSuitesRequest
+	(*ListTestSuitesResponse)(nil), // 4: qmsengine.v1.ListTestSuitesResponse
+	(*ListTestCasesRequest)(nil),   // 5: qmsengine.v1.ListTestCasesRequest
+	(*ListTestCasesResponse)(nil),  // 6: qmsengine.v1.ListTestCasesResponse
+	(*GetTestCaseRequest)(nil),     // 7: qmsengine.v1.GetTestCaseRequest
+	(*GetTestCaseResponse)(nil),    // 8: qmsengine.v1.GetTestCaseResponse
+	nil,                            // 9: qmsengine.v1.TestCase.CustomFieldsEntry
+	(*timestamppb.Timestamp)(nil),  // 10: google.protobuf.Timestamp
+}
+var file_qmsengine_v1_test_case_proto_depIdxs = []int32{
+	10, // 0: qmsengine.v1.TestSuite.created_at:type_name -> google.protobuf.Timestamp
+	10, // 1: qmsengine.v1.TestSuite.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: qmsengine.v1.TestCase.custom_fields:type_name -> qmsengine.v1.TestCase.CustomFieldsEntry
+	2,  // 3: qmsengine.v1.TestCase.steps:type_name -> qmsengine.v1.TestStep
+	10, // 4: qmsengine.v1.TestCase.created_at:type_name -> google.protobuf.Timestamp
+	10, // 5: qmsengine.v1.TestCase.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: qmsengine.v1.ListTestSuitesResponse.test_suites:type_name -> qmsengine.v1.TestSuite
+	1,  // 7: qmsengine.v1.ListTestCasesResponse.test_cases:type_name -> qmsengine.v1.TestCase
+	1,  // 8: qmsengine.v1.GetTestCaseResponse.test_case:type_name -> qmsengine.v1.TestCase
+	3,  // 9: qmsengine.v1.TestCaseService.ListTestSuites:input_type -> qmsengine.v1.ListTestSuitesRequest
+	5,  // 10: qmsengine.v1.TestCaseService.ListTestCases:input_type -> qmsengine.v1.ListTestCasesRequest
+	7,  // 11: qmsengine.v1.TestCaseService.GetTestCase:input_type -> qmsengine.v1.GetTestCaseRequest
+	4,  // 12: qmsengine.v1.TestCaseService.ListTestSuites:output_type -> qmsengine.v1.ListTestSuitesResponse
+	6,  // 13: qmsengine.v1.TestCaseService.ListTestCases:output_type -> qmsengine.v1.ListTestCasesResponse
+	8,  // 14: qmsengine.v1.TestCaseService.GetTestCase:output_type -> qmsengine.v1.GetTestCaseResponse
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_qmsengine_v1_test_case_proto_init() }
+func file_qmsengine_v1_test_case_proto_init() {
+	if File_qmsengine_v1_test_case_proto != nil {
+		return
+	}
+	file_qmsengine_v1_test_case_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_qmsengine_v1_test_case_proto_rawDesc), len(file_qmsengine_v1_test_case_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_qmsengine_v1_test_case_proto_goTypes,
+		DependencyIndexes: file_qmsengine_v1_test_case_proto_depIdxs,
+		MessageInfos:      file_qmsengine_v1_test_case_proto_msgTypes,
+	}.Build()
+	File_qmsengine_v1_test_case_proto = out.File
+	file_qmsengine_v1_test_case_proto_goTypes = nil
+	file_qmsengine_v1_test_case_proto_depIdxs = nil
+}
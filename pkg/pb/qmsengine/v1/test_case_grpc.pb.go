@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: qmsengine/v1/test_case.proto
+
+package qmsenginev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TestCaseService_ListTestSuites_FullMethodName = "/qmsengine.v1.TestCaseService/ListTestSuites"
+	TestCaseService_ListTestCases_FullMethodName  = "/qmsengine.v1.TestCaseService/ListTestCases"
+	TestCaseService_GetTestCase_FullMethodName    = "/qmsengine.v1.TestCaseService/GetTestCase"
+)
+
+// TestCaseServiceClient is the client API for TestCaseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TestCaseService reads the suites and cases of projects, which the imports write
+type TestCaseServiceClient interface {
+	ListTestSuites(ctx context.Context, in *ListTestSuitesRequest, opts ...grpc.CallOption) (*ListTestSuitesResponse, error)
+	// ListTestCases pages through the cases of a project by ID, without their steps. The next page
+	// starts after the next_after_id of the previous one, which is 0 on the last page
+	ListTestCases(ctx context.Context, in *ListTestCasesRequest, opts ...grpc.CallOption) (*ListTestCasesResponse, error)
+	// GetTestCase returns a case with its steps
+	GetTestCase(ctx context.Context, in *GetTestCaseRequest, opts ...grpc.CallOption) (*GetTestCaseResponse, error)
+}
+
+type testCaseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTestCaseServiceClient(cc grpc.ClientConnInterface) TestCaseServiceClient {
+	return &testCaseServiceClient{cc}
+}
+
+func (c *testCaseServiceClient) ListTestSuites(ctx context.Context, in *ListTestSuitesRequest, opts ...grpc.CallOption) (*ListTestSuitesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTestSuitesResponse)
+	err := c.cc.Invoke(ctx, TestCaseService_ListTestSuites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *testCaseServiceClient) ListTestCases(ctx context.Context, in *ListTestCasesRequest, opts ...grpc.CallOption) (*ListTestCasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTestCasesResponse)
+	err := c.cc.Invoke(ctx, TestCaseService_ListTestCases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *testCaseServiceClient) GetTestCase(ctx context.Context, in *GetTestCaseRequest, opts ...grpc.CallOption) (*GetTestCaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTestCaseResponse)
+	err := c.cc.Invoke(ctx, TestCaseService_GetTestCase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TestCaseServiceServer is the server API for TestCaseService service.
+// All implementations must embed UnimplementedTestCaseServiceServer
+// for forward compatibility.
+//
+// TestCaseService reads the suites and cases of projects, which the imports write
+type TestCaseServiceServer interface {
+	ListTestSuites(context.Context, *ListTestSuitesRequest) (*ListTestSuitesResponse, error)
+	// ListTestCases pages through the cases of a project by ID, without their steps. The next page
+	// starts after the next_after_id of the previous one, which is 0 on the last page
+	ListTestCases(context.Context, *ListTestCasesRequest) (*ListTestCasesResponse, error)
+	// GetTestCase returns a case with its steps
+	GetTestCase(context.Context, *GetTestCaseRequest) (*GetTestCaseResponse, error)
+	mustEmbedUnimplementedTestCaseServiceServer()
+}
+
+// UnimplementedTestCaseServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTestCaseServiceServer struct{}
+
+func (UnimplementedTestCaseServiceServer) ListTestSuites(context.Context, *ListTestSuitesRequest) (*ListTestSuitesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTestSuites not implemented")
+}
+func (UnimplementedTestCaseServiceServer) ListTestCases(context.Context, *ListTestCasesRequest) (*ListTestCasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTestCases not implemented")
+}
+func (UnimplementedTestCaseServiceServer) GetTestCase(context.Context, *GetTestCaseRequest) (*GetTestCaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTestCase not implemented")
+}
+func (UnimplementedTestCaseServiceServer) mustEmbedUnimplementedTestCaseServiceServer() {}
+func (UnimplementedTestCaseServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeTestCaseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TestCaseServiceServer will
+// result in compilation errors.
+type UnsafeTestCaseServiceServer interface {
+	mustEmbedUnimplementedTestCaseServiceServer()
+}
+
+func RegisterTestCaseServiceServer(s grpc.ServiceRegistrar, srv TestCaseServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTestCaseServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TestCaseService_ServiceDesc, srv)
+}
+
+func _TestCaseService_ListTestSuites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTestSuitesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestCaseServiceServer).ListTestSuites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TestCaseService_ListTestSuites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestCaseServiceServer).ListTestSuites(ctx, req.(*ListTestSuitesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TestCaseService_ListTestCases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTestCasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestCaseServiceServer).ListTestCases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TestCaseService_ListTestCases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestCaseServiceServer).ListTestCases(ctx, req.(*ListTestCasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TestCaseService_GetTestCase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTestCaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestCaseServiceServer).GetTestCase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TestCaseService_GetTestCase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestCaseServiceServer).GetTestCase(ctx, req.(*GetTestCaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TestCaseService_ServiceDesc is the grpc.ServiceDesc for TestCaseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TestCaseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "qmsengine.v1.TestCaseService",
+	HandlerType: (*TestCaseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTestSuites",
+			Handler:    _TestCaseService_ListTestSuites_Handler,
+		},
+		{
+			MethodName: "ListTestCases",
+			Handler:    _TestCaseService_ListTestCases_Handler,
+		},
+		{
+			MethodName: "GetTestCase",
+			Handler:    _TestCaseService_GetTestCase_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "qmsengine/v1/test_case.proto",
+}
@@ -0,0 +1,743 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: qmsengine/v1/test_run.proto
+
+package qmsenginev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TestRun struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProjectId int64                  `protobuf:"varint,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// status is open while results are accepted, then closed
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	LastSequence  int64                  `protobuf:"varint,5,opt,name=last_sequence,json=lastSequence,proto3" json:"last_sequence,omitempty"`
+	ResultCount   int64                  `protobuf:"varint,6,opt,name=result_count,json=resultCount,proto3" json:"result_count,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ClosedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestRun) Reset() {
+	*x = TestRun{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestRun) ProtoMessage() {}
+
+func (x *TestRun) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestRun.ProtoReflect.Descriptor instead.
+func (*TestRun) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TestRun) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TestRun) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *TestRun) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TestRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TestRun) GetLastSequence() int64 {
+	if x != nil {
+		return x.LastSequence
+	}
+	return 0
+}
+
+func (x *TestRun) GetResultCount() int64 {
+	if x != nil {
+		return x.ResultCount
+	}
+	return 0
+}
+
+func (x *TestRun) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TestRun) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *TestRun) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+type TestResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sequence is positive and increases through the upload
+	Sequence int64  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	CaseKey  string `protobuf:"bytes,2,opt,name=case_key,json=caseKey,proto3" json:"case_key,omitempty"`
+	// status is passed, failed, skipped or blocked
+	Status        string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	DurationMs    int64  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestResult) Reset() {
+	*x = TestResult{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestResult) ProtoMessage() {}
+
+func (x *TestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestResult.ProtoReflect.Descriptor instead.
+func (*TestResult) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TestResult) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TestResult) GetCaseKey() string {
+	if x != nil {
+		return x.CaseKey
+	}
+	return ""
+}
+
+func (x *TestResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TestResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *TestResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CreateTestRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     int64                  `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTestRunRequest) Reset() {
+	*x = CreateTestRunRequest{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTestRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTestRunRequest) ProtoMessage() {}
+
+func (x *CreateTestRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTestRunRequest.ProtoReflect.Descriptor instead.
+func (*CreateTestRunRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTestRunRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *CreateTestRunRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateTestRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TestRun       *TestRun               `protobuf:"bytes,1,opt,name=test_run,json=testRun,proto3" json:"test_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTestRunResponse) Reset() {
+	*x = CreateTestRunResponse{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTestRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTestRunResponse) ProtoMessage() {}
+
+func (x *CreateTestRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTestRunResponse.ProtoReflect.Descriptor instead.
+func (*CreateTestRunResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTestRunResponse) GetTestRun() *TestRun {
+	if x != nil {
+		return x.TestRun
+	}
+	return nil
+}
+
+type GetTestRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTestRunRequest) Reset() {
+	*x = GetTestRunRequest{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTestRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTestRunRequest) ProtoMessage() {}
+
+func (x *GetTestRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTestRunRequest.ProtoReflect.Descriptor instead.
+func (*GetTestRunRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTestRunRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetTestRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TestRun       *TestRun               `protobuf:"bytes,1,opt,name=test_run,json=testRun,proto3" json:"test_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTestRunResponse) Reset() {
+	*x = GetTestRunResponse{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTestRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTestRunResponse) ProtoMessage() {}
+
+func (x *GetTestRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTestRunResponse.ProtoReflect.Descriptor instead.
+func (*GetTestRunResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetTestRunResponse) GetTestRun() *TestRun {
+	if x != nil {
+		return x.TestRun
+	}
+	return nil
+}
+
+type CloseTestRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseTestRunRequest) Reset() {
+	*x = CloseTestRunRequest{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseTestRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseTestRunRequest) ProtoMessage() {}
+
+func (x *CloseTestRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseTestRunRequest.ProtoReflect.Descriptor instead.
+func (*CloseTestRunRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CloseTestRunRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CloseTestRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TestRun       *TestRun               `protobuf:"bytes,1,opt,name=test_run,json=testRun,proto3" json:"test_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseTestRunResponse) Reset() {
+	*x = CloseTestRunResponse{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseTestRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseTestRunResponse) ProtoMessage() {}
+
+func (x *CloseTestRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseTestRunResponse.ProtoReflect.Descriptor instead.
+func (*CloseTestRunResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CloseTestRunResponse) GetTestRun() *TestRun {
+	if x != nil {
+		return x.TestRun
+	}
+	return nil
+}
+
+type UploadResultsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// run_id is the same in every message of the stream
+	RunId         int64         `protobuf:"varint,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Results       []*TestResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadResultsRequest) Reset() {
+	*x = UploadResultsRequest{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadResultsRequest) ProtoMessage() {}
+
+func (x *UploadResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadResultsRequest.ProtoReflect.Descriptor instead.
+func (*UploadResultsRequest) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UploadResultsRequest) GetRunId() int64 {
+	if x != nil {
+		return x.RunId
+	}
+	return 0
+}
+
+func (x *UploadResultsRequest) GetResults() []*TestResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type UploadResultsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// acked_sequence is the sequence up to which results of the run are committed
+	AckedSequence int64 `protobuf:"varint,1,opt,name=acked_sequence,json=ackedSequence,proto3" json:"acked_sequence,omitempty"`
+	Received      int64 `protobuf:"varint,2,opt,name=received,proto3" json:"received,omitempty"`
+	Stored        int64 `protobuf:"varint,3,opt,name=stored,proto3" json:"stored,omitempty"`
+	Duplicates    int64 `protobuf:"varint,4,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Done          bool  `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadResultsResponse) Reset() {
+	*x = UploadResultsResponse{}
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadResultsResponse) ProtoMessage() {}
+
+func (x *UploadResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_qmsengine_v1_test_run_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadResultsResponse.ProtoReflect.Descriptor instead.
+func (*UploadResultsResponse) Descriptor() ([]byte, []int) {
+	return file_qmsengine_v1_test_run_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UploadResultsResponse) GetAckedSequence() int64 {
+	if x != nil {
+		return x.AckedSequence
+	}
+	return 0
+}
+
+func (x *UploadResultsResponse) GetReceived() int64 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *UploadResultsResponse) GetStored() int64 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *UploadResultsResponse) GetDuplicates() int64 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *UploadResultsResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+var File_qmsengine_v1_test_run_proto protoreflect.FileDescriptor
+
+const file_qmsengine_v1_test_run_proto_rawDesc = "" +
+	"\n" +
+	"\x1bqmsengine/v1/test_run.proto\x12\fqmsengine.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdb\x02\n" +
+	"\aTestRun\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x02 \x01(\x03R\tprojectId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\rlast_sequence\x18\x05 \x01(\x03R\flastSequence\x12!\n" +
+	"\fresult_count\x18\x06 \x01(\x03R\vresultCount\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x127\n" +
+	"\tclosed_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\"\x96\x01\n" +
+	"\n" +
+	"TestResult\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x19\n" +
+	"\bcase_key\x18\x02 \x01(\tR\acaseKey\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"I\n" +
+	"\x14CreateTestRunRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\x03R\tprojectId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"I\n" +
+	"\x15CreateTestRunResponse\x120\n" +
+	"\btest_run\x18\x01 \x01(\v2\x15.qmsengine.v1.TestRunR\atestRun\"#\n" +
+	"\x11GetTestRunRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"F\n" +
+	"\x12GetTestRunResponse\x120\n" +
+	"\btest_run\x18\x01 \x01(\v2\x15.qmsengine.v1.TestRunR\atestRun\"%\n" +
+	"\x13CloseTestRunRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"H\n" +
+	"\x14CloseTestRunResponse\x120\n" +
+	"\btest_run\x18\x01 \x01(\v2\x15.qmsengine.v1.TestRunR\atestRun\"a\n" +
+	"\x14UploadResultsRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\x03R\x05runId\x122\n" +
+	"\aresults\x18\x02 \x03(\v2\x18.qmsengine.v1.TestResultR\aresults\"\xa6\x01\n" +
+	"\x15UploadResultsResponse\x12%\n" +
+	"\x0eacked_sequence\x18\x01 \x01(\x03R\rackedSequence\x12\x1a\n" +
+	"\breceived\x18\x02 \x01(\x03R\breceived\x12\x16\n" +
+	"\x06stored\x18\x03 \x01(\x03R\x06stored\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x04 \x01(\x03R\n" +
+	"duplicates\x12\x12\n" +
+	"\x04done\x18\x05 \x01(\bR\x04done2\xf0\x02\n" +
+	"\x0eTestRunService\x12X\n" +
+	"\rCreateTestRun\x12\".qmsengine.v1.CreateTestRunRequest\x1a#.qmsengine.v1.CreateTestRunResponse\x12O\n" +
+	"\n" +
+	"GetTestRun\x12\x1f.qmsengine.v1.GetTestRunRequest\x1a .qmsengine.v1.GetTestRunResponse\x12U\n" +
+	"\fCloseTestRun\x12!.qmsengine.v1.CloseTestRunRequest\x1a\".qmsengine.v1.CloseTestRunResponse\x12\\\n" +
+	"\rUploadResults\x12\".qmsengine.v1.UploadResultsRequest\x1a#.qmsengine.v1.UploadResultsResponse(\x010\x01BGZEgithub.com/project-weekend/qms-engine/pkg/pb/qmsengine/v1;qmsenginev1b\x06proto3"
+
+var (
+	file_qmsengine_v1_test_run_proto_rawDescOnce sync.Once
+	file_qmsengine_v1_test_run_proto_rawDescData []byte
+)
+
+func file_qmsengine_v1_test_run_proto_rawDescGZIP() []byte {
+	file_qmsengine_v1_test_run_proto_rawDescOnce.Do(func() {
+		file_qmsengine_v1_test_run_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_qmsengine_v1_test_run_proto_rawDesc), len(file_qmsengine_v1_test_run_proto_rawDesc)))
+	})
+	return file_qmsengine_v1_test_run_proto_rawDescData
+}
+
+var file_qmsengine_v1_test_run_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_qmsengine_v1_test_run_proto_goTypes = []any{
+	(*TestRun)(nil),               // 0: qmsengine.v1.TestRun
+	(*TestResult)(nil),            // 1: qmsengine.v1.TestResult
+	(*CreateTestRunRequest)(nil),  // 2: qmsengine.v1.CreateTestRunRequest
+	(*CreateTestRunResponse)(nil), // 3: qmsengine.v1.CreateTestRunResponse
+	(*GetTestRunRequest)(nil),     // 4: qmsengine.v1.GetTestRunRequest
+	(*GetTestRunResponse)(nil),    // 5: qmsengine.v1.GetTestRunResponse
+	(*CloseTestRunRequest)(nil),   // 6: qmsengine.v1.CloseTestRunRequest
+	(*CloseTestRunResponse)(nil),  // 7: qmsengine.v1.CloseTestRunResponse
+	(*UploadResultsRequest)(nil),  // 8: qmsengine.v1.UploadResultsRequest
+	(*UploadResultsResponse)(nil), // 9: qmsengine.v1.UploadResultsResponse
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_qmsengine_v1_test_run_proto_depIdxs = []int32{
+	10, // 0: qmsengine.v1.TestRun.created_at:type_name -> google.protobuf.Timestamp
+	10, // 1: qmsengine.v1.TestRun.updated_at:type_name -> google.protobuf.Timestamp
+	10, // 2: qmsengine.v1.TestRun.closed_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: qmsengine.v1.CreateTestRunResponse.test_run:type_name -> qmsengine.v1.TestRun
+	0,  // 4: qmsengine.v1.GetTestRunResponse.test_run:type_name -> qmsengine.v1.TestRun
+	0,  // 5: qmsengine.v1.CloseTestRunResponse.test_run:type_name -> qmsengine.v1.TestRun
+	1,  // 6: qmsengine.v1.UploadResultsRequest.results:type_name -> qmsengine.v1.TestResult
+	2,  // 7: qmsengine.v1.TestRunService.CreateTestRun:input_type -> qmsengine.v1.CreateTestRunRequest
+	4,  // 8: qmsengine.v1.TestRunService.GetTestRun:input_type -> qmsengine.v1.GetTestRunRequest
+	6,  // 9: qmsengine.v1.TestRunService.CloseTestRun:input_type -> qmsengine.v1.CloseTestRunRequest
+	8,  // 10: qmsengine.v1.TestRunService.UploadResults:input_type -> qmsengine.v1.UploadResultsRequest
+	3,  // 11: qmsengine.v1.TestRunService.CreateTestRun:output_type -> qmsengine.v1.CreateTestRunResponse
+	5,  // 12: qmsengine.v1.TestRunService.GetTestRun:output_type -> qmsengine.v1.GetTestRunResponse
+	7,  // 13: qmsengine.v1.TestRunService.CloseTestRun:output_type -> qmsengine.v1.CloseTestRunResponse
+	9,  // 14: qmsengine.v1.TestRunService.UploadResults:output_type -> qmsengine.v1.UploadResultsResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_qmsengine_v1_test_run_proto_init() }
+func file_qmsengine_v1_test_run_proto_init() {
+	if File_qmsengine_v1_test_run_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_qmsengine_v1_test_run_proto_rawDesc), len(file_qmsengine_v1_test_run_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_qmsengine_v1_test_run_proto_goTypes,
+		DependencyIndexes: file_qmsengine_v1_test_run_proto_depIdxs,
+		MessageInfos:      file_qmsengine_v1_test_run_proto_msgTypes,
+	}.Build()
+	File_qmsengine_v1_test_run_proto = out.File
+	file_qmsengine_v1_test_run_proto_goTypes = nil
+	file_qmsengine_v1_test_run_proto_depIdxs = nil
+}
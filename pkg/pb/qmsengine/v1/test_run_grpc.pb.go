@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: qmsengine/v1/test_run.proto
+
+package qmsenginev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TestRunService_CreateTestRun_FullMethodName = "/qmsengine.v1.TestRunService/CreateTestRun"
+	TestRunService_GetTestRun_FullMethodName    = "/qmsengine.v1.TestRunService/GetTestRun"
+	TestRunService_CloseTestRun_FullMethodName  = "/qmsengine.v1.TestRunService/CloseTestRun"
+	TestRunService_UploadResults_FullMethodName = "/qmsengine.v1.TestRunService/UploadResults"
+)
+
+// TestRunServiceClient is the client API for TestRunService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TestRunService manages test runs and their results, it mirrors the /api/v1/run routes of the REST API
+type TestRunServiceClient interface {
+	CreateTestRun(ctx context.Context, in *CreateTestRunRequest, opts ...grpc.CallOption) (*CreateTestRunResponse, error)
+	// GetTestRun returns the run, an upload that was cut off resumes after its last_sequence
+	GetTestRun(ctx context.Context, in *GetTestRunRequest, opts ...grpc.CallOption) (*GetTestRunResponse, error)
+	CloseTestRun(ctx context.Context, in *CloseTestRunRequest, opts ...grpc.CallOption) (*CloseTestRunResponse, error)
+	// UploadResults stores the streamed results in an open run. The server answers after every
+	// committed batch and once more with done set when the client closes its side. When the upload
+	// fails the last progress, without done, comes ahead of the error, resume after its
+	// acked_sequence. Sequences must increase through the stream, results whose sequence is already
+	// stored are dropped as duplicates
+	UploadResults(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UploadResultsRequest, UploadResultsResponse], error)
+}
+
+type testRunServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTestRunServiceClient(cc grpc.ClientConnInterface) TestRunServiceClient {
+	return &testRunServiceClient{cc}
+}
+
+func (c *testRunServiceClient) CreateTestRun(ctx context.Context, in *CreateTestRunRequest, opts ...grpc.CallOption) (*CreateTestRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTestRunResponse)
+	err := c.cc.Invoke(ctx, TestRunService_CreateTestRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *testRunServiceClient) GetTestRun(ctx context.Context, in *GetTestRunRequest, opts ...grpc.CallOption) (*GetTestRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTestRunResponse)
+	err := c.cc.Invoke(ctx, TestRunService_GetTestRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *testRunServiceClient) CloseTestRun(ctx context.Context, in *CloseTestRunRequest, opts ...grpc.CallOption) (*CloseTestRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseTestRunResponse)
+	err := c.cc.Invoke(ctx, TestRunService_CloseTestRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *testRunServiceClient) UploadResults(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UploadResultsRequest, UploadResultsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TestRunService_ServiceDesc.Streams[0], TestRunService_UploadResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadResultsRequest, UploadResultsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TestRunService_UploadResultsClient = grpc.BidiStreamingClient[UploadResultsRequest, UploadResultsResponse]
+
+// TestRunServiceServer is the server API for TestRunService service.
+// All implementations must embed UnimplementedTestRunServiceServer
+// for forward compatibility.
+//
+// TestRunService manages test runs and their results, it mirrors the /api/v1/run routes of the REST API
+type TestRunServiceServer interface {
+	CreateTestRun(context.Context, *CreateTestRunRequest) (*CreateTestRunResponse, error)
+	// GetTestRun returns the run, an upload that was cut off resumes after its last_sequence
+	GetTestRun(context.Context, *GetTestRunRequest) (*GetTestRunResponse, error)
+	CloseTestRun(context.Context, *CloseTestRunRequest) (*CloseTestRunResponse, error)
+	// UploadResults stores the streamed results in an open run. The server answers after every
+	// committed batch and once more with done set when the client closes its side. When the upload
+	// fails the last progress, without done, comes ahead of the error, resume after its
+	// acked_sequence. Sequences must increase through the stream, results whose sequence is already
+	// stored are dropped as duplicates
+	UploadResults(grpc.BidiStreamingServer[UploadResultsRequest, UploadResultsResponse]) error
+	mustEmbedUnimplementedTestRunServiceServer()
+}
+
+// UnimplementedTestRunServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTestRunServiceServer struct{}
+
+func (UnimplementedTestRunServiceServer) CreateTestRun(context.Context, *CreateTestRunRequest) (*CreateTestRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTestRun not implemented")
+}
+func (UnimplementedTestRunServiceServer) GetTestRun(context.Context, *GetTestRunRequest) (*GetTestRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTestRun not implemented")
+}
+func (UnimplementedTestRunServiceServer) CloseTestRun(context.Context, *CloseTestRunRequest) (*CloseTestRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseTestRun not implemented")
+}
+func (UnimplementedTestRunServiceServer) UploadResults(grpc.BidiStreamingServer[UploadResultsRequest, UploadResultsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadResults not implemented")
+}
+func (UnimplementedTestRunServiceServer) mustEmbedUnimplementedTestRunServiceServer() {}
+func (UnimplementedTestRunServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeTestRunServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TestRunServiceServer will
+// result in compilation errors.
+type UnsafeTestRunServiceServer interface {
+	mustEmbedUnimplementedTestRunServiceServer()
+}
+
+func RegisterTestRunServiceServer(s grpc.ServiceRegistrar, srv TestRunServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTestRunServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TestRunService_ServiceDesc, srv)
+}
+
+func _TestRunService_CreateTestRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTestRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestRunServiceServer).CreateTestRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TestRunService_CreateTestRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestRunServiceServer).CreateTestRun(ctx, req.(*CreateTestRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TestRunService_GetTestRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTestRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestRunServiceServer).GetTestRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TestRunService_GetTestRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestRunServiceServer).GetTestRun(ctx, req.(*GetTestRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TestRunService_CloseTestRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseTestRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestRunServiceServer).CloseTestRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TestRunService_CloseTestRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestRunServiceServer).CloseTestRun(ctx, req.(*CloseTestRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TestRunService_UploadResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TestRunServiceServer).UploadResults(&grpc.GenericServerStream[UploadResultsRequest, UploadResultsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TestRunService_UploadResultsServer = grpc.BidiStreamingServer[UploadResultsRequest, UploadResultsResponse]
+
+// TestRunService_ServiceDesc is the grpc.ServiceDesc for TestRunService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TestRunService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "qmsengine.v1.TestRunService",
+	HandlerType: (*TestRunServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTestRun",
+			Handler:    _TestRunService_CreateTestRun_Handler,
+		},
+		{
+			MethodName: "GetTestRun",
+			Handler:    _TestRunService_GetTestRun_Handler,
+		},
+		{
+			MethodName: "CloseTestRun",
+			Handler:    _TestRunService_CloseTestRun_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadResults",
+			Handler:       _TestRunService_UploadResults_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "qmsengine/v1/test_run.proto",
+}
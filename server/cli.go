@@ -0,0 +1,106 @@
+package server
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/project-weekend/qms-engine/internal/config"
+)
+
+const usage = `Usage:
+  qms-engine [serve] [--config file] [--env name]
+  qms-engine config print [--redacted] [--config file] [--env name]
+  qms-engine openapi [--check file]
+  qms-engine import-cases --project id --file path [--format csv|xlsx] [--column field=header]...
+                          [--dry-run] [--config file] [--env name]
+`
+
+// Run dispatches the command line and returns the process exit code
+func Run(args []string) int {
+	command := "serve"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+		opts := configFlags(flags)
+		if err := flags.Parse(args); err != nil {
+			return 2
+		}
+		Serve(*opts)
+		return 0
+	case "config":
+		return runConfig(args, os.Stdout)
+	case "openapi":
+		return runOpenAPI(args, os.Stdout)
+	case "import-cases":
+		return runImportCases(args, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", command, usage)
+		return 2
+	}
+}
+
+func runConfig(args []string, out io.Writer) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	opts := configFlags(flags)
+	redacted := flags.Bool("redacted", false, "mask secrets in the output")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if err := config.PrintConfig(out, *opts, *redacted); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to print config: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runOpenAPI prints the OpenAPI document, or with --check compares it with a committed copy
+// and fails when they differ so that CI catches a spec that was not regenerated
+func runOpenAPI(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	check := flags.String("check", "", "committed document to compare with, e.g. api/openapi.json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	document, err := config.MarshalOpenAPIDocument(config.NewOpenAPIDocument())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate the OpenAPI document: %v\n", err)
+		return 1
+	}
+	if *check == "" {
+		_, _ = out.Write(document)
+		return 0
+	}
+
+	committed, err := os.ReadFile(*check)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", *check, err)
+		return 1
+	}
+	if !bytes.Equal(committed, document) {
+		fmt.Fprintf(os.Stderr, "%s is out of date, regenerate it with: qms-engine openapi > %s\n", *check, *check)
+		return 1
+	}
+	return 0
+}
+
+// configFlags registers the flags selecting the configuration files
+func configFlags(flags *flag.FlagSet) *config.ConfigOptions {
+	opts := &config.ConfigOptions{}
+	flags.StringVar(&opts.File, "config", "", "path of the base config file")
+	flags.StringVar(&opts.Env, "env", "", "environment overlay to merge, e.g. prod for service-config.prod.json")
+	return opts
+}
@@ -1,38 +1,207 @@
 package config
 
-// Config is the main configuration structure for qms-engine usecase
+// Config is the main configuration structure for qms-engine usecase.
+// Fields are checked with their validate tags at startup, omitted fields take their default tag.
+// Fields tagged reload:"true" are applied on SIGHUP or when a config file changes, others need a restart
 type Config struct {
-	Name        string      `json:"name"`
-	ServiceName string      `json:"serviceName"`
-	Env         string      `json:"env"`
-	Host        string      `json:"host"`
-	Port        int         `json:"port"`
-	OwnerInfo   OwnerInfo   `json:"ownerInfo"`
-	Database    Database    `json:"database"`
-	RedisConfig RedisConfig `json:"redisConfig"`
-	Statsd      Statsd      `json:"statsd"`
-	Trace       Trace       `json:"trace"`
-	Logger      Logger      `json:"logger"`
-	Kafka       KafkaConfig `json:"kafka"`
+	Name        string       `json:"name"`
+	ServiceName string       `json:"serviceName" validate:"required"`
+	Env         string       `json:"env" validate:"required"`
+	Host        string       `json:"host"`
+	Port        int          `json:"port" validate:"required,min=1,max=65535"`
+	Server      HTTPServer   `json:"server"`
+	GRPC        GRPC         `json:"grpc"`
+	Auth        AuthConfig   `json:"auth" reload:"true"`
+	CORS        CORSConfig   `json:"cors" reload:"true"`
+	Limits      LimitsConfig `json:"limits"`
+	Idempotency Idempotency  `json:"idempotency"`
+	Contract    Contract     `json:"contract" reload:"true"`
+	QualityGate QualityGate  `json:"qualityGate" reload:"true"`
+	Results     Results      `json:"results"`
+	Events      Events       `json:"events"`
+	Sessions    Sessions     `json:"sessions"`
+	Imports     Imports      `json:"imports"`
+	GraphQL     GraphQL      `json:"graphql"`
+	OwnerInfo   OwnerInfo    `json:"ownerInfo"`
+	Database    Database     `json:"database"`
+	RedisConfig RedisConfig  `json:"redisConfig"`
+	Statsd      Statsd       `json:"statsd"`
+	Trace       Trace        `json:"trace"`
+	Logger      Logger       `json:"logger"`
+	Kafka       KafkaConfig  `json:"kafka"`
+	Health      HealthConfig `json:"health"`
+	// Features are named feature flags, a missing flag is off
+	Features map[string]bool `json:"features" reload:"true"`
+}
+
+// HTTPServer contains HTTP server timeouts and graceful shutdown configuration
+type HTTPServer struct {
+	ReadTimeoutInSec       int `json:"readTimeoutInSec" default:"30" validate:"gte=0"`
+	ReadHeaderTimeoutInSec int `json:"readHeaderTimeoutInSec" default:"5" validate:"gte=0"`
+	WriteTimeoutInSec      int `json:"writeTimeoutInSec" default:"60" validate:"gte=0"`
+	IdleTimeoutInSec       int `json:"idleTimeoutInSec" default:"120" validate:"gte=0"`
+	// DrainDelayInSec is how long the service reports unready before it stops accepting connections
+	DrainDelayInSec int `json:"drainDelayInSec" validate:"gte=0"`
+	// ShutdownTimeoutInSec bounds draining in-flight requests and stopping components
+	ShutdownTimeoutInSec int `json:"shutdownTimeoutInSec" default:"30" validate:"gt=0"`
+	// TrustedProxies are the proxy IPs or CIDRs whose forwarding headers are believed,
+	// with none the client IP is the address of the connection
+	TrustedProxies []string `json:"trustedProxies" validate:"dive,cidr|ip"`
+	// RemoteIPHeaders are the headers a trusted proxy puts the client IP in, in order of preference
+	RemoteIPHeaders []string  `json:"remoteIPHeaders" default:"X-Forwarded-For,X-Real-IP"`
+	TLS             TLSConfig `json:"tls"`
+}
+
+// TLSConfig contains TLS termination configuration, the files are reloaded when they change
+type TLSConfig struct {
+	Enabled  bool   `json:"enabled"`
+	CertFile string `json:"certFile" validate:"required_if=Enabled true"`
+	KeyFile  string `json:"keyFile" validate:"required_if=Enabled true"`
+	// ClientCAFile holds the CAs that sign client certificates, setting it enables mTLS
+	ClientCAFile string `json:"clientCAFile"`
+	// ClientCertPaths are the path prefixes that require a verified client certificate, e.g. CI uploads
+	ClientCertPaths []string `json:"clientCertPaths"`
+	// ClientCertSubjects restricts the accepted client certificates by common name, empty accepts any
+	ClientCertSubjects []string `json:"clientCertSubjects"`
+}
+
+// GRPC contains the gRPC server, it serves the services of the REST API on its own port
+type GRPC struct {
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port" default:"9090" validate:"min=1,max=65535"`
+	// Reflection lets tools like grpcurl list and describe the services
+	Reflection         bool `json:"reflection" default:"false"`
+	MaxRecvMsgSizeInKB int  `json:"maxRecvMsgSizeInKB" default:"4096" validate:"gt=0"`
+	// APIKeys are the keys accepted in the x-api-key metadata, the server does not start without one
+	APIKeys []string `json:"apiKeys" validate:"dive,required"`
+}
+
+// AuthConfig contains the credentials accepted by the REST API
+type AuthConfig struct {
+	// APIKeys are the keys accepted in the X-API-Key header, written name:key to name the caller.
+	// Requests without a key are anonymous, a key that is not listed is refused
+	APIKeys []string `json:"apiKeys" validate:"dive,required"`
+}
+
+// LimitsConfig contains the request rate and size limits of the route groups
+type LimitsConfig struct {
+	// Store keeps the rate limit buckets, redis shares them between instances
+	Store string `json:"store" default:"memory" validate:"oneof=memory redis"`
+	// Groups maps a route group to its limits, group names are lower case.
+	// Routes outside any group use the "default" group
+	Groups map[string]RouteLimits `json:"groups" validate:"dive" reload:"true"`
+}
+
+// RouteLimits contains the limits of a route group, zero disables a limit
+type RouteLimits struct {
+	// RequestsPerMinute is the sustained rate per client, identified by API key, user or IP
+	RequestsPerMinute int `json:"requestsPerMinute" validate:"gte=0"`
+	// Burst is how many requests a client can make at once, by default RequestsPerMinute
+	Burst           int `json:"burst" validate:"gte=0"`
+	MaxBodySizeInKB int `json:"maxBodySizeInKB" validate:"gte=0"`
+}
+
+// Idempotency contains the Idempotency-Key handling of POST and PATCH requests
+type Idempotency struct {
+	Store    string `json:"store" default:"db" validate:"oneof=db redis"`
+	TTLInSec int    `json:"ttlInSec" default:"86400" validate:"gt=0"`
+	// LockTimeoutInSec bounds how long a request holds its key, the key of a crashed request is freed after it
+	LockTimeoutInSec int `json:"lockTimeoutInSec" default:"60" validate:"gt=0"`
+	// WaitTimeoutInSec is how long a duplicate waits for the first request before it gets a 409
+	WaitTimeoutInSec int `json:"waitTimeoutInSec" default:"5" validate:"gte=0"`
+}
+
+// Contract turns on checking API traffic against the OpenAPI document, meant for integration testing
+type Contract struct {
+	// ValidateRequests refuses requests breaking the document with BAD_REQUEST
+	ValidateRequests bool `json:"validateRequests"`
+	// ValidateResponses replaces responses breaking the document with an INTERNAL_SERVER_ERROR
+	// naming the violations, it is refused in production
+	ValidateResponses bool `json:"validateResponses"`
+}
+
+// QualityGate holds the thresholds a run is evaluated against when the request leaves them out
+type QualityGate struct {
+	// MinPassRate is the lowest percentage of passed results, skipped results left out
+	MinPassRate float64 `json:"minPassRate" validate:"gte=0,lte=100"`
+	// MaxFailed bounds the failed and blocked results, it is unbounded when unset
+	MaxFailed *int `json:"maxFailed" validate:"omitempty,gte=0"`
+}
+
+// Results contains the streaming upload of test results
+type Results struct {
+	// BatchSize is how many uploaded results are inserted in one transaction and acknowledged together
+	BatchSize int `json:"batchSize" default:"500" validate:"gt=0,lte=5000"`
+}
+
+// Events contains the streams of test run events
+type Events struct {
+	// Broker spreads events between instances, with memory a subscriber only gets the events of its instance
+	Broker string `json:"broker" default:"memory" validate:"oneof=memory redis"`
+	// HistorySize is how many recent events of each run are kept for subscribers that reconnect
+	HistorySize int `json:"historySize" default:"1000" validate:"gt=0,lte=100000"`
+	// BufferSize is how many events a subscriber may fall behind before it is disconnected
+	BufferSize int `json:"bufferSize" default:"256" validate:"gt=0"`
+}
+
+// Sessions contains the WebSocket sessions in which testers execute the cases of a run together.
+// They share changes through the broker of Events
+type Sessions struct {
+	// MaxTestersPerRun bounds the sessions of a run on one instance
+	MaxTestersPerRun int `json:"maxTestersPerRun" default:"50" validate:"gt=0"`
+	// MaxMessageSizeInKB bounds the messages testers send
+	MaxMessageSizeInKB int64 `json:"maxMessageSizeInKB" default:"64" validate:"gt=0,lte=1024"`
+}
+
+// Imports contains the import of test cases from spreadsheets and of the exports of other tools
+type Imports struct {
+	// BatchSize is how many imported cases are written in one transaction, or in one statement by
+	// the imports of exports, which are one transaction
+	BatchSize int `json:"batchSize" default:"500" validate:"gt=0,lte=5000"`
+	// MaxRows bounds the rows of an imported sheet and the cases and results of an imported export,
+	// they are all held in memory
+	MaxRows int `json:"maxRows" default:"50000" validate:"gt=0,lte=1000000"`
+}
+
+// GraphQL contains the /graphql endpoint serving nested reads for dashboards
+type GraphQL struct {
+	Enabled bool `json:"enabled"`
+	// MaxDepth is how deeply the fields of a query may nest
+	MaxDepth int `json:"maxDepth" default:"8" validate:"gt=0"`
+	// MaxComplexity bounds the fields a query can resolve, list fields count their children per item
+	MaxComplexity int `json:"maxComplexity" default:"10000" validate:"gt=0"`
+	// Introspection lets tools read the schema
+	Introspection bool `json:"introspection" default:"true"`
+}
+
+// CORSConfig contains the cross-origin policy, without origins cross-origin requests are refused
+type CORSConfig struct {
+	// AllowOrigins are full origins like https://qms.example.com, "*" allows any origin without credentials
+	AllowOrigins     []string `json:"allowOrigins"`
+	AllowMethods     []string `json:"allowMethods" default:"GET,POST,PUT,PATCH,DELETE,OPTIONS"`
+	AllowHeaders     []string `json:"allowHeaders" default:"Origin,Content-Type,Accept,Authorization,X-Request-ID"`
+	ExposeHeaders    []string `json:"exposeHeaders" default:"Content-Length"`
+	AllowCredentials bool     `json:"allowCredentials"`
+	MaxAgeInSec      int      `json:"maxAgeInSec" default:"43200" validate:"gte=0"`
 }
 
 // OwnerInfo contains information about the usecase owner
 type OwnerInfo struct {
 	Name  string `json:"name"`
-	Email string `json:"email"`
+	Email string `json:"email" validate:"omitempty,email"`
 	URL   string `json:"url"`
 }
 
 type Database struct {
-	Username string `json:"username"`
+	Username string `json:"username" validate:"required"`
 	Password string `json:"password"`
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Name     string `json:"name"`
+	Host     string `json:"host" validate:"required"`
+	Port     int    `json:"port" default:"3306" validate:"min=1,max=65535"`
+	Name     string `json:"name" validate:"required"`
 	Pool     struct {
-		Idle     int `json:"idle"`
-		Max      int `json:"max"`
-		Lifetime int `json:"lifetime"`
+		Idle     int `json:"idle" default:"10" validate:"gte=0,ltefield=Max"`
+		Max      int `json:"max" default:"100" validate:"gt=0"`
+		Lifetime int `json:"lifetime" default:"300" validate:"gte=0"`
 	} `json:"pool"`
 }
 
@@ -45,41 +214,70 @@ type CircuitBreaker struct {
 
 // RedisConfig contains Redis configuration
 type RedisConfig struct {
-	Addr               string `json:"addr"`
-	IdleTimeoutInSec   int    `json:"idleTimeoutInSec"`
-	PoolSize           int    `json:"poolSize"`
+	Addr               string `json:"addr" validate:"omitempty,hostname_port"`
+	IdleTimeoutInSec   int    `json:"idleTimeoutInSec" validate:"gte=0"`
+	PoolSize           int    `json:"poolSize" validate:"gte=0"`
 	ReadOnlyFromSlaves bool   `json:"readOnlyFromSlaves"`
-	ReadTimeoutInSec   int    `json:"readTimeoutInSec"`
-	WriteTimeoutInSec  int    `json:"writeTimeoutInSec"`
+	ReadTimeoutInSec   int    `json:"readTimeoutInSec" validate:"gte=0"`
+	WriteTimeoutInSec  int    `json:"writeTimeoutInSec" validate:"gte=0"`
 	TLSEnabled         bool   `json:"tlsEnabled"`
 }
 
 // Statsd contains StatsD configuration
 type Statsd struct {
 	Host string `json:"host"`
-	Port int    `json:"port"`
+	Port int    `json:"port" validate:"omitempty,min=1,max=65535"`
 }
 
 // Trace contains tracing configuration
 type Trace struct {
 	Host    string `json:"host"`
-	Port    int    `json:"port"`
+	Port    int    `json:"port" validate:"omitempty,min=1,max=65535"`
 	Disable bool   `json:"disable"`
 }
 
 // Logger contains logging configuration
 type Logger struct {
-	WorkerCount     int    `json:"workerCount"`
-	BufferSize      int    `json:"bufferSize"`
-	LogLevel        int    `json:"logLevel"`
-	StacktraceLevel int    `json:"stacktraceLevel"`
-	LogFormat       string `json:"logFormat"`
+	BufferSize     int    `json:"bufferSize" default:"1024" validate:"gte=1"`
+	OverflowPolicy string `json:"overflowPolicy" default:"drop" validate:"oneof=drop block"`
+	LogLevel       int    `json:"logLevel" default:"6" validate:"gte=0" reload:"true"`
+	// StacktraceLevel is on the scale of LogLevel, 9 and above are errors. 0 disables stack traces
+	StacktraceLevel int         `json:"stacktraceLevel" validate:"gte=0"`
+	LogFormat       string      `json:"logFormat" default:"text" validate:"oneof=text json logfmt cgls cglsdebug" reload:"true"`
+	Output          string      `json:"output" default:"stdout" validate:"oneof=stdout file both"`
+	File            LogFile     `json:"file"`
+	RedactKeys      []string    `json:"redactKeys"`
+	Sampling        LogSampling `json:"sampling"`
+}
+
+// LogFile contains the rotating log file configuration
+type LogFile struct {
+	Path          string `json:"path"`
+	MaxSizeInMB   int    `json:"maxSizeInMB" validate:"gte=0"`
+	MaxAgeInHours int    `json:"maxAgeInHours" validate:"gte=0"`
+	MaxBackups    int    `json:"maxBackups" validate:"gte=0"`
+	Compress      bool   `json:"compress"`
+}
+
+// LogSampling contains per-message log sampling configuration, a zero First disables sampling
+type LogSampling struct {
+	WindowInMs int `json:"windowInMs" validate:"gte=0"`
+	First      int `json:"first" validate:"gte=0"`
+	Thereafter int `json:"thereafter" validate:"gte=0"`
 }
 
 // KafkaConfig contains Kafka configuration
 type KafkaConfig struct {
-	BootstrapServers string `json:"bootstrap.servers"`
-	GroupID          string `json:"group.id"`
-	AutoOffsetReset  string `json:"auto.offset.reset"`
-	ProducerEnabled  bool   `json:"producer.enabled"`
+	BootstrapServers string `json:"bootstrapServers" validate:"required_if=ProducerEnabled true"`
+	GroupID          string `json:"groupId"`
+	AutoOffsetReset  string `json:"autoOffsetReset" validate:"omitempty,oneof=earliest latest none"`
+	ProducerEnabled  bool   `json:"producerEnabled"`
+}
+
+// HealthConfig contains readiness check configuration
+type HealthConfig struct {
+	TimeoutInMs  int `json:"timeoutInMs" default:"2000" validate:"gt=0"`
+	CacheTTLInMs int `json:"cacheTTLInMs" default:"1000" validate:"gte=0"`
+	// Critical lists the components whose failure makes the service unready
+	Critical []string `json:"critical" default:"database,migrations" validate:"dive,oneof=database migrations redis kafka"`
 }
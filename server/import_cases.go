@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/project-weekend/qms-engine/internal/common"
+	"github.com/project-weekend/qms-engine/internal/config"
+	"github.com/project-weekend/qms-engine/internal/model"
+	"github.com/project-weekend/qms-engine/internal/normalize"
+)
+
+// runImportCases imports the test cases of a spreadsheet straight into the database of the
+// configuration and prints the report. It exits with 1 when the import fails or a dry run finds
+// invalid rows
+func runImportCases(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("import-cases", flag.ContinueOnError)
+	opts := configFlags(flags)
+	projectID := flags.Int("project", 0, "ID of the project to import into")
+	file := flags.String("file", "", "CSV file or XLSX workbook holding the cases")
+	request := importFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *projectID <= 0 || *file == "" {
+		fmt.Fprint(os.Stderr, "--project and --file are required\n", usage)
+		return 2
+	}
+	if request.Format == "" {
+		request.Format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+
+	validate := config.NewValidator()
+	trans, err := checkImportRequest(validate, request)
+	if err != nil {
+		return printImportError(err)
+	}
+
+	appConfig, err := config.LoadConfig(*opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	sheet, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer sheet.Close()
+
+	// Logs go to stderr, stdout is for the report
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	db := config.NewDatabase(appConfig, logger)
+	defer db.Close()
+
+	service := config.NewTestCaseService(appConfig, db, validate, logger)
+	report, err := service.ImportCases(context.Background(), *projectID, request, sheet, trans)
+	if err != nil {
+		return printImportError(err)
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(report)
+	if report.Invalid > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkImportRequest normalizes and validates the request like the transports do. It returns the
+// translator of the messages of the report
+func checkImportRequest(validate *validator.Validate, request *model.ImportCasesRequest) (ut.Translator, error) {
+	translator, err := config.NewTranslator(validate)
+	if err != nil {
+		return nil, err
+	}
+	trans, _ := translator.GetTranslator(common.LocaleEnglish)
+	if err = normalize.Struct(request); err != nil {
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, nil)
+	}
+	if err = validate.Struct(request); err != nil {
+		return nil, common.NewServiceError(common.ErrCode_BadRequest, common.ParseValidationErrors(err, trans))
+	}
+	return trans, nil
+}
+
+// importFlags registers the flags of the import request
+func importFlags(flags *flag.FlagSet) *model.ImportCasesRequest {
+	request := &model.ImportCasesRequest{Columns: map[string]string{}}
+	flags.StringVar(&request.Format, "format", "", "csv or xlsx, by default the extension of the file")
+	flags.BoolVar(&request.DryRun, "dry-run", false, "report the invalid rows and what applying would do, writing nothing")
+	flags.Func("column", "field=header, the header of the column holding a case field, repeatable",
+		func(value string) error {
+			field, header, ok := strings.Cut(value, "=")
+			if !ok {
+				return fmt.Errorf("%q is not field=header", value)
+			}
+			request.Columns[field] = header
+			return nil
+		})
+	return request
+}
+
+// printImportError prints the service error of a failed import with its row errors
+func printImportError(err error) int {
+	encoder := json.NewEncoder(os.Stderr)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(common.AsServiceError(err))
+	return 1
+}
@@ -1,31 +1,148 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/project-weekend/qms-engine/internal/config"
+	serverconfig "github.com/project-weekend/qms-engine/server/config"
 )
 
-func Serve() {
-	appConfig := config.LoadConfig()
-	logger := config.NewLogger(appConfig)
+// logFlushTimeout bounds the final flush of the logs on shutdown
+const logFlushTimeout = 5 * time.Second
+
+// Serve starts the HTTP server and blocks until it is shut down, then exits the process
+func Serve(opts config.ConfigOptions) {
+	appConfig, err := config.LoadConfig(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	httpServer, lifecycle, logger := setup(opts, appConfig)
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	if listener, err := net.Listen("tcp", httpServer.Addr); err != nil {
+		serveErr <- err
+	} else {
+		go func() {
+			logger.Info(fmt.Sprintf("Starting HTTP server on %s", httpServer.Addr), "tls", httpServer.TLSConfig != nil)
+			serveErr <- config.Serve(httpServer, listener)
+		}()
+		// Ready only once the port accepts connections
+		lifecycle.SetReady(true)
+	}
+
+	exitCode := 0
+	drainDelayInSec := appConfig.Server.DrainDelayInSec
+	select {
+	case <-signalCtx.Done():
+		logger.Info("Shutdown signal received, draining")
+	case err := <-serveErr:
+		logger.Error("HTTP server stopped", "error", fmt.Errorf("failed to start http server: %w", err))
+		exitCode = 1
+		// Nothing was served, there is no traffic to drain
+		drainDelayInSec = 0
+	}
+	stop()
+
+	if err := shutdown(drainDelayInSec, appConfig.Server.ShutdownTimeoutInSec,
+		httpServer, lifecycle, logger); err != nil {
+		exitCode = 1
+	}
+	os.Exit(exitCode)
+}
+
+// setup wires the components and returns the server to start, exiting when one cannot be built
+func setup(opts config.ConfigOptions, appConfig *serverconfig.Config) (*http.Server, *config.Lifecycle,
+	*slog.Logger) {
+	logger, logControl := config.NewLogger(appConfig)
+	lifecycle := config.NewLifecycle(logger)
+	liveConfig := config.NewLiveConfig(appConfig)
+	liveConfig.Subscribe("logger", func(conf *serverconfig.Config) error {
+		return logControl.Apply(conf.Logger)
+	})
 	db := config.NewDatabase(appConfig, logger)
+	lifecycle.Go("config-reloader", config.NewConfigReloader(opts, liveConfig, db, logger).Run)
+	lifecycle.OnShutdown("database", func(context.Context) error {
+		return db.Close()
+	})
 	validator := config.NewValidator()
-	appEngine := config.NewGinEngine(appConfig, logger)
-
-	config.Bootstrap(&config.AppBootstrap{
-		Config:    appConfig,
-		Logger:    logger,
-		DB:        db,
-		Validate:  validator,
-		AppEngine: appEngine,
+	translator, err := config.NewTranslator(validator)
+	exitOnError(logger, "Failed to register validation translations", err)
+	appEngine := config.NewGinEngine(appConfig, liveConfig, logger)
+	appEngine.Use(config.LocaleMiddleware(translator))
+	config.RegisterHealthRoutes(appEngine, lifecycle, config.NewHealthRegistry(appConfig, db, logger))
+	grpcServer := config.NewGRPCServer(appConfig, logger)
+
+	err = config.Bootstrap(&config.AppBootstrap{
+		Config:     appConfig,
+		Logger:     logger,
+		DB:         db,
+		Validate:   validator,
+		AppEngine:  appEngine,
+		Lifecycle:  lifecycle,
+		LiveConfig: liveConfig,
+		GRPCServer: grpcServer,
 	})
+	exitOnError(logger, "Failed to bootstrap the application", err)
+	exitOnError(logger, "Failed to serve the OpenAPI document", config.RegisterOpenAPIRoutes(appEngine))
+
+	httpServer, err := config.NewHTTPServer(appConfig, appEngine, lifecycle, logger)
+	exitOnError(logger, "Failed to configure HTTP server", err)
+	if grpcServer != nil {
+		exitOnError(logger, "Failed to start gRPC server", config.ServeGRPC(appConfig, grpcServer, lifecycle, logger))
+	}
+	return httpServer, lifecycle, logger
+}
+
+// exitOnError logs err and exits when it is set, for components the service cannot run without
+func exitOnError(logger *slog.Logger, message string, err error) {
+	if err == nil {
+		return
+	}
+	logger.Error(message, "error", err)
+	_ = config.FlushLogger(context.Background(), logger)
+	os.Exit(1)
+}
+
+// shutdown flips the service to unready, waits for load balancers to notice, drains
+// in-flight requests, stops the components and finally flushes the logs
+func shutdown(drainDelayInSec, timeoutInSec int, httpServer *http.Server, lifecycle *config.Lifecycle,
+	logger *slog.Logger) error {
+	lifecycle.SetReady(false)
+	httpServer.SetKeepAlivesEnabled(false)
+	time.Sleep(time.Duration(drainDelayInSec) * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutInSec)*time.Second)
+	defer cancel()
 
-	addr := fmt.Sprintf("%s:%d", appConfig.Host, appConfig.Port)
-	logger.Info(fmt.Sprintf("Starting HTTP server on %s", addr))
+	var errs []error
+	if err := httpServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Failed to drain HTTP server", "error", err)
+		errs = append(errs, err)
+	}
+	if err := lifecycle.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
 
-	if err := appEngine.Run(addr); err != nil {
-		log.Fatal(fmt.Errorf("failed to start http server: %w", err))
+	if len(errs) == 0 {
+		logger.Info("Shutdown complete")
+	}
+	// The flush gets its own time, a slow drain must not cost the last log lines
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), logFlushTimeout)
+	defer cancelFlush()
+	if err := config.FlushLogger(flushCtx, logger); err != nil {
+		errs = append(errs, err)
 	}
+	return errors.Join(errs...)
 }